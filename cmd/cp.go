@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/spf13/cobra"
+)
+
+func newCopyCmd(ct container.ContainerTasks) *cobra.Command {
+	var node string
+	var force bool
+
+	copyCmd := &cobra.Command{
+		Use:   "cp [source] [destination]",
+		Short: "Copy files and directories between a resource and the local filesystem",
+		Long: `Copy files and directories between a resource and the local filesystem.
+
+The path in the resource is specified as [resource]:[path], e.g. resource.container.api:/etc/nginx.
+When copying from a resource and the local destination is an existing directory the
+file or directory is copied into it. An existing local file or directory with the same
+name is not replaced unless --force is set, the existing directory is removed and not
+merged. When copying to a resource the destination must be an existing directory in the
+container.`,
+		Example: `
+  # Copy a directory from a container
+  jumppad cp resource.container.api:/etc/nginx ./nginx
+
+  # Copy a file to a container
+  jumppad cp ./default.conf resource.container.api:/etc/nginx/conf.d
+
+  # Copy a file from the first client node of a Nomad cluster
+  jumppad cp --node 1.client resource.nomad_cluster.dev:/etc/nomad.d/config.hcl .
+	`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			srcResource, src, srcRemote, err := parseCopyPath(args[0])
+			if err != nil {
+				return err
+			}
+
+			dstResource, dst, dstRemote, err := parseCopyPath(args[1])
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case srcRemote && dstRemote:
+				return fmt.Errorf("copying between resources is not supported, either the source or destination must be a local path")
+			case !srcRemote && !dstRemote:
+				return fmt.Errorf("either the source or destination must be a resource path in the format [resource]:[path]")
+			case srcRemote:
+				return copyFromResource(ct, srcResource, node, src, dst, force)
+			}
+
+			return copyToResource(ct, dstResource, node, src, dst)
+		},
+	}
+
+	copyCmd.Flags().StringVar(&node, "node", "", "Node of a cluster resource to copy from or to, e.g. server or 1.client, defaults to the server")
+	copyCmd.Flags().BoolVar(&force, "force", false, "Replace an existing local file or directory when copying from a resource")
+
+	return copyCmd
+}
+
+// parseCopyPath splits a path in the format [resource]:[path], remote is
+// false when the path is a local path
+func parseCopyPath(p string) (resource, filePath string, remote bool, err error) {
+	r, fp, ok := strings.Cut(p, ":")
+	if !ok || (!strings.HasPrefix(r, "resource.") && !strings.HasPrefix(r, "module.")) {
+		return "", p, false, nil
+	}
+
+	if !path.IsAbs(fp) {
+		return "", "", false, fmt.Errorf("path '%s' in resource %s must be absolute", fp, r)
+	}
+
+	return r, fp, true, nil
+}
+
+func copyFromResource(ct container.ContainerTasks, resource, node, src, dst string, force bool) error {
+	id, err := findContainerForResource(ct, resource, node)
+	if err != nil {
+		return err
+	}
+
+	// copy into existing directories rather than replacing them
+	if fi, err := os.Stat(dst); err == nil && fi.IsDir() {
+		dst = filepath.Join(dst, path.Base(src))
+	}
+
+	// CopyFromContainer removes the destination before copying
+	if _, err := os.Stat(dst); err == nil && !force {
+		return fmt.Errorf("'%s' already exists, use --force to replace it", dst)
+	}
+
+	return ct.CopyFromContainer(id, src, dst)
+}
+
+func copyToResource(ct container.ContainerTasks, resource, node, src, dst string) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("unable to copy '%s': %s", src, err)
+	}
+
+	id, err := findContainerForResource(ct, resource, node)
+	if err != nil {
+		return err
+	}
+
+	if fi.IsDir() {
+		return ct.CopyDirectoryToContainer(id, src, dst)
+	}
+
+	return ct.CopyFileToContainer(id, src, dst)
+}
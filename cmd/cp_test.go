@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmock "github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCopy(t *testing.T) (*cmock.ContainerTasks, string) {
+	ct := setupExec(t)
+	ct.On("CopyFromContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	ct.On("CopyFileToContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	ct.On("CopyDirectoryToContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "config"), os.ModePerm)
+	os.WriteFile(filepath.Join(dir, "config", "app.conf"), []byte("test"), os.ModePerm)
+
+	return ct, dir
+}
+
+// replaceOnCopy makes CopyFromContainer remove the destination and copy a
+// single file in the same way as DockerTasks
+func replaceOnCopy(ct *cmock.ContainerTasks) {
+	testutils.RemoveOn(&ct.Mock, "CopyFromContainer")
+	ct.On("CopyFromContainer", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		dst := args.String(2)
+		os.RemoveAll(dst)
+		os.MkdirAll(dst, os.ModePerm)
+		os.WriteFile(filepath.Join(dst, "copied.conf"), []byte("copied"), os.ModePerm)
+	}).Return(nil)
+}
+
+func TestCopyFromResourceCopiesToPath(t *testing.T) {
+	ct, dir := setupCopy(t)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{"resource.container.api:/etc/nginx", filepath.Join(dir, "nginx")})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "FindContainerIDs", "api.container.local.jmpd.in")
+	ct.AssertCalled(t, "CopyFromContainer", "abc123", "/etc/nginx", filepath.Join(dir, "nginx"))
+}
+
+func TestCopyFromResourceCopiesIntoExistingDirectory(t *testing.T) {
+	ct, dir := setupCopy(t)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{"resource.container.api:/etc/nginx/nginx.conf", dir})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "CopyFromContainer", "abc123", "/etc/nginx/nginx.conf", filepath.Join(dir, "nginx.conf"))
+}
+
+func TestCopyFromResourceDoesNotReplaceExistingFiles(t *testing.T) {
+	ct, dir := setupCopy(t)
+	replaceOnCopy(ct)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{"resource.container.api:/etc/config", dir})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "use --force to replace it")
+
+	d, err := os.ReadFile(filepath.Join(dir, "config", "app.conf"))
+	require.NoError(t, err)
+	require.Equal(t, "test", string(d))
+
+	ct.AssertNotCalled(t, "CopyFromContainer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCopyFromResourceWithForceReplacesExistingFiles(t *testing.T) {
+	ct, dir := setupCopy(t)
+	replaceOnCopy(ct)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{"--force", "resource.container.api:/etc/config", dir})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	require.NoFileExists(t, filepath.Join(dir, "config", "app.conf"))
+	require.FileExists(t, filepath.Join(dir, "config", "copied.conf"))
+}
+
+func TestCopyFromClusterNode(t *testing.T) {
+	ct, dir := setupCopy(t)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{"--node", "1.client", "resource.nomad_cluster.dev:/etc/nomad.d/config.hcl", dir})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "FindContainerIDs", "1.client.dev.nomad-cluster.local.jmpd.in")
+}
+
+func TestCopyToResourceCopiesFile(t *testing.T) {
+	ct, dir := setupCopy(t)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{filepath.Join(dir, "config", "app.conf"), "resource.container.api:/etc"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "CopyFileToContainer", "abc123", filepath.Join(dir, "config", "app.conf"), "/etc")
+}
+
+func TestCopyToResourceCopiesDirectory(t *testing.T) {
+	ct, dir := setupCopy(t)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{filepath.Join(dir, "config"), "resource.container.api:/etc"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "CopyDirectoryToContainer", "abc123", filepath.Join(dir, "config"), "/etc")
+}
+
+func TestCopyToResourceWithMissingSourceReturnsError(t *testing.T) {
+	ct, dir := setupCopy(t)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{filepath.Join(dir, "missing"), "resource.container.api:/etc"})
+
+	err := c.Execute()
+	require.Error(t, err)
+	ct.AssertNotCalled(t, "FindContainerIDs", mock.Anything)
+}
+
+func TestCopyWithoutResourceReturnsError(t *testing.T) {
+	ct, dir := setupCopy(t)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{filepath.Join(dir, "config"), filepath.Join(dir, "other")})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "must be a resource path")
+}
+
+func TestCopyBetweenResourcesReturnsError(t *testing.T) {
+	ct, _ := setupCopy(t)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{"resource.container.api:/etc", "resource.nomad_cluster.dev:/etc"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "copying between resources is not supported")
+}
+
+func TestCopyWithRelativeResourcePathReturnsError(t *testing.T) {
+	ct, dir := setupCopy(t)
+
+	c := newCopyCmd(ct)
+	c.SetArgs([]string{"resource.container.api:etc/nginx", dir})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "must be absolute")
+}
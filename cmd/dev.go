@@ -58,8 +58,10 @@ func newDevCmdFunc(variables *[]string, variablesFile, interval *string, ttyFlag
 			}
 		}
 
-		engineClients, _ := clients.GenerateClients(v.Logger())
-		engine, err := createEngine(v.Logger(), engineClients)
+		pool := clients.NewPool(v.Logger())
+		engineClients, _ := pool.Clients()
+
+		engine, err := createEngine(v.Logger(), pool)
 		if err != nil {
 			return fmt.Errorf("unable to create engine: %s", err)
 		}
@@ -8,8 +8,8 @@ import (
 	"syscall"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients"
-	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/connector"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/spf13/cobra"
@@ -17,17 +17,26 @@ import (
 
 func newDestroyCmd(cc connector.Connector, l logger.Logger) *cobra.Command {
 	var force bool
+	var targets []string
+	var output string
 
 	downCmd := &cobra.Command{
-		Use:     "down",
-		Short:   "Remove all resources in the current state",
-		Long:    "Remove all resources in the current state",
-		Example: `jumppad down`,
+		Use:   "down",
+		Short: "Remove all resources in the current state",
+		Long:  "Remove all resources in the current state",
+		Example: `
+  # Remove all resources
+  jumppad down
+
+  # Remove a single container and the resources that depend on it
+  jumppad down --target resource.container.api
+	`,
 		Run: func(cmd *cobra.Command, args []string) {
-			engineClients, _ := clients.GenerateClients(l)
+			pool := clients.DefaultPool(l)
+			engineClients, _ := pool.Clients()
 			engineClients.ContainerTasks.SetForce(force)
 
-			engine, err := createEngine(l, engineClients)
+			engine, err := createEngine(l, pool)
 			if err != nil {
 				l.Error("Unable to create engine", "error", err)
 				return
@@ -35,6 +44,13 @@ func newDestroyCmd(cc connector.Connector, l logger.Logger) *cobra.Command {
 
 			logger := createLogger()
 
+			// render the engine events in the selected format
+			stopOutput, err := subscribeOutput(engine.Events(), l, output, cmd.OutOrStdout())
+			if err != nil {
+				l.Error("Unable to render output", "error", err)
+				return
+			}
+
 			done := make(chan os.Signal, 1)
 			signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
 
@@ -53,7 +69,22 @@ func newDestroyCmd(cc connector.Connector, l logger.Logger) *cobra.Command {
 				cancel()
 			}()
 
+			// when destroying targets the rest of the environment is still
+			// running, do not clean up the data folders or stop the connector
+			if len(targets) > 0 {
+				err = engine.DestroyTargets(ctx, targets, force)
+				stopOutput()
+
+				if err != nil {
+					l.Error("Unable to destroy resources", "error", err)
+				}
+
+				return
+			}
+
 			err = engine.Destroy(ctx, force)
+			stopOutput()
+
 			if err != nil {
 				l.Error("Unable to destroy stack", "error", err)
 				return
@@ -64,13 +95,19 @@ func newDestroyCmd(cc connector.Connector, l logger.Logger) *cobra.Command {
 			// using a UID that does not belong to the host user, so host side
 			// removal will fail with EPERM. Run a short lived alpine container
 			// to remove the folder as root.
-			dataPath := filepath.Join(utils.JumppadHome(), "data")
+			dataPath := filepath.Join(utils.WorkspaceDir(utils.CurrentWorkspace()), "data")
 			if _, err := os.Stat(dataPath); err == nil {
 				if cerr := container.CleanupHostPath(engineClients.ContainerTasks, logger, dataPath); cerr != nil {
 					logger.Error("Unable to clean data folder", "path", dataPath, "error", cerr)
 				}
 			}
 
+			// the library and temp folders and the connector are shared by all
+			// workspaces, leave them when other workspaces still have resources
+			if hasOtherWorkspaceState() {
+				return
+			}
+
 			if err := os.RemoveAll(utils.LibraryFolder("", os.ModePerm)); err != nil {
 				logger.Error("Unable to remove library folder", "error", err)
 			}
@@ -89,6 +126,8 @@ func newDestroyCmd(cc connector.Connector, l logger.Logger) *cobra.Command {
 	}
 
 	downCmd.Flags().BoolVarP(&force, "force", "", false, "When set to true Jumppad will not wait for containers to exit gracefully and will ignore errors")
+	downCmd.Flags().StringVarP(&output, "output", "", outputText, "Output format for progress, text writes log output, tree renders a live progress tree, jsonl streams events as JSON lines")
+	downCmd.Flags().StringSliceVarP(&targets, "target", "", nil, "Only remove the given resource and the resources that depend on it, e.g. --target resource.container.api. Can be specified multiple times")
 
 	return downCmd
 }
@@ -3,7 +3,6 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"runtime"
 	"strings"
 
@@ -44,8 +43,7 @@ func newEnvCmd() *cobra.Command {
 			// load the stack
 			c, err := config.LoadState()
 			if err != nil {
-				cmd.Println("Error: Unable to load state, ", err)
-				os.Exit(1)
+				return fmt.Errorf("unable to load state: %s", err)
 			}
 
 			prefix := "export "
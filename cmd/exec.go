@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// execFlags are the flags shared by the exec and shell commands
+type execFlags struct {
+	user    string
+	workdir string
+	env     []string
+	node    string
+}
+
+func (f *execFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.user, "user", "u", "", "User to run the command as, in the format user or user:group")
+	cmd.Flags().StringVarP(&f.workdir, "workdir", "w", "", "Working directory for the command inside the container")
+	cmd.Flags().StringArrayVarP(&f.env, "env", "e", nil, "Environment variable to set in the format KEY=VALUE, can be specified multiple times")
+	cmd.Flags().StringVar(&f.node, "node", "", "Node of a cluster resource to run the command on, e.g. server or 1.client, defaults to the server")
+}
+
+func (f *execFlags) validate() error {
+	for _, e := range f.env {
+		if !strings.Contains(e, "=") || strings.HasPrefix(e, "=") {
+			return fmt.Errorf("invalid environment variable '%s', must be in the format KEY=VALUE", e)
+		}
+	}
+
+	return nil
+}
+
+func newExecCmd(ct container.ContainerTasks, stdout io.Writer) *cobra.Command {
+	flags := &execFlags{}
+	var timeout time.Duration
+
+	execCmd := &cobra.Command{
+		Use:   "exec [resource] -- [command]",
+		Short: "Execute a command in a running container or cluster node",
+		Long: `Execute a command in a running container or cluster node, the command is not
+interactive and the exit code of the command is returned by jumppad`,
+		Example: `
+  # Run a command in a container
+  jumppad exec resource.container.api -- ls -las /etc
+
+  # Run a command as a specific user with an environment variable
+  jumppad exec --user root --env DEBUG=true resource.container.api -- env
+
+  # Run a command on the first client node of a Nomad cluster
+  jumppad exec --node 1.client resource.nomad_cluster.dev -- nomad node status
+	`,
+		Args:         cobra.MinimumNArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := flags.validate()
+			if err != nil {
+				return err
+			}
+
+			// the timeout for ExecuteCommand is in seconds
+			if timeout < 0 || (timeout > 0 && timeout < time.Second) {
+				return fmt.Errorf("invalid timeout %s, must be at least 1s", timeout)
+			}
+
+			seconds := int(math.Ceil(timeout.Seconds()))
+			if timeout == 0 {
+				seconds = -1
+			}
+
+			id, err := findContainerForResource(ct, args[0], flags.node)
+			if err != nil {
+				return err
+			}
+
+			_, err = ct.ExecuteCommand(id, args[1:], flags.env, flags.workdir, flags.user, "", seconds, stdout)
+
+			return execError(err)
+		},
+	}
+
+	flags.register(execCmd)
+	execCmd.Flags().DurationVar(&timeout, "timeout", 0, "Maximum time to wait for the command to complete, e.g. 30s, defaults to no timeout")
+
+	return execCmd
+}
+
+func newShellCmd(ct container.ContainerTasks, stdin io.ReadCloser, stdout, stderr io.Writer) *cobra.Command {
+	flags := &execFlags{}
+
+	shellCmd := &cobra.Command{
+		Use:   "shell [resource] -- [command]",
+		Short: "Open an interactive shell in a running container or cluster node",
+		Long: `Open an interactive shell in a running container or cluster node, the shell
+defaults to sh and can be changed by adding a command after --`,
+		Example: `
+  # Open a shell in a container
+  jumppad shell resource.container.api
+
+  # Open a bash shell in the Kubernetes server node
+  jumppad shell resource.k8s_cluster.k3s -- bash
+
+  # Open a shell on the first client node of a Nomad cluster
+  jumppad shell --node 1.client resource.nomad_cluster.dev
+	`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := flags.validate()
+			if err != nil {
+				return err
+			}
+
+			id, err := findContainerForResource(ct, args[0], flags.node)
+			if err != nil {
+				return err
+			}
+
+			command := []string{"sh"}
+			if len(args) > 1 {
+				command = args[1:]
+			}
+
+			err = ct.CreateShell(id, command, flags.env, flags.workdir, flags.user, stdin, stdout, stderr)
+
+			return execError(err)
+		},
+	}
+
+	flags.register(shellCmd)
+
+	return shellCmd
+}
+
+// findContainerForResource returns the id of the running container for the
+// resource in the state, for cluster resources node selects the node and
+// defaults to the server
+func findContainerForResource(ct container.ContainerTasks, resource, node string) (string, error) {
+	cfg, err := config.LoadState()
+	if err != nil {
+		return "", fmt.Errorf("unable to read state file: %w", err)
+	}
+
+	r, err := cfg.FindResource(resource)
+	if err != nil {
+		return "", fmt.Errorf("%s not found: %s", resource, err)
+	}
+
+	fqdns := getFQDNForResource(r)
+	if len(fqdns) == 0 {
+		return "", fmt.Errorf("%s does not have a container, only container, sidecar, k8s_cluster and nomad_cluster resources are supported", resource)
+	}
+
+	fqdn := fqdns[0]
+
+	if node != "" {
+		base := utils.FQDN(r.Metadata().Name, r.Metadata().Module, r.Metadata().Type)
+		nodes := []string{}
+		fqdn = ""
+
+		for _, f := range fqdns {
+			n := strings.TrimSuffix(f, "."+base)
+			if n == f {
+				continue
+			}
+
+			if n == node {
+				fqdn = f
+			}
+
+			nodes = append(nodes, n)
+		}
+
+		if fqdn == "" {
+			if len(nodes) == 0 {
+				return "", fmt.Errorf("%s is not a cluster, the node flag can only be used with k8s_cluster and nomad_cluster resources", resource)
+			}
+
+			return "", fmt.Errorf("node %s not found for %s, must be one of %s", node, resource, strings.Join(nodes, ", "))
+		}
+	}
+
+	ids, err := ct.FindContainerIDs(fqdn)
+	if err != nil {
+		return "", fmt.Errorf("unable to find container %s: %s", fqdn, err)
+	}
+
+	if len(ids) == 0 {
+		return "", fmt.Errorf("container %s for %s is not running", fqdn, resource)
+	}
+
+	return ids[0], nil
+}
+
+// execError converts the exit code of a command in a container to an
+// ExitCodeError so that jumppad exits with the same code
+func execError(err error) error {
+	ee := &container.ExecError{}
+	if errors.As(err, &ee) {
+		return &ExitCodeError{Code: ee.ExitCode}
+	}
+
+	return err
+}
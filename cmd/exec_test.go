@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	cmock "github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var execState = `
+{
+  "blueprint": null,
+  "resources": [
+  {
+    "meta": {
+      "id": "resource.container.api",
+      "name": "api",
+      "type": "container"
+    }
+  },
+  {
+    "meta": {
+      "id": "resource.nomad_cluster.dev",
+      "name": "dev",
+      "type": "nomad_cluster"
+    },
+    "client_nodes": 2
+  },
+  {
+    "meta": {
+      "id": "resource.network.main",
+      "name": "main",
+      "type": "network"
+    }
+  }
+  ]
+}`
+
+func setupExec(t *testing.T) *cmock.ContainerTasks {
+	testutils.SetupState(t, execState)
+
+	ct := &cmock.ContainerTasks{}
+	ct.On("FindContainerIDs", mock.Anything).Return([]string{"abc123"}, nil)
+	ct.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+	ct.On("CreateShell", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return ct
+}
+
+func TestExecRunsCommandInContainer(t *testing.T) {
+	ct := setupExec(t)
+	out := bytes.NewBuffer(nil)
+
+	c := newExecCmd(ct, out)
+	c.SetArgs([]string{"--user", "root", "--workdir", "/tmp", "--env", "FOO=bar", "resource.container.api", "--", "ls", "-la"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "FindContainerIDs", "api.container.local.jmpd.in")
+	ct.AssertCalled(t, "ExecuteCommand", "abc123", []string{"ls", "-la"}, []string{"FOO=bar"}, "/tmp", "root", "", -1, out)
+}
+
+func TestExecPassesTimeoutInSeconds(t *testing.T) {
+	ct := setupExec(t)
+
+	c := newExecCmd(ct, bytes.NewBuffer(nil))
+	c.SetArgs([]string{"--timeout", "1500ms", "resource.container.api", "--", "ls"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "ExecuteCommand", "abc123", []string{"ls"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, 2, mock.Anything)
+}
+
+func TestExecWithTimeoutLessThanASecondReturnsError(t *testing.T) {
+	ct := setupExec(t)
+
+	c := newExecCmd(ct, bytes.NewBuffer(nil))
+	c.SetArgs([]string{"--timeout", "500ms", "resource.container.api", "--", "ls"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "must be at least 1s")
+	ct.AssertNotCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExecReturnsExitCodeError(t *testing.T) {
+	ct := setupExec(t)
+	testutils.RemoveOn(&ct.Mock, "ExecuteCommand")
+	ct.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(3, &container.ExecError{ExitCode: 3})
+
+	c := newExecCmd(ct, bytes.NewBuffer(nil))
+	c.SetArgs([]string{"resource.container.api", "--", "false"})
+
+	err := c.Execute()
+	require.Equal(t, &ExitCodeError{Code: 3}, err)
+}
+
+func TestExecWithInvalidEnvReturnsError(t *testing.T) {
+	ct := setupExec(t)
+
+	c := newExecCmd(ct, bytes.NewBuffer(nil))
+	c.SetArgs([]string{"--env", "FOO", "resource.container.api", "--", "env"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "invalid environment variable 'FOO'")
+	ct.AssertNotCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExecUsesClusterServerByDefault(t *testing.T) {
+	ct := setupExec(t)
+
+	c := newExecCmd(ct, bytes.NewBuffer(nil))
+	c.SetArgs([]string{"resource.nomad_cluster.dev", "--", "nomad", "node", "status"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "FindContainerIDs", "server.dev.nomad-cluster.local.jmpd.in")
+}
+
+func TestExecUsesClusterNode(t *testing.T) {
+	ct := setupExec(t)
+
+	c := newExecCmd(ct, bytes.NewBuffer(nil))
+	c.SetArgs([]string{"--node", "2.client", "resource.nomad_cluster.dev", "--", "nomad", "node", "status"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "FindContainerIDs", "2.client.dev.nomad-cluster.local.jmpd.in")
+}
+
+func TestExecWithUnknownNodeReturnsError(t *testing.T) {
+	ct := setupExec(t)
+
+	c := newExecCmd(ct, bytes.NewBuffer(nil))
+	c.SetArgs([]string{"--node", "3.client", "resource.nomad_cluster.dev", "--", "ls"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "must be one of server, 1.client, 2.client")
+}
+
+func TestExecWithResourceWithoutContainerReturnsError(t *testing.T) {
+	ct := setupExec(t)
+
+	c := newExecCmd(ct, bytes.NewBuffer(nil))
+	c.SetArgs([]string{"resource.network.main", "--", "ls"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "does not have a container")
+}
+
+func TestExecWithInvalidStateReturnsStateError(t *testing.T) {
+	ct := setupExec(t)
+	testutils.SetupState(t, "not json")
+
+	c := newExecCmd(ct, bytes.NewBuffer(nil))
+	c.SetArgs([]string{"resource.container.api", "--", "ls"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "unable to read state file: ")
+}
+
+func TestExecWhenContainerNotRunningReturnsError(t *testing.T) {
+	ct := setupExec(t)
+	testutils.RemoveOn(&ct.Mock, "FindContainerIDs")
+	ct.On("FindContainerIDs", mock.Anything).Return(nil, nil)
+
+	c := newExecCmd(ct, bytes.NewBuffer(nil))
+	c.SetArgs([]string{"resource.container.api", "--", "ls"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "is not running")
+}
+
+func TestShellDefaultsToSh(t *testing.T) {
+	ct := setupExec(t)
+
+	c := newShellCmd(ct, nil, bytes.NewBuffer(nil), bytes.NewBuffer(nil))
+	c.SetArgs([]string{"--env", "FOO=bar", "resource.container.api"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "CreateShell", "abc123", []string{"sh"}, []string{"FOO=bar"}, "", "", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestShellUsesCommand(t *testing.T) {
+	ct := setupExec(t)
+
+	c := newShellCmd(ct, nil, bytes.NewBuffer(nil), bytes.NewBuffer(nil))
+	c.SetArgs([]string{"resource.container.api", "--", "bash", "-l"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "CreateShell", "abc123", []string{"bash", "-l"}, []string(nil), "", "", mock.Anything, mock.Anything, mock.Anything)
+}
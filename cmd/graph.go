@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+	graphFormatJSON    = "json"
+)
+
+func newGraphCmd(e jumppad.Engine, bp getter.Getter) *cobra.Command {
+	var variables []string
+	var variablesFile string
+	var format string
+	var withPlan bool
+
+	graphCmd := &cobra.Command{
+		Use:   "graph [file] | [directory]",
+		Short: "Output the dependency graph for the configuration at the given path",
+		Long: `Output the dependency graph for the configuration at the given path.
+
+Resources are created after the resources they reference or depend on, the graph
+shows these dependencies along with the type, module and current status of each
+resource. The graph can be output as Graphviz DOT, a Mermaid flowchart, or JSON.
+
+When --plan is specified the resources that would be created, refreshed, replaced,
+or destroyed by the next apply are highlighted`,
+		Example: `
+  # Render the graph for the configuration in the current folder as an SVG
+  jumppad graph | dot -Tsvg > graph.svg
+
+  # Output a Mermaid flowchart that can be added to a README
+  jumppad graph --format mermaid ./my-stack
+
+  # Show the resources that would change on the next apply
+  jumppad graph --plan --format json
+	`,
+		Args:         cobra.ArbitraryArgs,
+		RunE:         newGraphCmdFunc(e, bp, &variables, &variablesFile, &format, &withPlan),
+		SilenceUsage: true,
+	}
+
+	graphCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	graphCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	graphCmd.Flags().StringVarP(&format, "format", "", graphFormatDOT, "Output format for the graph, one of dot, mermaid, json")
+	graphCmd.Flags().BoolVarP(&withPlan, "plan", "", false, "Highlight the resources that would be changed by the next apply")
+
+	return graphCmd
+}
+
+func newGraphCmdFunc(e jumppad.Engine, bp getter.Getter, variables *[]string, variablesFile *string, format *string, withPlan *bool) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		switch *format {
+		case graphFormatDOT, graphFormatMermaid, graphFormatJSON:
+		default:
+			return fmt.Errorf(`invalid format "%s", must be one of dot, mermaid, json`, *format)
+		}
+
+		// create the jumppad and sub folders in the users home directory
+		utils.CreateFolders()
+
+		// parse the vars into a map
+		vars := map[string]string{}
+		for _, v := range *variables {
+			// if the variable is wrapped in single quotes remove them
+			v = strings.TrimPrefix(v, "'")
+			v = strings.TrimSuffix(v, "'")
+
+			parts := strings.Split(v, "=")
+			if len(parts) >= 2 {
+				vars[parts[0]] = strings.Join(parts[1:], "=")
+			}
+		}
+
+		// check the variables file exists
+		if variablesFile != nil && *variablesFile != "" {
+			if _, err := os.Stat(*variablesFile); err != nil {
+				return fmt.Errorf("variables file %s, does not exist", *variablesFile)
+			}
+		} else {
+			vf := ""
+			variablesFile = &vf
+		}
+
+		dst := "./"
+		if len(args) == 1 {
+			dst = args[0]
+		}
+
+		if dst == "." {
+			dst = "./"
+		}
+
+		if !utils.IsLocalFolder(dst) && !utils.IsHCLFile(dst) {
+			// fetch the remote server from github
+			err := bp.Get(dst, utils.BlueprintLocalFolder(dst))
+			if err != nil {
+				return fmt.Errorf("unable to retrieve blueprint: %s", err)
+			}
+
+			dst = utils.BlueprintLocalFolder(dst)
+		}
+
+		var plan *jumppad.Plan
+		if *withPlan {
+			var err error
+			plan, err = e.Plan(dst, vars, *variablesFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		c, err := e.ParseConfigWithVariables(dst, vars, *variablesFile)
+		if err != nil {
+			return err
+		}
+
+		// a missing state means nothing has been created
+		state, err := config.LoadState()
+		if err != nil {
+			var le config.StateLockedError
+			if errors.As(err, &le) {
+				return err
+			}
+
+			state = hclconfig.NewConfig()
+		}
+
+		g := jumppad.NewGraph(c, state, plan)
+
+		// write to stdout so the graph can be piped to other tools
+		out := cmd.OutOrStdout()
+
+		switch *format {
+		case graphFormatMermaid:
+			fmt.Fprint(out, g.Mermaid())
+		case graphFormatJSON:
+			d, err := json.MarshalIndent(g, "", "  ")
+			if err != nil {
+				return fmt.Errorf("unable to output graph as JSON: %s", err)
+			}
+
+			fmt.Fprintln(out, string(d))
+		default:
+			fmt.Fprint(out, g.DOT())
+		}
+
+		return nil
+	}
+}
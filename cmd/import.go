@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd(e jumppad.Engine) *cobra.Command {
+	var variables []string
+	var variablesFile string
+
+	importCmd := &cobra.Command{
+		Use:   "import <resource id> <docker id or name> [file] | [directory]",
+		Short: "Add an existing Docker container or network to the state",
+		Long: `Add an existing Docker container or network to the state.
+
+The resource must be defined in the configuration at the given path, the
+default is the current folder. The Docker object is checked against the
+resource configuration and added to the state as created, subsequent runs of
+'jumppad up' and 'jumppad down' manage the object as if it had been created
+by Jumppad.
+
+Containers must use the name Jumppad gives to the resource, existing
+containers can be renamed with 'docker rename'`,
+		Example: `
+  # Import a running container
+  jumppad import resource.container.db db.container.local.jmpd.in
+
+  # Import a network using the configuration in a folder
+  jumppad import resource.network.onprem 4b1c2a0e6f3d ./my-stack
+	`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// create the jumppad and sub folders in the users home directory
+			utils.CreateFolders()
+
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				// if the variable is wrapped in single quotes remove them
+				v = strings.TrimPrefix(v, "'")
+				v = strings.TrimSuffix(v, "'")
+
+				parts := strings.Split(v, "=")
+				if len(parts) >= 2 {
+					vars[parts[0]] = strings.Join(parts[1:], "=")
+				}
+			}
+
+			// check the variables file exists
+			if variablesFile != "" {
+				if _, err := os.Stat(variablesFile); err != nil {
+					return fmt.Errorf("variables file %s, does not exist", variablesFile)
+				}
+			}
+
+			dst := "./"
+			if len(args) == 3 {
+				dst = args[2]
+			}
+
+			if !utils.IsLocalFolder(dst) && !utils.IsHCLFile(dst) {
+				return fmt.Errorf("%s is not a local configuration file or folder", dst)
+			}
+
+			r, err := e.Import(dst, vars, variablesFile, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("%s%s imported from %s\n", greenIcon.Render("+"), whiteText.Render(r.Metadata().ID), args[1])
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+
+	importCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	importCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+
+	return importCmd
+}
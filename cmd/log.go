@@ -1,24 +1,34 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
 	"context"
-	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
 	"os/signal"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/fatih/color"
 	hcltypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	kclient "github.com/jumppad-labs/jumppad/pkg/clients/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	nclient "github.com/jumppad-labs/jumppad/pkg/clients/nomad"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/cache"
 	ct "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
@@ -27,11 +37,24 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
-func newLogCmd(dc container.Docker, stdout, stderr io.Writer) *cobra.Command {
+// logOptions are the flags for the logs command
+type logOptions struct {
+	since      string
+	tail       int
+	noFollow   bool
+	timestamps bool
+	grep       string
+	types      []string
+	json       bool
+}
+
+func newLogCmd(dc container.Docker, kc kclient.Kubernetes, nc func() nclient.Nomad, stdout, stderr io.Writer) *cobra.Command {
+	opts := &logOptions{}
+
 	logCmd := &cobra.Command{
 		Use:     "logs [resource]",
 		Short:   "Tails logs for running jumppad resources",
-		Long:    "Tails logs for running jumppad resources",
+		Long:    "Tails logs for running jumppad resources, for k8s_cluster and nomad_cluster resources the logs for the pods and allocations running in the cluster are also shown",
 		Aliases: []string{"log"},
 		Example: `
   # Tail logs for all running resources
@@ -39,12 +62,26 @@ func newLogCmd(dc container.Docker, stdout, stderr io.Writer) *cobra.Command {
 
 	# Tail logs for a specific resource
 	jumppad logs resource.container.nginx
+
+	# Show the last 10 minutes of logs for all containers without following
+	jumppad logs --since 10m --tail -1 --no-follow --type container
+
+	# Show errors for the pods in a Kubernetes cluster as JSON
+	jumppad logs --grep "(?i)error" --json resource.k8s_cluster.k3s
 	`,
-		Args:              cobra.ArbitraryArgs,
+		Args:              cobra.MaximumNArgs(1),
 		ValidArgsFunction: getResources,
-		RunE:              newLogCmdFunc(dc, stdout, stderr),
+		RunE:              newLogCmdFunc(dc, kc, nc, stdout, stderr, opts),
 	}
 
+	logCmd.Flags().StringVar(&opts.since, "since", "", "Only show logs written after the given time, either a duration such as 10m or an RFC3339 timestamp, not supported for Nomad allocations")
+	logCmd.Flags().IntVar(&opts.tail, "tail", 40, "Number of lines to show from the end of the logs for each source, -1 shows all lines")
+	logCmd.Flags().BoolVar(&opts.noFollow, "no-follow", false, "Exit once the existing logs have been shown instead of following the logs")
+	logCmd.Flags().BoolVar(&opts.timestamps, "timestamps", false, "Show the timestamp for each line, not supported for Nomad allocations")
+	logCmd.Flags().StringVar(&opts.grep, "grep", "", "Only show lines that match the given regular expression")
+	logCmd.Flags().StringSliceVar(&opts.types, "type", nil, "Only show logs for resources of the given types, e.g. container,k8s_cluster")
+	logCmd.Flags().BoolVar(&opts.json, "json", false, "Output each line as a JSON object")
+
 	return logCmd
 }
 
@@ -67,74 +104,121 @@ func getResources(cmd *cobra.Command, args []string, complete string) ([]string,
 	return loggable, cobra.ShellCompDirectiveNoFileComp
 }
 
-func newLogCmdFunc(dc container.Docker, stdout, stderr io.Writer) func(cmd *cobra.Command, args []string) error {
+func newLogCmdFunc(dc container.Docker, kc kclient.Kubernetes, nc func() nclient.Nomad, stdout, stderr io.Writer, opts *logOptions) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		log := createLogger()
-		sigs := make(chan os.Signal, 1)
-		signal.Notify(sigs, os.Interrupt)
-		waitGroup := sync.WaitGroup{}
+		if opts.json {
+			// keep stdout for the JSON lines
+			log = logger.NewLogger(stderr, logger.LogLevelInfo)
+		}
 
-		var loggable []string
+		p := &logPrinter{stdout: stdout, stderr: stderr, json: opts.json, timestamps: opts.timestamps}
 
-		if len(args) == 1 {
-			cfg, err := config.LoadState()
+		if opts.grep != "" {
+			re, err := regexp.Compile(opts.grep)
 			if err != nil {
-				return errors.New("unable to read state file")
+				return fmt.Errorf("invalid value for grep '%s': %s", opts.grep, err)
 			}
 
+			p.grep = re
+		}
+
+		var since time.Time
+		if opts.since != "" {
+			var err error
+			since, err = parseSince(opts.since)
+			if err != nil {
+				return err
+			}
+		}
+
+		cfg, err := config.LoadState()
+		if err != nil {
+			return errors.New("unable to read state file")
+		}
+
+		var resources []hcltypes.Resource
+
+		if len(args) == 1 {
 			r, err := cfg.FindResource(args[0])
 			if err != nil {
 				return fmt.Errorf("%s not found: %s", args[0], err)
 			}
 
-			loggable = getFQDNForResource(r)
+			resources = append(resources, r)
 		} else {
-			var err error
-			loggable, err = getLoggable()
-			if err != nil {
-				return err
+			for _, r := range cfg.Resources {
+				if !r.GetDisabled() {
+					resources = append(resources, r)
+				}
 			}
 		}
 
-		ctx := context.Background()
-
-		for _, r := range loggable {
-			rc, err := dc.ContainerLogs(
-				ctx,
-				r,
-				dcontainer.LogsOptions{
-					ShowStdout: true,
-					ShowStderr: true,
-					Follow:     true,
-					Tail:       "40",
-				},
-			)
-
-			if err == nil {
-				waitGroup.Add(1)
-				go func(rc io.ReadCloser, name string, c color.Attribute, log logger.Logger) {
-					writeLogOutput(rc, stdout, stderr, name, c, log)
-					waitGroup.Done()
-				}(rc, r, getRandomColor(), log)
-			} else {
-				log.Error("Unable to get logs for container", "error", err)
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		ls := &logSources{dc: dc, kc: kc, nc: nc, log: log, opts: opts, since: since}
+
+		streams := []*logStream{}
+		for _, r := range resources {
+			if len(opts.types) > 0 && !slices.Contains(opts.types, r.Metadata().Type) {
+				continue
 			}
+
+			streams = append(streams, ls.open(ctx, r)...)
 		}
 
-		// send an interrupt when the waitGroup is done
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, os.Interrupt)
+		defer signal.Stop(sigs)
+
+		waitGroup := sync.WaitGroup{}
+		for i, s := range streams {
+			waitGroup.Add(1)
+			go func(s *logStream, c color.Attribute) {
+				s.read(p, c, log)
+				waitGroup.Done()
+			}(s, termColors[i%len(termColors)])
+		}
+
+		done := make(chan struct{})
 		go func() {
 			waitGroup.Wait()
-			log.Info("No more logs to tail")
-			sigs <- os.Interrupt
+			close(done)
 		}()
 
-		// block until a signal is received
-		<-sigs
+		// block until all the streams have finished or a signal is received
+		select {
+		case <-done:
+			if !opts.json {
+				log.Info("No more logs to tail")
+			}
+		case <-sigs:
+			cancel()
+			for _, s := range streams {
+				s.reader.Close()
+			}
+		}
 
 		return nil
 	}
 }
 
+// parseSince returns the time for a duration relative to now or an RFC3339
+// timestamp
+func parseSince(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid value for since '%s', must be a duration such as 10m or an RFC3339 timestamp", s)
+	}
+
+	return t, nil
+}
+
 // if this methods returns and error, it will get returned as shell-completion data
 // otherwise fmt.println() gets lost
 func getLoggable() ([]string, error) {
@@ -179,38 +263,334 @@ func getFQDNForResource(r hcltypes.Resource) []string {
 	return fqdns
 }
 
-func getRandomColor() color.Attribute {
-	return termColors[rand.Intn(len(termColors)-1)]
+// logSources opens the log streams for the containers, pods and allocations
+// of a resource
+type logSources struct {
+	dc    container.Docker
+	kc    kclient.Kubernetes
+	nc    func() nclient.Nomad
+	log   logger.Logger
+	opts  *logOptions
+	since time.Time
 }
 
-func writeLogOutput(rc io.ReadCloser, stdout, stderr io.Writer, name string, c color.Attribute, log logger.Logger) {
-	hdr := make([]byte, 8)
-	colorWriter := color.New(c)
+func (l *logSources) open(ctx context.Context, r hcltypes.Resource) []*logStream {
+	streams := []*logStream{}
 
-	for {
-		_, err := rc.Read(hdr)
+	for _, fqdn := range getFQDNForResource(r) {
+		s, err := l.openContainer(ctx, r, fqdn)
 		if err != nil {
-			log.Error("Unable to read from log stream", "name", name, "error", err)
-			return
+			l.log.Error("Unable to get logs for container", "name", fqdn, "error", err)
+			continue
 		}
 
-		var w io.Writer
-		switch hdr[0] {
-		case 1:
-			w = stdout
-		default:
-			w = stderr
+		streams = append(streams, s)
+	}
+
+	switch v := r.(type) {
+	case *k8s.Cluster:
+		streams = append(streams, l.openPods(ctx, v)...)
+	case *nomad.NomadCluster:
+		streams = append(streams, l.openAllocations(ctx, v)...)
+	}
+
+	return streams
+}
+
+func (l *logSources) openContainer(ctx context.Context, r hcltypes.Resource, fqdn string) (*logStream, error) {
+	opts := dcontainer.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     !l.opts.noFollow,
+		Timestamps: l.opts.timestamps,
+		Tail:       "all",
+	}
+
+	if l.opts.tail >= 0 {
+		opts.Tail = strconv.Itoa(l.opts.tail)
+	}
+
+	if !l.since.IsZero() {
+		opts.Since = strconv.FormatInt(l.since.Unix(), 10)
+	}
+
+	rc, err := l.dc.ContainerLogs(ctx, fqdn, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logStream{
+		resource:    r.Metadata().ID,
+		source:      strings.TrimSuffix(fqdn, "."+utils.WorkspaceDomain()+"."+utils.LocalTLD),
+		reader:      rc,
+		multiplexed: true,
+	}, nil
+}
+
+// openPods opens a stream for every container in every pod in the cluster
+func (l *logSources) openPods(ctx context.Context, c *k8s.Cluster) []*logStream {
+	kc, err := l.kc.SetConfig(c.KubeConfig.ConfigPath)
+	if err != nil {
+		l.log.Error("Unable to create Kubernetes client", "cluster", c.Meta.ID, "error", err)
+		return nil
+	}
+
+	pods, err := kc.GetPods("")
+	if err != nil {
+		l.log.Error("Unable to list pods", "cluster", c.Meta.ID, "error", err)
+		return nil
+	}
+
+	streams := []*logStream{}
+	for _, pod := range pods.Items {
+		for _, pc := range pod.Spec.Containers {
+			opts := &v1.PodLogOptions{
+				Container:  pc.Name,
+				Follow:     !l.opts.noFollow,
+				Timestamps: l.opts.timestamps,
+			}
+
+			if l.opts.tail >= 0 {
+				tail := int64(l.opts.tail)
+				opts.TailLines = &tail
+			}
+
+			if !l.since.IsZero() {
+				since := metav1.NewTime(l.since)
+				opts.SinceTime = &since
+			}
+
+			source := fmt.Sprintf("%s/%s/%s", c.Meta.Name, pod.Namespace, pod.Name)
+			if len(pod.Spec.Containers) > 1 {
+				source = source + "/" + pc.Name
+			}
+
+			rc, err := kc.GetPodLogs(ctx, pod.Name, pod.Namespace, opts)
+			if err != nil {
+				l.log.Error("Unable to get logs for pod", "name", source, "error", err)
+				continue
+			}
+
+			streams = append(streams, &logStream{resource: c.Meta.ID, source: source, reader: rc, stream: "stdout"})
 		}
+	}
 
-		count := binary.BigEndian.Uint32(hdr[4:])
-		dat := make([]byte, count)
-		_, err = rc.Read(dat)
+	return streams
+}
+
+// openAllocations opens a stream for stdout and stderr of every task in the
+// running allocations in the cluster
+func (l *logSources) openAllocations(ctx context.Context, c *nomad.NomadCluster) []*logStream {
+	// each cluster has its own client as the client is configured with the
+	// address of the cluster
+	nc := l.nc()
+
+	err := nc.SetConfig(fmt.Sprintf("http://%s", c.ExternalIP), c.APIPort, c.ClientNodes)
+	if err != nil {
+		l.log.Error("Unable to create Nomad client", "cluster", c.Meta.ID, "error", err)
+		return nil
+	}
+
+	tasks, err := nc.AllocationTasks()
+	if err != nil {
+		l.log.Error("Unable to list allocations", "cluster", c.Meta.ID, "error", err)
+		return nil
+	}
+
+	streams := []*logStream{}
+	for _, t := range tasks {
+		source := fmt.Sprintf("%s/%s/%s/%s[%s]", c.Meta.Name, t.Job, t.Group, t.Task, shortAllocationID(t.AllocationID))
+
+		for _, stream := range []string{"stdout", "stderr"} {
+			rc, err := l.openTask(ctx, nc, t, stream)
+			if err != nil {
+				l.log.Error("Unable to get logs for task", "name", source, "error", err)
+				continue
+			}
+
+			streams = append(streams, &logStream{resource: c.Meta.ID, source: source, reader: rc, stream: stream})
+		}
+	}
+
+	return streams
+}
+
+// openTask returns the logs for a task, the Nomad API can not return the last
+// lines of a log so the existing logs are read and tailed before following
+// any new lines
+func (l *logSources) openTask(ctx context.Context, nc nclient.Nomad, t nclient.AllocationTask, stream string) (io.ReadCloser, error) {
+	follow := !l.opts.noFollow
+
+	if l.opts.tail < 0 {
+		return nc.TaskLogs(ctx, t.AllocationID, t.Task, stream, follow, false)
+	}
+
+	rc, err := nc.TaskLogs(ctx, t.AllocationID, t.Task, stream, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := bytes.NewReader(lastLines(d, l.opts.tail))
+
+	if !follow {
+		return io.NopCloser(existing), nil
+	}
+
+	frc, err := nc.TaskLogs(ctx, t.AllocationID, t.Task, stream, true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(existing, frc), frc}, nil
+}
+
+// lastLines returns the last n lines of d
+func lastLines(d []byte, n int) []byte {
+	if n == 0 {
+		return nil
+	}
+
+	lines := bytes.SplitAfter(d, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return bytes.Join(lines, nil)
+}
+
+func shortAllocationID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+
+	return id
+}
+
+// logStream is an open stream of logs from a container, pod, or task
+type logStream struct {
+	resource string
+	source   string
+	reader   io.ReadCloser
+
+	// multiplexed streams contain both stdout and stderr using the Docker
+	// stream format, otherwise all lines are written to stream
+	multiplexed bool
+	stream      string
+}
+
+// read writes every line in the stream to the printer until the stream is
+// closed
+func (s *logStream) read(p *logPrinter, c color.Attribute, log logger.Logger) {
+	defer s.reader.Close()
+
+	if !s.multiplexed {
+		err := scanLines(s.reader, func(line string) { p.print(s, s.stream, line, c) })
 		if err != nil {
-			log.Error("Unable to read from log stream", "name", name, "error", err)
-			return
+			log.Debug("Log stream closed", "name", s.source, "error", err)
+		}
+
+		return
+	}
+
+	outR, outW := io.Pipe()
+	errR, errW := io.Pipe()
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	go func() {
+		scanLines(outR, func(line string) { p.print(s, "stdout", line, c) })
+		wg.Done()
+	}()
+
+	go func() {
+		scanLines(errR, func(line string) { p.print(s, "stderr", line, c) })
+		wg.Done()
+	}()
+
+	_, err := stdcopy.StdCopy(outW, errW, s.reader)
+	if err != nil {
+		log.Debug("Log stream closed", "name", s.source, "error", err)
+	}
+
+	outW.Close()
+	errW.Close()
+	wg.Wait()
+}
+
+func scanLines(r io.Reader, f func(line string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		f(scanner.Text())
+	}
+
+	return scanner.Err()
+}
+
+// logLine is the JSON output for a line of logs
+type logLine struct {
+	Resource  string `json:"resource"`
+	Source    string `json:"source"`
+	Stream    string `json:"stream"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Message   string `json:"message"`
+}
+
+// logPrinter filters and writes the lines from all the log streams
+type logPrinter struct {
+	mutex      sync.Mutex
+	stdout     io.Writer
+	stderr     io.Writer
+	grep       *regexp.Regexp
+	json       bool
+	timestamps bool
+}
+
+func (p *logPrinter) print(s *logStream, stream, line string, c color.Attribute) {
+	ll := logLine{Resource: s.resource, Source: s.source, Stream: stream, Message: line}
+
+	// Docker and Kubernetes add the timestamp to the start of the line
+	if p.timestamps {
+		if ts, msg, ok := strings.Cut(line, " "); ok {
+			if _, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				ll.Timestamp = ts
+				ll.Message = msg
+			}
 		}
+	}
+
+	if p.grep != nil && !p.grep.MatchString(ll.Message) {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 
-		name = strings.TrimSuffix(name, utils.LocalTLD)
-		colorWriter.Fprintf(w, "[%s]   %s", name, string(dat))
+	if p.json {
+		d, _ := json.Marshal(ll)
+		fmt.Fprintln(p.stdout, string(d))
+		return
 	}
+
+	w := p.stdout
+	if stream == "stderr" {
+		w = p.stderr
+	}
+
+	color.New(c).Fprintf(w, "[%s]   %s\n", s.source, line)
 }
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmock "github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	kclient "github.com/jumppad-labs/jumppad/pkg/clients/k8s"
+	nclient "github.com/jumppad-labs/jumppad/pkg/clients/nomad"
+	nmock "github.com/jumppad-labs/jumppad/pkg/clients/nomad/mocks"
+	"github.com/jumppad-labs/jumppad/testutils"
+)
+
+var logState = `
+{
+  "blueprint": null,
+  "resources": [
+  {
+    "meta": {
+      "id": "resource.container.api",
+      "name": "api",
+      "type": "container"
+    }
+  },
+  {
+    "meta": {
+      "id": "resource.k8s_cluster.k3s",
+      "name": "k3s",
+      "type": "k8s_cluster"
+    },
+    "kube_config": {
+      "path": "/tmp/kubeconfig.yaml"
+    }
+  },
+  {
+    "meta": {
+      "id": "resource.nomad_cluster.dev",
+      "name": "dev",
+      "type": "nomad_cluster"
+    },
+    "external_ip": "127.0.0.1",
+    "api_port": 4646
+  }
+  ]
+}`
+
+// syncBuffer is written to by multiple log streams
+type syncBuffer struct {
+	buffer bytes.Buffer
+	mutex  sync.Mutex
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.buffer.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.buffer.String()
+}
+
+type logMocks struct {
+	docker *cmock.Docker
+	k8s    *kclient.MockKubernetes
+	nomad  *nmock.Nomad
+	stdout *syncBuffer
+	stderr *syncBuffer
+}
+
+// dockerLogs returns the logs in the Docker stream format
+func dockerLogs(stdout, stderr string) io.ReadCloser {
+	buf := bytes.NewBuffer(nil)
+	stdcopy.NewStdWriter(buf, stdcopy.Stdout).Write([]byte(stdout))
+	stdcopy.NewStdWriter(buf, stdcopy.Stderr).Write([]byte(stderr))
+
+	return io.NopCloser(buf)
+}
+
+func setupLog(t *testing.T) (*cobra.Command, *logMocks) {
+	testutils.SetupState(t, logState)
+
+	md := &cmock.Docker{}
+	md.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, name string, opts dcontainer.LogsOptions) (io.ReadCloser, error) {
+			return dockerLogs("hello from "+name+"\n", "error from "+name+"\n"), nil
+		},
+	)
+
+	mk := &kclient.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("GetPods", mock.Anything).Return(&v1.PodList{
+		Items: []v1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web"}}},
+			},
+		},
+	}, nil)
+	mk.On("GetPodLogs", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		io.NopCloser(strings.NewReader("pod started\n")), nil,
+	)
+
+	mn := &nmock.Nomad{}
+	mn.On("SetConfig", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mn.On("AllocationTasks").Return([]nclient.AllocationTask{
+		{AllocationID: "da975cd1-8b04", Job: "example", Group: "app", Task: "server"},
+	}, nil)
+	mn.On("TaskLogs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, id, task, logType string, follow, fromEnd bool) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("one " + logType + "\ntwo " + logType + "\nthree " + logType + "\n")), nil
+		},
+	)
+
+	lm := &logMocks{docker: md, k8s: mk, nomad: mn, stdout: &syncBuffer{}, stderr: &syncBuffer{}}
+
+	return newLogCmd(md, mk, func() nclient.Nomad { return mn }, lm.stdout, lm.stderr), lm
+}
+
+func TestLogsShowsContainerLogs(t *testing.T) {
+	c, lm := setupLog(t)
+	c.SetArgs([]string{"--no-follow", "resource.container.api"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	require.Contains(t, lm.stdout.String(), "[api.container]   hello from api.container.local.jmpd.in")
+	require.Contains(t, lm.stderr.String(), "[api.container]   error from api.container.local.jmpd.in")
+
+	lm.docker.AssertCalled(t, "ContainerLogs", mock.Anything, "api.container.local.jmpd.in", dcontainer.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     false,
+		Tail:       "40",
+	})
+}
+
+func TestLogsSetsContainerLogOptions(t *testing.T) {
+	c, lm := setupLog(t)
+	c.SetArgs([]string{"--since", "2024-01-02T03:04:05Z", "--tail", "-1", "--timestamps", "resource.container.api"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	opts := testutils.GetCalls(&lm.docker.Mock, "ContainerLogs")[0].Arguments[2].(dcontainer.LogsOptions)
+	require.True(t, opts.Follow)
+	require.True(t, opts.Timestamps)
+	require.Equal(t, "all", opts.Tail)
+	require.Equal(t, "1704164645", opts.Since)
+}
+
+func TestLogsWithInvalidSinceReturnsError(t *testing.T) {
+	c, _ := setupLog(t)
+	c.SetArgs([]string{"--since", "yesterday"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "invalid value for since")
+}
+
+func TestLogsFiltersWithGrep(t *testing.T) {
+	c, lm := setupLog(t)
+	c.SetArgs([]string{"--no-follow", "--grep", "^error", "resource.container.api"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	require.NotContains(t, lm.stdout.String(), "hello")
+	require.Contains(t, lm.stderr.String(), "error from")
+}
+
+func TestLogsFiltersByType(t *testing.T) {
+	c, lm := setupLog(t)
+	c.SetArgs([]string{"--no-follow", "--type", "container"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	lm.docker.AssertNumberOfCalls(t, "ContainerLogs", 1)
+	lm.k8s.AssertNotCalled(t, "GetPods", mock.Anything)
+	lm.nomad.AssertNotCalled(t, "AllocationTasks")
+}
+
+func TestLogsOutputsJSON(t *testing.T) {
+	c, lm := setupLog(t)
+	lm.docker.ExpectedCalls = nil
+	lm.docker.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Return(
+		dockerLogs("2024-01-02T03:04:05.123456789Z hello\n", ""), nil,
+	)
+
+	c.SetArgs([]string{"--no-follow", "--json", "--timestamps", "resource.container.api"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	ll := logLine{}
+	err = json.Unmarshal([]byte(strings.TrimSpace(lm.stdout.String())), &ll)
+	require.NoError(t, err)
+
+	require.Equal(t, logLine{
+		Resource:  "resource.container.api",
+		Source:    "api.container",
+		Stream:    "stdout",
+		Timestamp: "2024-01-02T03:04:05.123456789Z",
+		Message:   "hello",
+	}, ll)
+}
+
+func TestLogsShowsPodLogsForKubernetesCluster(t *testing.T) {
+	c, lm := setupLog(t)
+	c.SetArgs([]string{"--no-follow", "--tail", "10", "resource.k8s_cluster.k3s"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	require.Contains(t, lm.stdout.String(), "[server.k3s.k8s-cluster]   hello from server.k3s.k8s-cluster.local.jmpd.in")
+	require.Contains(t, lm.stdout.String(), "[k3s/default/web]   pod started")
+
+	lm.k8s.AssertCalled(t, "SetConfig", "/tmp/kubeconfig.yaml")
+
+	opts := testutils.GetCalls(&lm.k8s.Mock, "GetPodLogs")[0].Arguments[3].(*v1.PodLogOptions)
+	require.Equal(t, "web", opts.Container)
+	require.False(t, opts.Follow)
+	require.Equal(t, int64(10), *opts.TailLines)
+}
+
+func TestLogsShowsAllocationLogsForNomadCluster(t *testing.T) {
+	c, lm := setupLog(t)
+	c.SetArgs([]string{"--no-follow", "--tail", "2", "resource.nomad_cluster.dev"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	require.Contains(t, lm.stdout.String(), "[dev/example/app/server[da975cd1]]   two stdout")
+	require.Contains(t, lm.stdout.String(), "[dev/example/app/server[da975cd1]]   three stdout")
+	require.NotContains(t, lm.stdout.String(), "one stdout")
+	require.Contains(t, lm.stderr.String(), "three stderr")
+
+	lm.nomad.AssertCalled(t, "SetConfig", "http://127.0.0.1", 4646, 0)
+	lm.nomad.AssertCalled(t, "TaskLogs", mock.Anything, "da975cd1-8b04", "server", "stdout", false, false)
+}
+
+func TestLogsDoesNotListAllocationsWhenNomadConfigFails(t *testing.T) {
+	c, lm := setupLog(t)
+	testutils.RemoveOn(&lm.nomad.Mock, "SetConfig")
+	lm.nomad.On("SetConfig", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	c.SetArgs([]string{"--no-follow", "resource.nomad_cluster.dev"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	lm.nomad.AssertNotCalled(t, "AllocationTasks")
+}
+
+func TestLogsFollowsAllocationLogsAfterTail(t *testing.T) {
+	c, lm := setupLog(t)
+	c.SetArgs([]string{"--tail", "1", "resource.nomad_cluster.dev"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	lm.nomad.AssertCalled(t, "TaskLogs", mock.Anything, "da975cd1-8b04", "server", "stdout", false, false)
+	lm.nomad.AssertCalled(t, "TaskLogs", mock.Anything, "da975cd1-8b04", "server", "stdout", true, true)
+}
+
+func TestLastLinesReturnsEndOfLogs(t *testing.T) {
+	require.Equal(t, "b\nc\n", string(lastLines([]byte("a\nb\nc\n"), 2)))
+	require.Equal(t, "a\nb\nc", string(lastLines([]byte("a\nb\nc"), 5)))
+	require.Empty(t, lastLines([]byte("a\nb\nc\n"), 0))
+}
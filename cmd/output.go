@@ -3,26 +3,30 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/hokaccha/go-prettyjson"
 	"github.com/jumppad-labs/hclconfig/resources"
 	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
 	"github.com/spf13/cobra"
 )
 
 var outputCmd = &cobra.Command{
 	Use:   "output",
 	Short: "Show the output variables",
-	Long:  `Show the output variables`,
-	Args:  cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Show the output variables.
+
+Sensitive outputs are redacted unless the --show-sensitive flag is set. An output
+is sensitive when it sets sensitive = true, the value uses the sensitive function,
+references a sensitive variable, or references a sensitive attribute such as the
+value of a random_password`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// load the stack
 		cfg, err := config.LoadState()
 		if err != nil {
-			cmd.Println("Error: Unable to load state, ", err)
-			os.Exit(1)
+			return fmt.Errorf("unable to load state: %s", err)
 		}
 
 		out := map[string]interface{}{}
@@ -38,17 +42,24 @@ var outputCmd = &cobra.Command{
 					continue
 				}
 
-				out[r.Metadata().Name] = r.(*resources.Output).Value
+				value := r.(*resources.Output).Value
+				if r.Metadata().Properties[constants.PropertySensitive] == true && !showSensitive(cmd) {
+					value = config.SensitiveValue
+				}
+
+				out[r.Metadata().Name] = value
 
 				if len(args) > 0 && strings.EqualFold(args[0], r.Metadata().Name) {
-					d, _ := json.Marshal(r.(*resources.Output).Value)
+					d, _ := json.Marshal(value)
 					fmt.Printf("%s", string(d))
-					return
+					return nil
 				}
 			}
 		}
 
 		d, _ := prettyjson.Marshal(out)
 		fmt.Printf("%s", string(d))
+
+		return nil
 	},
 }
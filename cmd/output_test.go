@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputReturnsErrorWhenStateDoesNotExist(t *testing.T) {
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+	t.Setenv(utils.WorkspaceEnvVar, "")
+
+	err := outputCmd.RunE(outputCmd, []string{})
+	require.ErrorContains(t, err, "unable to load state")
+}
+
+func TestTaintReturnsErrorWithoutResource(t *testing.T) {
+	err := taintCmd.RunE(taintCmd, []string{})
+	require.ErrorContains(t, err, "resource to taint must be specified")
+}
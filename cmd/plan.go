@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newPlanCmd(e jumppad.Engine, bp getter.Getter) *cobra.Command {
+	var variables []string
+	var variablesFile string
+	var jsonOutput bool
+
+	planCmd := &cobra.Command{
+		Use:   "plan [file] | [directory]",
+		Short: "Show the changes required to apply the configuration at the given path",
+		Long: `Show the changes required to apply the configuration at the given path.
+
+For each resource the plan shows whether the resource will be created, refreshed,
+replaced (destroyed then created), destroyed, or left unchanged, along with the
+attributes that have changed since the configuration was last applied.
+
+The values of sensitive attributes are redacted unless the --show-sensitive flag
+is set`,
+		Example: `
+  # Show the plan for the configuration in the current folder
+  jumppad plan
+
+  # Output the plan as JSON
+  jumppad plan --json ./my-stack
+	`,
+		Args:         cobra.ArbitraryArgs,
+		RunE:         newPlanCmdFunc(e, bp, &variables, &variablesFile, &jsonOutput),
+		SilenceUsage: true,
+	}
+
+	planCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	planCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	planCmd.Flags().BoolVarP(&jsonOutput, "json", "", false, "Output the plan as JSON")
+
+	return planCmd
+}
+
+func newPlanCmdFunc(e jumppad.Engine, bp getter.Getter, variables *[]string, variablesFile *string, jsonOutput *bool) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		// create the jumppad and sub folders in the users home directory
+		utils.CreateFolders()
+
+		// parse the vars into a map
+		vars := map[string]string{}
+		for _, v := range *variables {
+			// if the variable is wrapped in single quotes remove them
+			v = strings.TrimPrefix(v, "'")
+			v = strings.TrimSuffix(v, "'")
+
+			parts := strings.Split(v, "=")
+			if len(parts) >= 2 {
+				vars[parts[0]] = strings.Join(parts[1:], "=")
+			}
+		}
+
+		// check the variables file exists
+		if variablesFile != nil && *variablesFile != "" {
+			if _, err := os.Stat(*variablesFile); err != nil {
+				return fmt.Errorf("variables file %s, does not exist", *variablesFile)
+			}
+		} else {
+			vf := ""
+			variablesFile = &vf
+		}
+
+		dst := "./"
+		if len(args) == 1 {
+			dst = args[0]
+		}
+
+		if dst == "." {
+			dst = "./"
+		}
+
+		if !utils.IsLocalFolder(dst) && !utils.IsHCLFile(dst) {
+			// fetch the remote server from github
+			err := bp.Get(dst, utils.BlueprintLocalFolder(dst))
+			if err != nil {
+				return fmt.Errorf("unable to retrieve blueprint: %s", err)
+			}
+
+			dst = utils.BlueprintLocalFolder(dst)
+		}
+
+		// the plan is returned with the error when a resource with
+		// prevent_destroy would be destroyed, show the plan before the error
+		plan, planErr := e.Plan(dst, vars, *variablesFile)
+		if plan == nil {
+			return planErr
+		}
+
+		if !showSensitive(cmd) {
+			plan.Redact()
+		}
+
+		if *jsonOutput {
+			d, err := json.MarshalIndent(plan, "", "  ")
+			if err != nil {
+				return fmt.Errorf("unable to output plan as JSON: %s", err)
+			}
+
+			// values from sensitive attributes can be interpolated into other
+			// attributes e.g. a password in an environment variable
+			cmd.Println(logger.Redact(string(d)))
+			return planErr
+		}
+
+		printPlan(cmd, plan)
+
+		return planErr
+	}
+}
+
+func printPlan(cmd *cobra.Command, plan *jumppad.Plan) {
+	for _, r := range plan.Resources {
+		if r.Action == jumppad.PlanActionNone {
+			continue
+		}
+
+		reason := ""
+		if r.Reason != "" {
+			reason = grayText.Render(fmt.Sprintf(" (%s)", r.Reason))
+		}
+
+		if r.PreventDestroy && (r.Action == jumppad.PlanActionDestroy || r.Action == jumppad.PlanActionReplace) {
+			reason += redIcon.Render(" (prevent_destroy is set)")
+		}
+
+		cmd.Printf("%s%s will be %s%s\n", planActionIcon(r.Action), whiteText.Render(r.ID), planActionText(r.Action), reason)
+
+		for _, c := range r.Changes {
+			switch {
+			case c.Old == nil:
+				cmd.Printf("    %s%s: %s\n", greenIcon.Render("+"), c.Path, formatPlanValue(c.New))
+			case c.New == nil:
+				cmd.Printf("    %s%s: %s\n", redIcon.Render("-"), c.Path, formatPlanValue(c.Old))
+			default:
+				cmd.Printf("    %s%s: %s => %s\n", yellowIcon.Render("~"), c.Path, formatPlanValue(c.Old), formatPlanValue(c.New))
+			}
+		}
+	}
+
+	if !plan.HasChanges() {
+		cmd.Println("No changes, the resources match the configuration")
+		return
+	}
+
+	cmd.Println()
+	cmd.Println(whiteText.Render(fmt.Sprintf(
+		"Plan: %d to create, %d to refresh, %d to replace, %d to destroy",
+		plan.Summary.Create,
+		plan.Summary.Refresh,
+		plan.Summary.Replace,
+		plan.Summary.Destroy,
+	)))
+}
+
+func planActionIcon(a jumppad.PlanAction) string {
+	switch a {
+	case jumppad.PlanActionCreate:
+		return greenIcon.Render("+")
+	case jumppad.PlanActionDestroy:
+		return redIcon.Render("-")
+	case jumppad.PlanActionReplace:
+		return redIcon.Render("-/+")
+	default:
+		return yellowIcon.Render("~")
+	}
+}
+
+func planActionText(a jumppad.PlanAction) string {
+	switch a {
+	case jumppad.PlanActionCreate:
+		return "created"
+	case jumppad.PlanActionDestroy:
+		return "destroyed"
+	case jumppad.PlanActionReplace:
+		return "destroyed and created"
+	default:
+		return "refreshed"
+	}
+}
+
+func formatPlanValue(v interface{}) string {
+	d, err := json.Marshal(v)
+	if err != nil {
+		return logger.Redact(fmt.Sprintf("%v", v))
+	}
+
+	return logger.Redact(string(d))
+}
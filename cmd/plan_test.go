@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	enginemocks "github.com/jumppad-labs/jumppad/pkg/jumppad/mocks"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPlan(t *testing.T, args ...string) (*cobra.Command, *bytes.Buffer) {
+	me := &enginemocks.Engine{}
+	me.On("Plan", mock.Anything, mock.Anything, mock.Anything).Return(&jumppad.Plan{
+		Resources: []jumppad.ResourcePlan{
+			{
+				ID:     "resource.container.db",
+				Action: jumppad.PlanActionRefresh,
+				Changes: []jumppad.AttributeChange{
+					{Path: "image.password", Old: "oldsecret", New: "newsecret", Sensitive: true},
+				},
+			},
+		},
+		Summary: jumppad.PlanSummary{Refresh: 1},
+	}, nil)
+
+	out := bytes.NewBuffer(nil)
+
+	c := newPlanCmd(me, nil)
+	c.Flags().Bool("show-sensitive", false, "")
+	c.SetOut(out)
+	c.SetArgs(append([]string{t.TempDir()}, args...))
+
+	return c, out
+}
+
+func TestPlanRedactsSensitiveValues(t *testing.T) {
+	c, out := setupPlan(t)
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	require.NotContains(t, out.String(), "newsecret")
+	require.Contains(t, out.String(), "(sensitive)")
+}
+
+func TestPlanJSONRedactsSensitiveValues(t *testing.T) {
+	c, out := setupPlan(t, "--json")
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	require.NotContains(t, out.String(), "newsecret")
+	require.Contains(t, out.String(), "(sensitive)")
+}
+
+func TestPlanWithShowSensitiveShowsValues(t *testing.T) {
+	c, out := setupPlan(t, "--json", "--show-sensitive")
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	require.Contains(t, out.String(), "newsecret")
+}
@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	outputText  = "text"
+	outputTree  = "tree"
+	outputJSONL = "jsonl"
+)
+
+// subscribeOutput configures the rendering of engine events for the given
+// output format and returns a function that must be called once the engine
+// has finished.
+//
+// text writes the log output only, tree renders a live progress tree, and
+// jsonl streams every event as a JSON object on a single line
+func subscribeOutput(bus *events.Bus, l logger.Logger, output string, w io.Writer) (func(), error) {
+	switch output {
+	case outputText, "":
+		return func() {}, nil
+
+	case outputJSONL:
+		// keep stdout clean for the event stream
+		l.SetOutput(os.Stderr)
+
+		return bus.Subscribe(newJSONLRenderer(w)), nil
+
+	case outputTree:
+		// the log output would break the tree, unless debugging only
+		// errors are shown once the tree is complete
+		if !l.IsDebug() {
+			l.SetOutput(io.Discard)
+		}
+
+		pt := newProgressTree(w)
+		unsubscribe := bus.Subscribe(pt.Handle)
+		pt.Start()
+
+		return func() {
+			unsubscribe()
+			pt.Stop()
+		}, nil
+	}
+
+	return nil, fmt.Errorf("invalid output %s, valid options are text, tree, or jsonl", output)
+}
+
+// newJSONLRenderer returns a handler that writes each event as JSON
+func newJSONLRenderer(w io.Writer) events.Handler {
+	mutex := sync.Mutex{}
+	enc := json.NewEncoder(w)
+
+	return func(e events.Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		enc.Encode(e)
+	}
+}
+
+type progressCheck struct {
+	message  string
+	status   string
+	duration time.Duration
+}
+
+type progressNode struct {
+	id       string
+	action   string
+	status   string
+	started  time.Time
+	duration time.Duration
+	err      string
+	checks   []*progressCheck
+}
+
+// progressTree renders the engine events as a tree of resources and their
+// health checks. When the output is a terminal the tree is redrawn in place,
+// otherwise a line is written as each operation completes
+type progressTree struct {
+	mutex  sync.Mutex
+	w      io.Writer
+	tty    bool
+	nodes  []*progressNode
+	lines  int
+	ticker *time.Ticker
+	done   chan struct{}
+}
+
+func newProgressTree(w io.Writer) *progressTree {
+	tty := false
+	if f, ok := w.(*os.File); ok {
+		tty = isatty.IsTerminal(f.Fd())
+	}
+
+	return &progressTree{w: w, tty: tty, done: make(chan struct{})}
+}
+
+// Start redraws the tree periodically to update the elapsed times
+func (p *progressTree) Start() {
+	if !p.tty {
+		return
+	}
+
+	p.ticker = time.NewTicker(500 * time.Millisecond)
+
+	go func() {
+		for {
+			select {
+			case <-p.ticker.C:
+				p.mutex.Lock()
+				p.render()
+				p.mutex.Unlock()
+			case <-p.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop draws the final tree
+func (p *progressTree) Stop() {
+	if p.ticker != nil {
+		p.ticker.Stop()
+		close(p.done)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.tty {
+		p.render()
+	}
+}
+
+// Handle updates the tree with the given event
+func (p *progressTree) Handle(e events.Event) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	n := p.node(e.ResourceID)
+
+	switch e.Type {
+	case events.ResourceCreateStarted, events.ResourceRefreshStarted, events.ResourceDestroyStarted,
+		events.ResourceStopStarted, events.ResourceStartStarted:
+		n.action = eventAction(e.Type)
+		n.status = "running"
+		n.started = e.Time
+		n.err = ""
+		n.checks = nil
+
+	case events.ResourceCreateFinished, events.ResourceRefreshFinished, events.ResourceDestroyFinished,
+		events.ResourceStopFinished, events.ResourceStartFinished:
+		n.status = "done"
+		n.duration = e.Duration()
+
+	case events.ResourceCreateFailed, events.ResourceRefreshFailed, events.ResourceDestroyFailed,
+		events.ResourceStopFailed, events.ResourceStartFailed:
+		n.status = "failed"
+		n.duration = e.Duration()
+		n.err = e.Error
+
+	case events.ResourceCreateRetrying:
+		n.status = "running"
+		n.err = fmt.Sprintf("%s, %s", e.Error, e.Message)
+
+	case events.HealthCheckStarted, events.HealthCheckProgress, events.HealthCheckPassed, events.HealthCheckFailed:
+		c := n.check(e.Message)
+		c.duration = e.Duration()
+
+		switch e.Type {
+		case events.HealthCheckPassed:
+			c.status = "done"
+		case events.HealthCheckFailed:
+			c.status = "failed"
+		default:
+			c.status = "running"
+		}
+	}
+
+	if p.tty {
+		p.render()
+		return
+	}
+
+	// when not writing to a terminal only write completed operations
+	switch e.Type {
+	case events.ResourceCreateFinished, events.ResourceRefreshFinished, events.ResourceDestroyFinished,
+		events.ResourceStopFinished, events.ResourceStartFinished,
+		events.ResourceCreateFailed, events.ResourceRefreshFailed, events.ResourceDestroyFailed,
+		events.ResourceStopFailed, events.ResourceStartFailed:
+		fmt.Fprint(p.w, p.formatNode(n))
+
+	case events.ResourceCreateRetrying:
+		fmt.Fprintf(p.w, "%s%s %s\n", statusIcon("running"), n.id, grayText.Render(e.Message))
+
+	case events.HealthCheckPassed, events.HealthCheckFailed:
+		c := n.check(e.Message)
+		fmt.Fprintf(p.w, "%s%s health check %s %s\n", statusIcon(c.status), n.id, c.message, grayText.Render(formatDuration(c.duration)))
+	}
+}
+
+func (p *progressTree) node(id string) *progressNode {
+	for _, n := range p.nodes {
+		if n.id == id {
+			return n
+		}
+	}
+
+	n := &progressNode{id: id}
+	p.nodes = append(p.nodes, n)
+
+	return n
+}
+
+func (n *progressNode) check(message string) *progressCheck {
+	for _, c := range n.checks {
+		if c.message == message {
+			return c
+		}
+	}
+
+	c := &progressCheck{message: message}
+	n.checks = append(n.checks, c)
+
+	return c
+}
+
+func (p *progressTree) formatNode(n *progressNode) string {
+	d := n.duration
+	if n.status == "running" {
+		d = time.Since(n.started)
+	}
+
+	line := fmt.Sprintf("%s%s %s %s\n", statusIcon(n.status), n.id, grayText.Render(statusText(n.action, n.status)), grayText.Render(formatDuration(d)))
+
+	if n.err != "" {
+		line += fmt.Sprintf("    %s %s\n", grayText.Render("└─"), redIcon.Render(n.err))
+	}
+
+	return line
+}
+
+// render redraws the tree, the caller must hold the mutex
+func (p *progressTree) render() {
+	sb := strings.Builder{}
+
+	// move the cursor to the start of the tree and clear it
+	if p.lines > 0 {
+		sb.WriteString(fmt.Sprintf("\033[%dA\033[J", p.lines))
+	}
+
+	lines := 0
+	for _, n := range p.nodes {
+		s := p.formatNode(n)
+		sb.WriteString(s)
+		lines += strings.Count(s, "\n")
+
+		for _, c := range n.checks {
+			sb.WriteString(fmt.Sprintf("    %s %s%s %s\n", grayText.Render("└─"), statusIcon(c.status), c.message, grayText.Render(formatDuration(c.duration))))
+			lines++
+		}
+	}
+
+	p.lines = lines
+	fmt.Fprint(p.w, sb.String())
+}
+
+func eventAction(t events.Type) string {
+	switch t {
+	case events.ResourceRefreshStarted:
+		return "refresh"
+	case events.ResourceDestroyStarted:
+		return "destroy"
+	case events.ResourceStopStarted:
+		return "stop"
+	case events.ResourceStartStarted:
+		return "start"
+	default:
+		return "create"
+	}
+}
+
+func statusIcon(status string) string {
+	switch status {
+	case "done":
+		return greenIcon.Render("✔")
+	case "failed":
+		return redIcon.Render("✘")
+	default:
+		return yellowIcon.Render("•")
+	}
+}
+
+func statusText(action, status string) string {
+	switch status {
+	case "running":
+		return map[string]string{"create": "creating", "refresh": "refreshing", "destroy": "destroying", "stop": "stopping", "start": "starting"}[action]
+	case "failed":
+		return fmt.Sprintf("%s failed", action)
+	default:
+		return map[string]string{"create": "created", "refresh": "refreshed", "destroy": "destroyed", "stop": "stopped", "start": "started"}[action]
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// printTimingSummary writes the slowest resources and the critical path
+// through the dependency graph, the critical path shows the resources that
+// determine how long the configuration takes to apply
+func printTimingSummary(w io.Writer, s jumppad.TimingSummary) {
+	if len(s.Slowest) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, whiteText.Render("Slowest resources"))
+
+	for _, r := range s.Slowest {
+		fmt.Fprintf(w, "  %-10s %s\n", formatDuration(r.Duration), r.ID)
+	}
+
+	if len(s.CriticalPath) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "")
+	fmt.Fprintf(w, "%s %s\n", whiteText.Render("Critical path"), grayText.Render(formatDuration(s.CriticalPathDuration())))
+
+	for i, r := range s.CriticalPath {
+		arrow := "  "
+		if i > 0 {
+			arrow = "→ "
+		}
+
+		fmt.Fprintf(w, "  %-10s %s%s\n", formatDuration(r.Duration), arrow, r.ID)
+	}
+}
+
+// printFailureReport writes the resources that failed to be created along
+// with the resources that were skipped because of the failures
+func printFailureReport(w io.Writer, ae *jumppad.ApplyError) {
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, whiteText.Render(fmt.Sprintf("%d resources failed", len(ae.Failures))))
+
+	for _, f := range ae.Failures {
+		fmt.Fprintln(w, "")
+		fmt.Fprintf(w, "%s %s\n", redIcon.Render("✘"), f.ID)
+
+		for _, l := range strings.Split(strings.TrimSpace(f.Error), "\n") {
+			fmt.Fprintf(w, "    %s\n", l)
+		}
+
+		for _, s := range f.Skipped {
+			fmt.Fprintf(w, "    %s %s\n", grayText.Render("skipped"), s)
+		}
+	}
+
+	if len(ae.NotStarted) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, whiteText.Render("Not started after the first failure, use --keep-going to create independent resources"))
+
+	for _, id := range ae.NotStarted {
+		fmt.Fprintf(w, "  %s %s\n", yellowIcon.Render("-"), id)
+	}
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients"
@@ -48,8 +47,7 @@ func newPushCmd(ct container.ContainerTasks, l logger.Logger) *cobra.Command {
 
 			c, err := config.LoadState()
 			if err != nil {
-				cmd.Println("Error: Unable to load state, ", err)
-				os.Exit(1)
+				return fmt.Errorf("unable to load state: %s", err)
 			}
 
 			r, err := c.FindResource(cluster)
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/spf13/cobra"
+)
+
+func newRefreshCmd(e jumppad.Engine) *cobra.Command {
+	var fix bool
+	var jsonOutput bool
+
+	refreshCmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Check the running resources against the state",
+		Long: `Check the running resources against the state.
+
+Reports resources where the objects backing the resource have been changed or
+removed outside of Jumppad, for example a container that has been stopped or a
+network that has been deleted. Use --fix to mark these resources as tainted so
+that they are recreated the next time 'jumppad up' is run.
+
+Resources with types that do not support drift detection are listed as not
+checked`,
+		Example: `
+  # Report any resources that have drifted
+  jumppad refresh
+
+  # Mark drifted resources so they are recreated by the next up
+  jumppad refresh --fix
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := e.DetectDrift(fix)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				d, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("unable to output drift as JSON: %s", err)
+				}
+
+				cmd.Println(string(d))
+				return nil
+			}
+
+			printDrift(cmd, report, fix)
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+
+	refreshCmd.Flags().BoolVarP(&fix, "fix", "", false, "Mark resources that have drifted as tainted so they are recreated by the next up")
+	refreshCmd.Flags().BoolVarP(&jsonOutput, "json", "", false, "Output the drifted resources as JSON")
+
+	return refreshCmd
+}
+
+func printDrift(cmd *cobra.Command, report *jumppad.DriftReport, fix bool) {
+	if len(report.Unchecked) > 0 {
+		for _, id := range report.Unchecked {
+			cmd.Printf("%s%s\n", grayIcon.Render("-"), grayText.Render(id))
+		}
+
+		cmd.Println()
+		cmd.Println(grayText.Render(fmt.Sprintf("%d resources were not checked, drift detection is not supported for their type", len(report.Unchecked))))
+		cmd.Println()
+	}
+
+	drift := report.Drifted
+	if len(drift) == 0 {
+		cmd.Println("No drift detected, the checked resources match the state")
+		return
+	}
+
+	for _, d := range drift {
+		cmd.Printf("%s%s\n", yellowIcon.Render("~"), whiteText.Render(d.ID))
+
+		for _, r := range d.Reasons {
+			cmd.Printf("    %s\n", grayText.Render(r))
+		}
+	}
+
+	cmd.Println()
+
+	if fix {
+		cmd.Println(whiteText.Render(fmt.Sprintf("%d resources marked as tainted, run 'jumppad up' to recreate them", len(drift))))
+		return
+	}
+
+	cmd.Println(whiteText.Render(fmt.Sprintf("%d resources have drifted, run 'jumppad refresh --fix' to recreate them on the next up", len(drift))))
+}
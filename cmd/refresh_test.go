@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	enginemocks "github.com/jumppad-labs/jumppad/pkg/jumppad/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshReportsUncheckedResources(t *testing.T) {
+	me := &enginemocks.Engine{}
+	me.On("DetectDrift", false).Return(&jumppad.DriftReport{
+		Drifted:   []jumppad.ResourceDrift{{ID: "resource.container.api", Reasons: []string{"container is exited"}}},
+		Unchecked: []string{"resource.exec.setup"},
+	}, nil)
+
+	out := bytes.NewBuffer(nil)
+
+	c := newRefreshCmd(me)
+	c.SetOut(out)
+	c.SetArgs([]string{})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	require.Contains(t, out.String(), "resource.exec.setup")
+	require.Contains(t, out.String(), "1 resources were not checked")
+	require.Contains(t, out.String(), "container is exited")
+}
@@ -1,15 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/jumppad-labs/jumppad/cmd/changelog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/telemetry"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 
 	"github.com/spf13/cobra"
 )
@@ -24,8 +29,11 @@ var version string //lint:ignore U1000 set at runtime
 var date string    //lint:ignore U1000 set at runtime
 var commit string  //lint:ignore U1000 set at runtime
 
-func createEngine(l logger.Logger, c *clients.Clients) (jumppad.Engine, error) {
-	providers := config.NewProviders(c)
+// shutdownTracing flushes the spans when tracing is enabled
+var shutdownTracing func(context.Context) error
+
+func createEngine(l logger.Logger, p *clients.Pool) (jumppad.Engine, error) {
+	providers := config.NewProvidersWithPool(p)
 
 	engine, err := jumppad.New(providers, l)
 	if err != nil {
@@ -53,9 +61,11 @@ func Execute(v, c, d string) error {
 	// setup dependencies
 	l := createLogger()
 
-	engineClients, _ := clients.GenerateClients(l)
+	// the engine and the commands share the clients from the pool
+	pool := clients.DefaultPool(l)
+	engine, _ := createEngine(l, pool)
 
-	engine, _ := createEngine(l, engineClients)
+	engineClients, _ := pool.Clients()
 
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(outputCmd)
@@ -70,7 +80,10 @@ func Execute(v, c, d string) error {
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(uninstallCmd)
 	rootCmd.AddCommand(newPushCmd(engineClients.ContainerTasks, l))
-	rootCmd.AddCommand(newLogCmd(engineClients.Docker, os.Stdout, os.Stderr), completionCmd)
+	rootCmd.AddCommand(newLogCmd(engineClients.Docker, engineClients.Kubernetes, engineClients.NewNomadClient, os.Stdout, os.Stderr), completionCmd)
+	rootCmd.AddCommand(newExecCmd(engineClients.ContainerTasks, os.Stdout))
+	rootCmd.AddCommand(newShellCmd(engineClients.ContainerTasks, os.Stdin, os.Stdout, os.Stderr))
+	rootCmd.AddCommand(newCopyCmd(engineClients.ContainerTasks))
 	rootCmd.AddCommand(changelogCmd)
 
 	// add the server commands
@@ -89,14 +102,86 @@ func Execute(v, c, d string) error {
 	// add the validate command
 	rootCmd.AddCommand(newValidateCmd(engine, engineClients.Getter))
 
+	// add the plan command
+	rootCmd.AddCommand(newPlanCmd(engine, engineClients.Getter))
+
+	// add the graph command
+	rootCmd.AddCommand(newGraphCmd(engine, engineClients.Getter))
+
+	// add the refresh command
+	rootCmd.AddCommand(newRefreshCmd(engine))
+
+	// add the import command
+	rootCmd.AddCommand(newImportCmd(engine))
+
+	// add the stop and start commands
+	rootCmd.AddCommand(newStopCmd(engine, l))
+	rootCmd.AddCommand(newStartCmd(engine, l))
+
+	// add the snapshot commands
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(newSnapshotSaveCmd(engineClients.ContainerTasks, engineClients.TarGz, l))
+	snapshotCmd.AddCommand(newSnapshotRestoreCmd(engine, engineClients.ContainerTasks, engineClients.TarGz, l))
+
 	// add the fmt command
 	rootCmd.AddCommand(newFormatCmd())
 
+	// add the state commands
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(newStateListCmd())
+	stateCmd.AddCommand(newStateShowCmd())
+	stateCmd.AddCommand(newStateRmCmd())
+	stateCmd.AddCommand(newStateMvCmd())
+	stateCmd.AddCommand(newStateEncryptCmd())
+	stateCmd.AddCommand(newStateUnlockCmd())
+	stateCmd.AddCommand(newStateHistoryCmd())
+	stateCmd.AddCommand(newStateDiffCmd())
+	stateCmd.AddCommand(newStateRollbackCmd(engine))
+
+	// add the workspace commands
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(newWorkspaceListCmd())
+	workspaceCmd.AddCommand(newWorkspaceNewCmd())
+	workspaceCmd.AddCommand(newWorkspaceSelectCmd())
+	workspaceCmd.AddCommand(newWorkspaceDeleteCmd())
+
 	rootCmd.SilenceErrors = true
 
 	// set a pre run function to show the changelog
 	rootCmd.PersistentFlags().Bool("non-interactive", false, "Run in non-interactive mode")
+	rootCmd.PersistentFlags().Bool("show-sensitive", false, "Show sensitive values such as passwords and private keys in the output and logs")
+	rootCmd.PersistentFlags().String("workspace", "", "Workspace to use for this command, overrides the selected workspace and the JUMPPAD_WORKSPACE environment variable")
+	rootCmd.PersistentFlags().String("trace-file", "", "Write OpenTelemetry spans for the engine and provider operations to the given file as JSON, spans are exported with OTLP when OTEL_EXPORTER_OTLP_ENDPOINT is set")
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		logger.ShowSensitiveValues(showSensitive(cmd))
+
+		// set the workspace before anything reads the state
+		if ws, _ := cmd.Flags().GetString("workspace"); ws != "" {
+			if _, err := utils.ValidateName(ws); err != nil {
+				return fmt.Errorf("invalid workspace %s: %s", ws, err)
+			}
+
+			if !utils.WorkspaceExists(ws) {
+				return fmt.Errorf("workspace %s does not exist, create it with 'jumppad workspace new %s'", ws, ws)
+			}
+
+			os.Setenv(utils.WorkspaceEnvVar, ws)
+		}
+
+		// ensure the workspace from the environment or the selected workspace
+		// is valid before it is used in paths and resource names
+		if _, err := utils.ResolveWorkspace(); err != nil {
+			return err
+		}
+
+		traceFile, _ := cmd.Flags().GetString("trace-file")
+		shutdown, err := telemetry.Setup(cmd.Context(), traceFile, version)
+		if err != nil {
+			return err
+		}
+
+		shutdownTracing = shutdown
+
 		ni, _ := cmd.Flags().GetBool("non-interactive")
 		if ni {
 			return nil
@@ -107,7 +192,7 @@ func Execute(v, c, d string) error {
 		// replace """ with ``` in changelog
 		changes = strings.ReplaceAll(changes, `"""`, "```")
 
-		err := cl.Show(changes, changesVersion, false)
+		err = cl.Show(changes, changesVersion, false)
 		if err != nil {
 			showErr(err)
 			return err
@@ -127,13 +212,39 @@ func Execute(v, c, d string) error {
 
 	err := rootCmd.Execute()
 
-	if err != nil {
+	// flush any spans before exiting
+	if shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		shutdownTracing(ctx)
+	}
+
+	// the output for commands that exit with a code has already been shown
+	ec := &ExitCodeError{}
+	if err != nil && !errors.As(err, &ec) {
 		showErr(err)
 	}
 
 	return err
 }
 
+// showSensitive returns true when sensitive values should not be redacted
+func showSensitive(cmd *cobra.Command) bool {
+	show, _ := cmd.Flags().GetBool("show-sensitive")
+	return show
+}
+
+// ExitCodeError is returned when a command should exit with the given code,
+// any output has already been written so the error is not shown
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", e.Code)
+}
+
 func showErr(err error) {
 	fmt.Println("")
 	fmt.Println(err)
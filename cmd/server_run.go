@@ -93,8 +93,7 @@ func newConnectorRunCommand() *cobra.Command {
 			l.Info("Starting gRPC server", "bind_addr", grpcBindAddr)
 			lis, err := net.Listen("tcp", grpcBindAddr)
 			if err != nil {
-				l.Error("Unable to list on address", "bind_addr", grpcBindAddr)
-				os.Exit(1)
+				return fmt.Errorf("unable to listen on address %s: %s", grpcBindAddr, err)
 			}
 
 			// start the gRPC server
@@ -107,8 +106,7 @@ func newConnectorRunCommand() *cobra.Command {
 			err = httpS.Serve()
 			l.Info("Started")
 			if err != nil {
-				l.Error("Unable to start HTTP server", "error", err)
-				os.Exit(1)
+				return fmt.Errorf("unable to start HTTP server: %s", err)
 			}
 
 			// start the API server
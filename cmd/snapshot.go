@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore snapshots of the environment",
+	Long: `Save and restore snapshots of the environment.
+
+A snapshot is a single tarball containing the state, the Docker volumes mounted
+into the resources, the data folder and the list of images used by the resources
+in the active workspace.
+
+The data written by Kubernetes and Nomad clusters is stored in the cluster
+containers and is not included, only the volumes mounted into the clusters are
+saved. The cached images are not included, the images are pulled when the
+snapshot is restored`,
+}
+
+func newSnapshotSaveCmd(ct container.ContainerTasks, tg *tar.TarGz, l logger.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "save [file]",
+		Short: "Save a snapshot of the environment to a file",
+		Long: `Save a snapshot of the environment to a file.
+
+The snapshot contains the state and the data in the volumes. When state
+encryption is enabled with 'jumppad state encrypt' the snapshot is encrypted
+with the state key and can only be restored with the same key, otherwise any
+sensitive values are stored in plain text and the snapshot file should be
+stored securely.
+
+Volumes are copied while the resources are running, run 'jumppad stop' before
+saving the snapshot to ensure the data in the volumes is consistent`,
+		Example: `
+  # Save a snapshot of the environment
+  jumppad snapshot save ./env.tar.gz
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Create(args[0])
+			if err != nil {
+				return fmt.Errorf("unable to create snapshot file: %s", err)
+			}
+
+			m, err := snapshot.New(ct, tg, l).Save(f)
+			f.Close()
+
+			if err != nil {
+				// do not leave a partial snapshot
+				os.Remove(args[0])
+				return fmt.Errorf("unable to save snapshot: %s", err)
+			}
+
+			cmd.Printf("Saved snapshot to %s with %d volumes and %d images\n", args[0], len(m.Volumes), len(m.Images))
+			if config.StateEncryptionEnabled() {
+				cmd.Println("The snapshot is encrypted with the state key")
+			}
+
+			return nil
+		},
+	}
+}
+
+func newSnapshotRestoreCmd(e jumppad.Engine, ct container.ContainerTasks, tg *tar.TarGz, l logger.Logger) *cobra.Command {
+	var output string
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore [file]",
+		Short: "Restore a snapshot of the environment from a file",
+		Long: `Restore a snapshot of the environment from a file.
+
+The data folder and the Docker volumes are restored and the images are pulled,
+the state from the snapshot is then applied to the active workspace. Resources
+that are not in the snapshot are destroyed, resources that have changed are
+recreated and resources that do not exist are created.
+
+The resources in the active workspace must be stopped with 'jumppad stop' or
+removed with 'jumppad down' before a snapshot is restored`,
+		Example: `
+  # Restore a snapshot of the environment
+  jumppad snapshot restore ./env.tar.gz
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("unable to open snapshot file: %s", err)
+			}
+			defer f.Close()
+
+			// hold the lock until the state from the snapshot is applied
+			unlock, err := config.LockState()
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			m, c, err := snapshot.New(ct, tg, l).Restore(f)
+			if err != nil {
+				return fmt.Errorf("unable to restore snapshot: %s", err)
+			}
+
+			cmd.Println("Restoring snapshot from", m.Created.Format("2006-01-02 15:04:05"), " -- press ctrl c to cancel")
+			cmd.Println("")
+
+			return runCancellable(cmd, e, l, output, func(ctx context.Context) error {
+				_, err := e.ApplyState(ctx, c)
+				if err != nil {
+					return fmt.Errorf("unable to restore snapshot: %s", err)
+				}
+
+				return nil
+			})
+		},
+	}
+
+	restoreCmd.Flags().StringVarP(&output, "output", "", outputText, "Output format for progress, text writes log output, tree renders a live progress tree, jsonl streams events as JSON lines")
+
+	return restoreCmd
+}
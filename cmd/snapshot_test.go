@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	cmocks "github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	enginemocks "github.com/jumppad-labs/jumppad/pkg/jumppad/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSnapshot(t *testing.T) (*enginemocks.Engine, *cmocks.ContainerTasks) {
+	testutils.SetupState(t, snapshotCmdState)
+
+	me := &enginemocks.Engine{}
+	me.On("Events").Return(events.NewBus())
+	me.On("ApplyState", mock.Anything, mock.Anything).Return(nil, nil)
+
+	ct := &cmocks.ContainerTasks{}
+	ct.On("CopyFromVolume", mock.Anything, mock.Anything).Return(nil)
+	ct.On("CopyDirectoryToVolume", mock.Anything, mock.Anything).Return(nil)
+	ct.On("PullImage", mock.Anything, false).Return(nil)
+
+	return me, ct
+}
+
+func TestSnapshotSaveAndRestoreAppliesState(t *testing.T) {
+	me, ct := setupSnapshot(t)
+	file := filepath.Join(t.TempDir(), "env.tar.gz")
+
+	c := newSnapshotSaveCmd(ct, &tar.TarGz{}, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetArgs([]string{file})
+
+	err := c.Execute()
+	require.NoError(t, err)
+	require.FileExists(t, file)
+
+	// restore to an empty environment
+	os.Remove(utils.StatePath())
+
+	c = newSnapshotRestoreCmd(me, ct, &tar.TarGz{}, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetArgs([]string{file})
+
+	err = c.Execute()
+	require.NoError(t, err)
+
+	me.AssertCalled(t, "ApplyState", mock.Anything, mock.MatchedBy(func(c *hclconfig.Config) bool {
+		_, err := c.FindResource("resource.container.consul")
+		return err == nil
+	}))
+}
+
+func TestSnapshotSaveWithErrorRemovesFile(t *testing.T) {
+	_, ct := setupSnapshot(t)
+	testutils.RemoveOn(&ct.Mock, "CopyFromVolume")
+	ct.On("CopyFromVolume", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	file := filepath.Join(t.TempDir(), "env.tar.gz")
+
+	c := newSnapshotSaveCmd(ct, &tar.TarGz{}, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetArgs([]string{file})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "boom")
+	require.NoFileExists(t, file)
+}
+
+func TestSnapshotRestoreWithRunningResourcesDoesNotRestoreVolumes(t *testing.T) {
+	me, ct := setupSnapshot(t)
+	file := filepath.Join(t.TempDir(), "env.tar.gz")
+
+	c := newSnapshotSaveCmd(ct, &tar.TarGz{}, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetArgs([]string{file})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	c = newSnapshotRestoreCmd(me, ct, &tar.TarGz{}, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetArgs([]string{file})
+
+	err = c.Execute()
+	require.ErrorContains(t, err, "resource.container.consul are running")
+
+	ct.AssertNotCalled(t, "CopyDirectoryToVolume", mock.Anything, mock.Anything)
+	me.AssertNotCalled(t, "ApplyState", mock.Anything, mock.Anything)
+
+	_, err = os.Stat(utils.StateLockPath())
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestSnapshotRestoreWithInvalidFileDoesNotApplyState(t *testing.T) {
+	me, ct := setupSnapshot(t)
+	os.Remove(utils.StatePath())
+
+	file := filepath.Join(t.TempDir(), "env.tar.gz")
+	os.WriteFile(file, []byte("not a snapshot"), 0644)
+
+	c := newSnapshotRestoreCmd(me, ct, &tar.TarGz{}, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetArgs([]string{file})
+
+	err := c.Execute()
+	require.Error(t, err)
+	me.AssertNotCalled(t, "ApplyState", mock.Anything, mock.Anything)
+}
+
+var snapshotCmdState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "name": "consul",
+        "properties": {
+          "status": "created"
+        },
+        "type": "container"
+      },
+      "image": {
+        "name": "consul:1.16"
+      },
+      "volumes": [
+        {
+          "source": "data",
+          "destination": "/data",
+          "type": "volume"
+        }
+      ]
+  }
+  ]
+}
+`
@@ -0,0 +1,453 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Inspect and manage the state",
+	Long:  `Inspect and manage the state for the active workspace`,
+}
+
+func newStateUnlockCmd() *cobra.Command {
+	var force bool
+
+	unlockCmd := &cobra.Command{
+		Use:   "unlock",
+		Short: "Remove the lock from the state",
+		Long: `Remove the lock from the state.
+
+The state is locked while jumppad modifies it, should a process exit
+without releasing the lock it is removed automatically by the next command when
+the process is no longer running on this machine.
+
+Locks held by running processes, or by processes on other machines, can only be
+removed with the --force flag`,
+		Example: `
+  # Remove the lock from the state
+  jumppad state unlock --force
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			l, err := config.ReadStateLock()
+			if err != nil {
+				if os.IsNotExist(err) {
+					cmd.Println("State is not locked")
+					return nil
+				}
+
+				if !force {
+					return err
+				}
+			}
+
+			if l != nil && !l.IsStale() && !force {
+				return fmt.Errorf("state is locked by process %d on %s running '%s', use --force to remove the lock", l.PID, l.Hostname, l.Command)
+			}
+
+			err = config.ForceUnlockState()
+			if err != nil {
+				return err
+			}
+
+			cmd.Println("State unlocked")
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+
+	unlockCmd.Flags().BoolVarP(&force, "force", "", false, "Remove the lock even when the process holding it is still running")
+
+	return unlockCmd
+}
+
+func newStateHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "List the snapshots in the state history",
+		Long: `List the snapshots in the state history.
+
+A snapshot of the state is taken every time a command modifies the state,
+snapshots can be compared with 'jumppad state diff' and restored with
+'jumppad state rollback'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snapshots, err := config.ListStateHistory()
+			if err != nil {
+				return err
+			}
+
+			if len(snapshots) == 0 {
+				cmd.Println("No state history")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "ID\tCREATED\tRESOURCES\tCOMMAND")
+
+			for _, s := range snapshots {
+				count := "?"
+				if c, err := s.Config(); err == nil {
+					count = strconv.Itoa(len(c.Resources))
+				}
+
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.ID, s.Created.Format("2006-01-02 15:04:05"), count, s.Command)
+			}
+
+			return w.Flush()
+		},
+		SilenceUsage: true,
+	}
+}
+
+func newStateDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff [from] [to]",
+		Short: "Show the differences between two snapshots",
+		Long:  "Show the resources that were added, removed, or changed between two snapshots in the state history",
+		Example: `
+  # Show the changes between snapshot 3 and snapshot 5
+  jumppad state diff 3 5
+	`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, err := loadSnapshotConfig(args[0])
+			if err != nil {
+				return err
+			}
+
+			to, err := loadSnapshotConfig(args[1])
+			if err != nil {
+				return err
+			}
+
+			diff, err := from.Diff(to)
+			if err != nil {
+				return fmt.Errorf("unable to compare snapshots: %s", err)
+			}
+
+			changed := append(diff.ParseUpdated, diff.ProcessedUpdated...)
+
+			printStateDiff(cmd, diff.Added, greenIcon.Render("+"))
+			printStateDiff(cmd, diff.Removed, redIcon.Render("-"))
+			printStateDiff(cmd, changed, yellowIcon.Render("~"))
+
+			cmd.Println()
+			cmd.Println(whiteText.Render(fmt.Sprintf("Added: %d  Removed: %d  Changed: %d", len(diff.Added), len(diff.Removed), len(changed))))
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
+
+func newStateRollbackCmd(e jumppad.Engine) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback [id]",
+		Short: "Restore a snapshot from the state history",
+		Long: `Restore a snapshot from the state history.
+
+Resources that are not in the snapshot are destroyed, resources that have
+changed are recreated, and resources that no longer exist are created`,
+		Example: `
+  # Restore the state to snapshot 3
+  jumppad state rollback 3
+	`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := loadSnapshotConfig(args[0])
+			if err != nil {
+				return err
+			}
+
+			cmd.Println("Rolling back state to snapshot", args[0], " -- press ctrl c to cancel")
+			cmd.Println("")
+
+			// trap ctrl c
+			done := make(chan os.Signal, 1)
+			signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			go func() {
+				<-done // Will block here until user hits ctrl+c
+
+				// cancel the context
+				cancel()
+			}()
+
+			_, err = e.ApplyState(ctx, c)
+			if err != nil {
+				return fmt.Errorf("unable to roll back state: %s", err)
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
+
+func newStateListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the resources in the state",
+		Long:  "List the resources in the state for the active workspace along with their status",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.LoadState()
+			if err != nil {
+				return fmt.Errorf("unable to load state: %s", err)
+			}
+
+			res := []types.Resource{}
+			for _, r := range c.Resources {
+				if r.Metadata().Type == resources.TypeRoot {
+					continue
+				}
+
+				res = append(res, r)
+			}
+
+			if len(res) == 0 {
+				cmd.Println("No resources in the state")
+				return nil
+			}
+
+			sort.Slice(res, func(i, j int) bool {
+				return res[i].Metadata().ID < res[j].Metadata().ID
+			})
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "ID\tTYPE\tSTATUS")
+
+			for _, r := range res {
+				status, _ := r.Metadata().Properties[constants.PropertyStatus].(string)
+				if r.GetDisabled() {
+					status = "disabled"
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\n", r.Metadata().ID, r.Metadata().Type, status)
+			}
+
+			return w.Flush()
+		},
+		SilenceUsage: true,
+	}
+}
+
+func newStateShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show [id]",
+		Short: "Show a resource in the state",
+		Long:  "Show the attributes of a resource in the state as JSON, sensitive values are redacted unless the --show-sensitive flag is set",
+		Example: `
+  # Show the container db
+  jumppad state show resource.container.db
+	`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.LoadState()
+			if err != nil {
+				return fmt.Errorf("unable to load state: %s", err)
+			}
+
+			r, err := c.FindResource(args[0])
+			if err != nil {
+				return fmt.Errorf(`resource "%s" does not exist in the state`, args[0])
+			}
+
+			var out any = r
+			if !showSensitive(cmd) {
+				out, err = config.RedactResource(r)
+				if err != nil {
+					return err
+				}
+			}
+
+			d, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				return fmt.Errorf("unable to output resource as JSON: %s", err)
+			}
+
+			cmd.Println(string(d))
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
+
+func newStateRmCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm [id]",
+		Short: "Remove a resource from the state without destroying it",
+		Long: `Remove a resource from the state without destroying it.
+
+The running objects for the resource are left untouched and are no longer
+managed by jumppad. When the resource is a module all the resources in the
+module are removed`,
+		Example: `
+  # Stop managing the container db
+  jumppad state rm resource.container.db
+	`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return editState(func(c *hclconfig.Config) error {
+				removed, err := config.RemoveStateResource(c, args[0])
+				if err != nil {
+					return err
+				}
+
+				for _, r := range removed {
+					cmd.Printf("%s%s\n", redIcon.Render("-"), r.Metadata().ID)
+				}
+
+				return nil
+			})
+		},
+		SilenceUsage: true,
+	}
+}
+
+func newStateMvCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mv [from] [to]",
+		Short: "Change the id of a resource in the state",
+		Long: `Change the id of a resource in the state.
+
+Use mv after renaming a resource or moving it into a module so that the next
+'jumppad up' does not destroy and recreate the resource. References to the
+resource from other resources in the state are updated`,
+		Example: `
+  # Rename the container db to postgres
+  jumppad state mv resource.container.db resource.container.postgres
+
+  # Move the container db into the module data
+  jumppad state mv resource.container.db module.data.resource.container.db
+	`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return editState(func(c *hclconfig.Config) error {
+				r, err := config.MoveStateResource(c, args[0], args[1])
+				if err != nil {
+					return err
+				}
+
+				cmd.Printf("%s%s => %s\n", yellowIcon.Render("~"), args[0], r.Metadata().ID)
+
+				return nil
+			})
+		},
+		SilenceUsage: true,
+	}
+}
+
+func newStateEncryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt the sensitive values in the state",
+		Long: `Encrypt the sensitive values in the state.
+
+A key is created in the jumppad home folder and the sensitive values in the
+state, such as passwords and private keys, are encrypted with it. Once the key
+exists the sensitive values in the state for all workspaces are encrypted when
+the state is written.
+
+The key is required to read the state, if the key is removed the state can no
+longer be used`,
+		Example: `
+  # Encrypt the sensitive values in the state
+  jumppad state encrypt
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := config.EncryptState()
+			if err != nil {
+				return fmt.Errorf("unable to encrypt state: %s", err)
+			}
+
+			cmd.Printf("Sensitive values in the state are encrypted with the key %s\n", utils.StateKeyPath())
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
+
+// editState loads the state, calls f, and saves the modified state, the state
+// is locked while it is modified
+func editState(f func(c *hclconfig.Config) error) error {
+	unlock, err := config.LockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	c, err := config.LoadState()
+	if err != nil {
+		return fmt.Errorf("unable to load state: %s", err)
+	}
+
+	err = f(c)
+	if err != nil {
+		return err
+	}
+
+	err = config.SaveState(c)
+	if err != nil {
+		return fmt.Errorf("unable to save state: %s", err)
+	}
+
+	return nil
+}
+
+func loadSnapshotConfig(id string) (*hclconfig.Config, error) {
+	i, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot id %s, the id must be a number, use 'jumppad state history' to list the snapshots", id)
+	}
+
+	s, err := config.LoadStateSnapshot(i)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load snapshot %d: %s", i, err)
+	}
+
+	return s.Config()
+}
+
+func printStateDiff(cmd *cobra.Command, res []types.Resource, icon string) {
+	ids := []string{}
+	for _, r := range res {
+		if r.Metadata().Type == resources.TypeRoot || r.Metadata().Type == resources.TypeModule {
+			continue
+		}
+
+		ids = append(ids, r.Metadata().ID)
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		cmd.Printf("%s%s\n", icon, id)
+	}
+}
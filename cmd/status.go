@@ -2,9 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/hokaccha/go-prettyjson"
+	"github.com/jumppad-labs/hclconfig"
 	"github.com/jumppad-labs/hclconfig/resources"
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/config"
@@ -42,21 +42,26 @@ var statusCmd = &cobra.Command{
 	Short: "Show the status of the current resources",
 	Long:  `Show the status of the current resources`,
 	Args:  cobra.NoArgs,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// load the resources from state
 
 		cfg, err := config.LoadState()
 		if err != nil {
-			fmt.Println(err)
-			fmt.Printf("Unable to read state file")
-			os.Exit(1)
+			return fmt.Errorf("unable to read state file: %s", err)
 		}
 
 		if jsonFlag {
-			s, err := prettyjson.Marshal(cfg)
+			var out any = cfg
+			if !showSensitive(cmd) {
+				out, err = redactConfig(cfg)
+				if err != nil {
+					return fmt.Errorf("unable to output state as JSON: %s", err)
+				}
+			}
+
+			s, err := prettyjson.Marshal(out)
 			if err != nil {
-				fmt.Println("Unable to output state as JSON", err)
-				os.Exit(1)
+				return fmt.Errorf("unable to output state as JSON: %s", err)
 			}
 
 			fmt.Println(string(s))
@@ -68,6 +73,7 @@ var statusCmd = &cobra.Command{
 			failedCount := 0
 			disabledCount := 0
 			pendingCount := 0
+			stoppedCount := 0
 
 			// sort the resources
 			resourceMap := map[string][]types.Resource{}
@@ -102,6 +108,12 @@ var statusCmd = &cobra.Command{
 						case constants.StatusFailed:
 							status = redIcon.Render("✘")
 							failedCount++
+						case constants.StatusCancelled:
+							status = yellowIcon.Render("!")
+							pendingCount++
+						case constants.StatusStopped:
+							status = grayIcon.Render("■")
+							stoppedCount++
 						default:
 							pendingCount++
 						}
@@ -146,9 +158,16 @@ var statusCmd = &cobra.Command{
 			// fmt.Println()
 			// fmt.Println(grayIcon.Render("-") + grayText.Render("resource.container.frontend"))
 			fmt.Println()
-			fmt.Println(whiteText.Render(fmt.Sprintf("Pending: %d  Created: %d  Failed: %d  Disabled: %d", pendingCount, createdCount, failedCount, disabledCount)))
+			fmt.Println(whiteText.Render(fmt.Sprintf("Pending: %d  Created: %d  Stopped: %d  Failed: %d  Disabled: %d", pendingCount, createdCount, stoppedCount, failedCount, disabledCount)))
+
+			if !utils.IsDefaultWorkspace() {
+				fmt.Println(grayText.Render(fmt.Sprintf("Workspace: %s", utils.CurrentWorkspace())))
+			}
+
 			fmt.Println()
 		}
+
+		return nil
 	},
 }
 
@@ -156,3 +175,20 @@ func init() {
 	statusCmd.Flags().BoolVarP(&jsonFlag, "json", "", false, "Output the status as JSON")
 	statusCmd.Flags().StringVarP(&resourceType, "type", "", "", "Resource type used to filter status list")
 }
+
+// redactConfig returns the JSON representation of the config with the
+// sensitive values redacted
+func redactConfig(c *hclconfig.Config) (map[string]any, error) {
+	res := []map[string]any{}
+
+	for _, r := range c.Resources {
+		m, err := config.RedactResource(r)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, m)
+	}
+
+	return map[string]any{"resources": res}, nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/spf13/cobra"
+)
+
+func newStopCmd(e jumppad.Engine, l logger.Logger) *cobra.Command {
+	var output string
+
+	stopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the resources in the current state without destroying them",
+		Long: `Stop the resources in the current state without destroying them.
+
+Containers, sidecars, the nodes of Kubernetes and Nomad clusters, docs and the
+image cache are stopped in reverse dependency order and marked as stopped in the
+state. The containers and their data are kept, run 'jumppad start' or
+'jumppad up' to start the environment again`,
+		Example: `
+  # Stop the environment
+  jumppad stop
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Println("Stopping resources", " -- press ctrl c to cancel")
+			cmd.Println("")
+
+			return runCancellable(cmd, e, l, output, e.Stop)
+		},
+	}
+
+	stopCmd.Flags().StringVarP(&output, "output", "", outputText, "Output format for progress, text writes log output, tree renders a live progress tree, jsonl streams events as JSON lines")
+
+	return stopCmd
+}
+
+func newStartCmd(e jumppad.Engine, l logger.Logger) *cobra.Command {
+	var output string
+
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the resources that have been stopped with 'jumppad stop'",
+		Long: `Start the resources that have been stopped with 'jumppad stop'.
+
+Stopped resources are started in dependency order and marked as created in the
+state, the configuration is not read so changes to the configuration are not
+applied, use 'jumppad up' to start the environment and apply any changes`,
+		Example: `
+  # Start a stopped environment
+  jumppad start
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Println("Starting resources", " -- press ctrl c to cancel")
+			cmd.Println("")
+
+			return runCancellable(cmd, e, l, output, e.Start)
+		},
+	}
+
+	startCmd.Flags().StringVarP(&output, "output", "", outputText, "Output format for progress, text writes log output, tree renders a live progress tree, jsonl streams events as JSON lines")
+
+	return startCmd
+}
+
+// runCancellable renders the engine events in the selected output format
+// while f runs, the context passed to f is cancelled on ctrl-c
+func runCancellable(cmd *cobra.Command, e jumppad.Engine, l logger.Logger, output string, f func(ctx context.Context) error) error {
+	stopOutput, err := subscribeOutput(e.Events(), l, output, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err = f(ctx)
+	stopOutput()
+
+	return err
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	enginemocks "github.com/jumppad-labs/jumppad/pkg/jumppad/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStop(t *testing.T) *enginemocks.Engine {
+	me := &enginemocks.Engine{}
+	me.On("Events").Return(events.NewBus())
+	me.On("Stop", mock.Anything).Return(nil)
+	me.On("Start", mock.Anything).Return(nil)
+
+	return me
+}
+
+func TestStopStopsEngine(t *testing.T) {
+	me := setupStop(t)
+
+	c := newStopCmd(me, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetArgs([]string{})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	me.AssertCalled(t, "Stop", mock.Anything)
+	me.AssertNotCalled(t, "Start", mock.Anything)
+}
+
+func TestStopReturnsEngineError(t *testing.T) {
+	me := setupStop(t)
+	me.ExpectedCalls = nil
+	me.On("Events").Return(events.NewBus())
+	me.On("Stop", mock.Anything).Return(fmt.Errorf("boom"))
+
+	c := newStopCmd(me, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetArgs([]string{})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestStartStartsEngine(t *testing.T) {
+	me := setupStop(t)
+
+	c := newStartCmd(me, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetArgs([]string{})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	me.AssertCalled(t, "Start", mock.Anything)
+}
+
+func TestStartWithInvalidOutputReturnsError(t *testing.T) {
+	me := setupStop(t)
+
+	c := newStartCmd(me, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetArgs([]string{"--output", "xml"})
+
+	err := c.Execute()
+	require.Error(t, err)
+	me.AssertNotCalled(t, "Start", mock.Anything)
+}
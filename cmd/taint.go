@@ -2,11 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
-	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -18,32 +16,35 @@ var taintCmd = &cobra.Command{
 	jumppad taint resource.container.test
 	`,
 	Args: cobra.ArbitraryArgs,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			fmt.Println("The resource to taint must be specified as an argument")
-			os.Exit(1)
+			return fmt.Errorf("the resource to taint must be specified as an argument")
 		}
 
+		// hold the lock while the state is modified
+		unlock, err := config.LockState()
+		if err != nil {
+			return fmt.Errorf("unable to lock statefile: %s", err)
+		}
+		defer unlock()
+
 		cfg, err := config.LoadState()
 		if err != nil {
-			fmt.Println("Unable to load statefile, do you have a running blueprint?")
-			os.Exit(1)
+			return fmt.Errorf("unable to load statefile, do you have a running blueprint? %s", err)
 		}
 
 		r, err := cfg.FindResource(args[0])
 		if err != nil || r == nil {
-			fmt.Println("Unable to locate resource in the state", args[0])
-			os.Exit(1)
+			return fmt.Errorf("unable to locate resource in the state %s", args[0])
 		}
 
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusTainted
 
-		d, err := cfg.ToJSON()
+		err = config.SaveState(cfg)
 		if err != nil {
-			fmt.Println("Unable to save state", err)
-			os.Exit(1)
+			return fmt.Errorf("unable to save state: %s", err)
 		}
 
-		os.WriteFile(utils.StatePath(), d, os.ModePerm)
+		return nil
 	},
 }
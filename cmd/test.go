@@ -105,9 +105,7 @@ func newTestCmdFunc(
 			dontDestroy:   dontDestroy,
 		}
 
-		tr.start()
-
-		return nil
+		return tr.start()
 	}
 }
 
@@ -131,7 +129,7 @@ type CucumberRunner struct {
 }
 
 // Initialize the functional tests
-func (cr *CucumberRunner) start() {
+func (cr *CucumberRunner) start() error {
 	godog.BindFlags("godog.", flag.CommandLine, opts)
 	flag.Parse()
 
@@ -147,8 +145,7 @@ func (cr *CucumberRunner) start() {
 	var err error
 	cr.basePath, err = filepath.Abs(cr.args[0])
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	cr.testPath = filepath.Join(cr.basePath, cr.testFolder)
@@ -162,7 +159,11 @@ func (cr *CucumberRunner) start() {
 		Options:             opts,
 	}.Run()
 
-	os.Exit(status)
+	if status != 0 {
+		return &ExitCodeError{Code: status}
+	}
+
+	return nil
 }
 
 func (cr *CucumberRunner) initializeSuite(ctx *godog.ScenarioContext) {
@@ -176,8 +177,10 @@ func (cr *CucumberRunner) initializeSuite(ctx *godog.ScenarioContext) {
 
 		cl := logger.NewLogger(sb, logger.LogLevelDebug)
 
-		cli, _ := clients.GenerateClients(cl)
-		engine, err := createEngine(cl, cli)
+		pool := clients.NewPool(cl)
+		cli, _ := pool.Clients()
+
+		engine, err := createEngine(cl, pool)
 		if err != nil {
 			fmt.Printf("Unable to setup tests: %s\n", err)
 			return ctx, err
@@ -226,7 +229,7 @@ func (cr *CucumberRunner) initializeSuite(ctx *godog.ScenarioContext) {
 		err = dest.Execute()
 		if err != nil {
 			fmt.Println(sb.String())
-			os.Exit(1)
+			return ctx, fmt.Errorf("unable to destroy resources: %s", err)
 		}
 
 		return ctx, nil
@@ -269,6 +272,13 @@ func (cr *CucumberRunner) iRunApplyAtPath(path string) error {
 
 	// re-use the run command
 	noCache := false
+	targets := []string{}
+	outputFormat := outputText
+	parallelism := 0
+	typeLimits := []string{}
+	resume := false
+	gracePeriod := jumppad.DefaultGracePeriod
+	keepGoing := false
 	rc := newRunCmdFunc(
 		cr.e,
 		cr.cli.ContainerTasks,
@@ -281,6 +291,13 @@ func (cr *CucumberRunner) iRunApplyAtPath(path string) error {
 		&noCache,
 		&cr.variables,
 		&cr.variablesFile,
+		&targets,
+		&outputFormat,
+		&parallelism,
+		&typeLimits,
+		&resume,
+		&gracePeriod,
+		&keepGoing,
 		cr.l,
 	)
 
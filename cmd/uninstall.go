@@ -15,30 +15,29 @@ var uninstallCmd = &cobra.Command{
 	Long:                  `Uninstall jumppad`,
 	DisableFlagsInUseLine: true,
 	Args:                  cobra.NoArgs,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// remove the config
 		fmt.Println("Removing Shipyard configuration from", utils.JumppadHome())
 		err := os.RemoveAll(utils.JumppadHome())
 		if err != nil {
-			fmt.Println("Error: Unable to remove jumppad configuration", err)
-			os.Exit(1)
+			return fmt.Errorf("unable to remove jumppad configuration: %s", err)
 		}
 
 		// remove the binary
 		ep, _ := os.Executable()
 		cf, err := filepath.Abs(ep)
 		if err != nil {
-			fmt.Println("Error: Unable to remove jumppad application", err)
-			os.Exit(1)
+			return fmt.Errorf("unable to remove jumppad application: %s", err)
 		}
 		fmt.Println("Removing jumppad application from", cf)
 		err = os.Remove(cf)
 		if err != nil {
-			fmt.Println("Error: Unable to remove jumppad application", err)
-			os.Exit(1)
+			return fmt.Errorf("unable to remove jumppad application: %s", err)
 		}
 
 		fmt.Println("")
 		fmt.Println("jumppad successfully uninstalled")
+
+		return nil
 	},
 }
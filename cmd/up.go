@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jumppad-labs/hclconfig"
 	"github.com/jumppad-labs/hclconfig/resources"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients/connector"
@@ -21,12 +23,14 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/http"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/clients/system"
+	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/blueprint"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/docs"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/ingress"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
 	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/spf13/cobra"
 
@@ -39,6 +43,13 @@ func newRunCmd(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, h
 	var noCache bool
 	var variables []string
 	var variablesFile string
+	var targets []string
+	var output string
+	var parallelism int
+	var typeLimits []string
+	var resume bool
+	var gracePeriod time.Duration
+	var keepGoing bool
 
 	runCmd := &cobra.Command{
 		Use:   "up [file] | [directory]",
@@ -53,9 +64,21 @@ func newRunCmd(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, h
 
   # Create resources from a blueprint in GitHub
   jumppad up github.com/jumppad-labs/blueprints/kubernetes-vault
+
+  # Create only a single container and the resources it depends on
+  jumppad up --target resource.container.api ./
+
+  # Create at most four resources and one Kubernetes cluster at a time
+  jumppad up --parallelism 4 --parallelism-type k8s_cluster=1 ./
+
+  # Continue an apply that was cancelled with ctrl c
+  jumppad up --resume
+
+  # Create every resource that does not depend on a failed resource
+  jumppad up --keep-going ./
 	`,
 		Args:         cobra.ArbitraryArgs,
-		RunE:         newRunCmdFunc(e, dt, bp, hc, bc, cc, &noOpen, &force, &noCache, &variables, &variablesFile, l),
+		RunE:         newRunCmdFunc(e, dt, bp, hc, bc, cc, &noOpen, &force, &noCache, &variables, &variablesFile, &targets, &output, &parallelism, &typeLimits, &resume, &gracePeriod, &keepGoing, l),
 		SilenceUsage: true,
 	}
 
@@ -64,11 +87,18 @@ func newRunCmd(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, h
 	runCmd.Flags().BoolVarP(&noCache, "no-cache", "", false, "When set to true Jumppad will not create the image cache proxy. Clusters will pull images directly from registries")
 	runCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
 	runCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	runCmd.Flags().StringVarP(&output, "output", "", outputText, "Output format for progress, text writes log output, tree renders a live progress tree, jsonl streams events as JSON lines")
+	runCmd.Flags().StringSliceVarP(&targets, "target", "", nil, "Only create the given resource and the resources it depends on, e.g. --target resource.container.api. Can be specified multiple times")
+	runCmd.Flags().IntVarP(&parallelism, "parallelism", "", 0, "Maximum number of resources to create at the same time, 0 creates all independent resources at the same time")
+	runCmd.Flags().StringSliceVarP(&typeLimits, "parallelism-type", "", nil, "Maximum number of resources of a type to create at the same time, e.g. --parallelism-type k8s_cluster=1. Can be specified multiple times")
+	runCmd.Flags().BoolVarP(&resume, "resume", "", false, "Continue the last apply that was cancelled, using the same path, variables and targets")
+	runCmd.Flags().BoolVarP(&keepGoing, "keep-going", "", false, "Continue to create resources that do not depend on a failed resource and report all failures at the end, by default no new resources are started after a failure")
+	runCmd.Flags().DurationVarP(&gracePeriod, "grace-period", "", jumppad.DefaultGracePeriod, "Time resources that are being created are given to finish when the apply is cancelled")
 
 	return runCmd
 }
 
-func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, hc http.HTTP, bc system.System, cc connector.Connector, noOpen *bool, force *bool, noCache *bool, variables *[]string, variablesFile *string, l logger.Logger) func(cmd *cobra.Command, args []string) error {
+func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, hc http.HTTP, bc system.System, cc connector.Connector, noOpen *bool, force *bool, noCache *bool, variables *[]string, variablesFile *string, targets *[]string, output *string, parallelism *int, typeLimits *[]string, resume *bool, gracePeriod *time.Duration, keepGoing *bool, l logger.Logger) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		// create the shipyard and sub folders in the users home directory
 		utils.CreateFolders()
@@ -96,6 +126,19 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 			}
 		}
 
+		// limit the number of resources created at the same time
+		if *parallelism != 0 || len(*typeLimits) > 0 {
+			tl, err := jumppad.ParseTypeLimits(*typeLimits)
+			if err != nil {
+				return err
+			}
+
+			err = e.SetParallelism(jumppad.Parallelism{Limit: *parallelism, TypeLimits: tl})
+			if err != nil {
+				return err
+			}
+		}
+
 		// check the variables file exists
 		if variablesFile != nil && *variablesFile != "" {
 			if _, err := os.Stat(*variablesFile); err != nil {
@@ -106,6 +149,32 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 			variablesFile = &vf
 		}
 
+		applyTargets := *targets
+
+		// resume the last cancelled apply using the same arguments
+		if *resume {
+			if len(args) > 0 || len(*variables) > 0 || *variablesFile != "" || len(*targets) > 0 {
+				return fmt.Errorf("--resume uses the path, variables and targets of the cancelled apply and can not be combined with them")
+			}
+
+			ri, err := jumppad.LoadResumeInfo()
+			if err != nil {
+				return err
+			}
+
+			if ri == nil {
+				return fmt.Errorf("there is no cancelled apply to resume")
+			}
+
+			args = []string{ri.Path}
+			vars = ri.Variables
+			variablesFile = &ri.VariablesFile
+			applyTargets = ri.Targets
+		}
+
+		e.SetGracePeriod(*gracePeriod)
+		e.SetKeepGoing(*keepGoing)
+
 		// create the certificates for the connector
 		if cb, err := cc.GetLocalCertBundle(utils.CertsDir("")); err != nil || cb == nil {
 			// generate certs
@@ -157,16 +226,28 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 			}
 		}
 
+		// render the engine events in the selected format
+		stopOutput, err := subscribeOutput(e.Events(), l, *output, cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+
+		// record the time taken for each resource for the summary
+		timings := jumppad.NewTimingRecorder()
+		stopTimings := e.Events().Subscribe(timings.Handle)
+
 		// update status every 30s to let people know we are still running
 		statusUpdate := time.NewTicker(15 * time.Second)
 		startTime := time.Now()
 
-		go func() {
-			for range statusUpdate.C {
-				elapsedTime := time.Since(startTime).Seconds()
-				l.Info(fmt.Sprintf("Please wait, still creating resources [Elapsed Time: %f]", elapsedTime))
-			}
-		}()
+		if *output == outputText {
+			go func() {
+				for range statusUpdate.C {
+					elapsedTime := time.Since(startTime).Seconds()
+					l.Info(fmt.Sprintf("Please wait, still creating resources [Elapsed Time: %f]", elapsedTime))
+				}
+			}()
+		}
 
 		// trap ctrl c
 		done := make(chan os.Signal, 1)
@@ -178,22 +259,55 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 		go func() {
 			<-done // Will block here until user hits ctrl+c
 
+			// stop trapping the signal so that pressing ctrl c again exits
+			// immediately
+			signal.Stop(done)
+
+			l.Info("Cancelling, waiting for resources that are being created to finish -- press ctrl c again to exit immediately", "grace_period", *gracePeriod)
+
 			// cancel the context
 			cancel()
 		}()
 
-		config, err := e.ApplyWithVariables(ctx, dst, vars, *variablesFile)
+		var cfg *hclconfig.Config
+
+		if len(applyTargets) > 0 {
+			cfg, err = e.ApplyTargets(ctx, dst, vars, *variablesFile, applyTargets)
+		} else {
+			cfg, err = e.ApplyWithVariables(ctx, dst, vars, *variablesFile)
+		}
+
+		stopOutput()
+		stopTimings()
+
+		if errors.Is(err, jumppad.ErrCancelled) {
+			return fmt.Errorf("%s, run jumppad up --resume to continue", err)
+		}
+
+		// list every failure rather than the parser errors, failures are
+		// published as events when streaming
+		var ae *jumppad.ApplyError
+		if errors.As(err, &ae) && *output != outputJSONL {
+			printFailureReport(cmd.OutOrStdout(), ae)
+			return fmt.Errorf("unable to create %d resources", len(ae.Failures))
+		}
+
 		if err != nil {
 			return err
 		}
 
+		// the summary is not written when streaming events
+		if *output != outputJSONL {
+			printTimingSummary(cmd.OutOrStdout(), timings.Summary(cfg, 5))
+		}
+
 		// do not open the browser windows
 		if !*noOpen {
 
 			browserList := []string{}
 			checkDuration := 30 * time.Second
 
-			for _, r := range config.Resources {
+			for _, r := range cfg.Resources {
 				switch v := r.(type) {
 				case *container.Container:
 					for _, p := range v.Ports {
@@ -251,7 +365,7 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 
 		// if we have a blueprint show the header
 		var b *blueprint.Blueprint
-		bps, _ := config.FindResourcesByType(blueprint.TypeBlueprint)
+		bps, _ := cfg.FindResourcesByType(blueprint.TypeBlueprint)
 		for _, bp := range bps {
 			// pick the first blueprint in the root
 			if bp.Metadata().Module == "" {
@@ -260,7 +374,8 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 			}
 		}
 
-		if b != nil {
+		// the blueprint details are not written when streaming events
+		if b != nil && *output != outputJSONL {
 			cmd.Println("")
 			cmd.Println("########################################################")
 			cmd.Println("")
@@ -297,7 +412,12 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 				format := fmt.Sprintf(" * %%%ds: %%s\n", maxLen)
 
 				for _, o := range outputs {
-					fmt.Printf(format, o.Meta.Name, o.Value)
+					var value any = o.Value
+					if o.Meta.Properties[constants.PropertySensitive] == true && !showSensitive(cmd) {
+						value = config.SensitiveValue
+					}
+
+					cmd.Printf(format, o.Meta.Name, value)
 				}
 
 				cmd.Println("")
@@ -9,20 +9,25 @@ import (
 	"time"
 
 	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
 	hcltypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	conmock "github.com/jumppad-labs/jumppad/pkg/clients/connector/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/clients/connector/types"
 	cmock "github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
 	gettermock "github.com/jumppad-labs/jumppad/pkg/clients/getter/mocks"
 	httpmock "github.com/jumppad-labs/jumppad/pkg/clients/http/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	systemmock "github.com/jumppad-labs/jumppad/pkg/clients/system/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/blueprint"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/docs"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/ingress"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
 	enginemocks "github.com/jumppad-labs/jumppad/pkg/jumppad/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/jumppad-labs/jumppad/testutils"
@@ -87,7 +92,12 @@ func setupRun(t *testing.T) (*cobra.Command, *runMocks) {
 	mockEngine := &enginemocks.Engine{}
 	mockEngine.On("ParseConfigWithVariables", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockEngine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&hclconfig, nil)
+	mockEngine.On("ApplyTargets", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&hclconfig, nil)
 	mockEngine.On("GetClients", mock.Anything).Return(clients)
+	mockEngine.On("Events").Return(events.NewBus())
+	mockEngine.On("SetParallelism", mock.Anything).Return(nil)
+	mockEngine.On("SetGracePeriod", mock.Anything)
+	mockEngine.On("SetKeepGoing", mock.Anything)
 	mockEngine.On("ResourceCountForType", mock.Anything).Return(0)
 
 	bp := blueprint.Blueprint{}
@@ -202,6 +212,108 @@ func TestRunSetsDestinationFromArgsWhenPresent(t *testing.T) {
 	rm.engine.AssertCalled(t, "ApplyWithVariables", mock.Anything, "/tmp", mock.Anything, mock.Anything)
 }
 
+func TestRunAppliesTargetsFromFlag(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("no-browser", "true")
+	rf.Flags().Set("target", "resource.container.api")
+	rf.Flags().Set("target", "resource.container.web")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "ApplyTargets", mock.Anything, "/tmp", mock.Anything, mock.Anything, []string{"resource.container.api", "resource.container.web"})
+	rm.engine.AssertNotCalled(t, "ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunSetsParallelismFromFlags(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("no-browser", "true")
+	rf.Flags().Set("parallelism", "4")
+	rf.Flags().Set("parallelism-type", "k8s_cluster=1")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "SetParallelism", jumppad.Parallelism{Limit: 4, TypeLimits: map[string]int{"k8s_cluster": 1}})
+}
+
+func TestRunDoesNotSetParallelismByDefault(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("no-browser", "true")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertNotCalled(t, "SetParallelism", mock.Anything)
+}
+
+func TestRunReturnsErrorForInvalidTypeParallelism(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("parallelism-type", "k8s_cluster")
+
+	err := rf.Execute()
+	require.Error(t, err)
+
+	rm.engine.AssertNotCalled(t, "ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunResumeAppliesCancelledApply(t *testing.T) {
+	testutils.SetupState(t, "")
+	os.MkdirAll(utils.StateDir(), os.ModePerm)
+
+	dir := t.TempDir()
+	err := os.WriteFile(utils.ResumePath(), []byte(fmt.Sprintf(`{"path": "%s", "variables": {"version": "1"}, "targets": ["resource.container.api"]}`, dir)), 0600)
+	require.NoError(t, err)
+
+	rf, rm := setupRun(t)
+	rf.Flags().Set("no-browser", "true")
+	rf.Flags().Set("resume", "true")
+
+	err = rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "ApplyTargets", mock.Anything, dir, map[string]string{"version": "1"}, "", []string{"resource.container.api"})
+}
+
+func TestRunResumeReturnsErrorWhenNothingToResume(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	rf, rm := setupRun(t)
+	rf.Flags().Set("resume", "true")
+
+	err := rf.Execute()
+	require.Error(t, err)
+
+	rm.engine.AssertNotCalled(t, "ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunResumeReturnsErrorWithPath(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("resume", "true")
+
+	err := rf.Execute()
+	require.Error(t, err)
+
+	rm.engine.AssertNotCalled(t, "ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunSetsKeepGoingFromFlag(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("no-browser", "true")
+	rf.Flags().Set("keep-going", "true")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "SetKeepGoing", true)
+}
+
 func TestRunSetsVariablesFromFlag(t *testing.T) {
 	rf, rm := setupRun(t)
 	rf.SetArgs([]string{
@@ -215,7 +327,7 @@ func TestRunSetsVariablesFromFlag(t *testing.T) {
 	err := rf.Execute()
 	require.NoError(t, err)
 
-	args := rm.engine.Calls[0].Arguments[2]
+	args := testutils.GetCalls(&rm.engine.Mock, "ApplyWithVariables")[0].Arguments[2]
 
 	require.Equal(t, map[string]string{
 		"abc":  "1234",
@@ -351,3 +463,36 @@ func TestRunDoesNotOpensBrowserWindowWhenCheckError(t *testing.T) {
 
 	rm.system.AssertNumberOfCalls(t, "OpenBrowser", 0)
 }
+
+func TestRunRedactsSensitiveOutputs(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+
+	out := bytes.NewBuffer(nil)
+	rf.SetOut(out)
+
+	bp := &blueprint.Blueprint{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{Name: "test", Type: blueprint.TypeBlueprint}}}
+
+	pw := &resources.Output{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{
+		Name:       "password",
+		Type:       resources.TypeOutput,
+		Properties: map[string]any{constants.PropertySensitive: true},
+	}}}
+	pw.Value = "supersecret"
+
+	user := &resources.Output{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{Name: "user", Type: resources.TypeOutput}}}
+	user.Value = "admin"
+
+	c := &hclconfig.Config{Resources: []hcltypes.Resource{bp, pw, user}}
+
+	testutils.RemoveOn(&rm.engine.Mock, "ApplyWithVariables")
+	rm.engine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(c, nil)
+	rm.engine.On("Config").Return(c)
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	require.Contains(t, out.String(), "admin")
+	require.Contains(t, out.String(), config.SensitiveValue)
+	require.NotContains(t, out.String(), "supersecret")
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage workspaces",
+	Long: `Manage workspaces, each workspace has its own state, data folder, and resource names.
+This allows multiple blueprints to be run side by side without conflicting.
+
+The active workspace can be overridden for a single command using the --workspace flag
+or the JUMPPAD_WORKSPACE environment variable`,
+}
+
+func newWorkspaceListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the available workspaces",
+		Long:  "List the available workspaces, the active workspace is marked with an asterisk",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ws, err := utils.ListWorkspaces()
+			if err != nil {
+				return fmt.Errorf("unable to list workspaces: %s", err)
+			}
+
+			current := utils.CurrentWorkspace()
+			for _, w := range ws {
+				if w == current {
+					cmd.Printf("* %s\n", w)
+					continue
+				}
+
+				cmd.Printf("  %s\n", w)
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
+
+func newWorkspaceNewCmd() *cobra.Command {
+	var noSelect bool
+
+	newCmd := &cobra.Command{
+		Use:   "new [name]",
+		Short: "Create a new workspace",
+		Long:  "Create a new workspace and make it the active workspace",
+		Example: `
+  # Create a new workspace called nomad and select it
+  jumppad workspace new nomad
+	`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := utils.CreateWorkspace(args[0])
+			if err != nil {
+				return fmt.Errorf("unable to create workspace %s: %s", args[0], err)
+			}
+
+			cmd.Printf("Created workspace %s\n", args[0])
+
+			if noSelect {
+				return nil
+			}
+
+			err = utils.SelectWorkspace(args[0])
+			if err != nil {
+				return fmt.Errorf("unable to select workspace %s: %s", args[0], err)
+			}
+
+			cmd.Printf("Switched to workspace %s\n", args[0])
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+
+	newCmd.Flags().BoolVarP(&noSelect, "no-select", "", false, "When set to true the new workspace is created but not selected")
+
+	return newCmd
+}
+
+func newWorkspaceSelectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "select [name]",
+		Short: "Select the active workspace",
+		Long:  "Select the active workspace, subsequent commands use the state and data of this workspace",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := utils.SelectWorkspace(args[0])
+			if err != nil {
+				return fmt.Errorf("unable to select workspace %s: %s", args[0], err)
+			}
+
+			cmd.Printf("Switched to workspace %s\n", args[0])
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
+
+func newWorkspaceDeleteCmd() *cobra.Command {
+	var force bool
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Delete a workspace",
+		Long: `Delete a workspace including its state and data folder.
+Workspaces that contain resources must be destroyed with 'jumppad down' before they can be deleted`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			if name == utils.CurrentWorkspace() {
+				return fmt.Errorf("unable to delete the active workspace %s, select a different workspace first", name)
+			}
+
+			if _, err := os.Stat(utils.WorkspaceStatePath(name)); err == nil && !force {
+				return fmt.Errorf("workspace %s contains resources, run 'jumppad down --workspace %s' first or use --force to delete anyway", name, name)
+			}
+
+			err := utils.DeleteWorkspace(name)
+			if err != nil {
+				return fmt.Errorf("unable to delete workspace %s: %s", name, err)
+			}
+
+			cmd.Printf("Deleted workspace %s\n", name)
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+
+	deleteCmd.Flags().BoolVarP(&force, "force", "", false, "When set to true the workspace is deleted even when it contains resources, resources are not destroyed")
+
+	return deleteCmd
+}
+
+// hasOtherWorkspaceState returns true if a workspace other than the
+// active workspace may have resources. The state for remote backends is not
+// checked, any other workspace is assumed to have resources
+func hasOtherWorkspaceState() bool {
+	ws, err := utils.ListWorkspaces()
+	if err != nil {
+		return true
+	}
+
+	bc, err := config.LoadBackendConfig()
+	remote := err != nil || bc.Type != config.BackendTypeLocal
+
+	for _, w := range ws {
+		if w == utils.CurrentWorkspace() {
+			continue
+		}
+
+		if remote {
+			return true
+		}
+
+		if _, err := os.Stat(utils.WorkspaceStatePath(w)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func setupWorkspace(t *testing.T) {
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+	t.Setenv(config.BackendEnvVar, "")
+	t.Setenv(utils.WorkspaceEnvVar, "nomad")
+
+	require.NoError(t, utils.CreateWorkspace("nomad"))
+}
+
+func TestHasOtherWorkspaceStateReturnsFalseWithoutState(t *testing.T) {
+	setupWorkspace(t)
+
+	require.False(t, hasOtherWorkspaceState())
+}
+
+func TestHasOtherWorkspaceStateReturnsTrueWithLocalState(t *testing.T) {
+	setupWorkspace(t)
+
+	sp := utils.WorkspaceStatePath(utils.DefaultWorkspace)
+	require.NoError(t, os.MkdirAll(filepath.Dir(sp), os.ModePerm))
+	require.NoError(t, os.WriteFile(sp, []byte("{}"), 0644))
+
+	require.True(t, hasOtherWorkspaceState())
+}
+
+func TestHasOtherWorkspaceStateReturnsTrueWithRemoteBackend(t *testing.T) {
+	setupWorkspace(t)
+	t.Setenv(config.BackendEnvVar, config.BackendTypeS3)
+
+	require.True(t, hasOtherWorkspaceState())
+}
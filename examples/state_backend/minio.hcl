@@ -0,0 +1,54 @@
+# Runs a MinIO server that can be used as an S3 compatible state backend,
+# the s3 backend tests run against this server when JUMPPAD_TEST_S3_ENDPOINT
+# is set, e.g.
+#
+#   jumppad up ./examples/state_backend
+#   JUMPPAD_TEST_S3_ENDPOINT=http://localhost:9000 go test ./pkg/config/...
+#
+# To store the state for other blueprints in MinIO create a bucket named
+# jumppad and add the following to ~/.jumppad/settings.hcl
+#
+#   jumppad {
+#     backend "s3" {
+#       bucket         = "jumppad"
+#       endpoint       = "http://localhost:9000"
+#       use_path_style = true
+#       access_key     = "minioadmin"
+#       secret_key     = "minioadmin"
+#     }
+#   }
+#
+# The state for the MinIO server itself must be stored with the local backend.
+
+resource "network" "main" {
+  subnet = "10.10.0.0/16"
+}
+
+resource "container" "minio" {
+  image {
+    name = "minio/minio:latest"
+  }
+
+  command = ["server", "/data", "--console-address", ":9001"]
+
+  environment = {
+    MINIO_ROOT_USER     = "minioadmin"
+    MINIO_ROOT_PASSWORD = "minioadmin"
+  }
+
+  network {
+    id = resource.network.main.meta.id
+  }
+
+  port {
+    local  = 9000
+    remote = 9000
+    host   = 9000
+  }
+
+  port {
+    local  = 9001
+    remote = 9001
+    host   = 9001
+  }
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/jumppad-labs/jumppad/cmd"
@@ -13,6 +14,11 @@ var date = "0000-00-00"
 func main() {
 	err := cmd.Execute(version, commit, date)
 	if err != nil {
+		ec := &cmd.ExitCodeError{}
+		if errors.As(err, &ec) {
+			os.Exit(ec.Code)
+		}
+
 		os.Exit(1)
 	}
 }
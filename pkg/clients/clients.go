@@ -34,6 +34,13 @@ type Clients struct {
 	TarGz          *tar.TarGz
 }
 
+// NewNomadClient returns a new Nomad client that uses the shared HTTP client.
+// A Nomad client is configured with the address of a single cluster, providers
+// must not share the Nomad client and call SetConfig on it
+func (c *Clients) NewNomadClient() nomad.Nomad {
+	return nomad.NewNomad(c.HTTP, 1*time.Second, c.Logger)
+}
+
 // GenerateClients creates the various clients for creating and destroying resources
 func GenerateClients(l logger.Logger) (*Clients, error) {
 	dc, _ := container.NewDocker()
@@ -27,6 +27,10 @@ type ContainerTasks interface {
 	ContainerInfo(id string) (interface{}, error)
 	// RemoveContainer stops and removes a running container
 	RemoveContainer(id string, force bool) error
+	// StopContainer gracefully stops a running container without removing it
+	StopContainer(id string) error
+	// StartContainer starts a stopped container
+	StartContainer(id string) error
 	// BuildContainer builds a container based on the given configuration
 	// If a cached image already exists Build will noop
 	// When force is specified BuildContainer will rebuild the container regardless of cached images
@@ -70,6 +74,9 @@ type ContainerTasks interface {
 	CopyFromContainer(id, src, dst string) error
 	// CopyToContainer allows a file to be copied into a container
 	CopyFileToContainer(id, src, dst string) error
+	// CopyDirectoryToContainer copies the directory src and its contents into
+	// the directory dst in the container
+	CopyDirectoryToContainer(id, src, dst string) error
 	// CreateFileInContainer creates a file with the given contents and name in the container containerID and
 	// stores it in the container at the directory path.
 	CreateFileInContainer(containerID, contents, filename, path string) error
@@ -81,10 +88,17 @@ type ContainerTasks interface {
 
 	//CopyFilesToVolume copies the files to the path in a Docker volume
 	CopyFilesToVolume(volume string, files []string, path string, force bool) ([]string, error)
+
+	// CopyFromVolume copies the contents of a Docker volume to the local
+	// directory dst
+	CopyFromVolume(volume, dst string) error
+	// CopyDirectoryToVolume copies the contents of the local directory src to
+	// the root of a Docker volume, the volume is created if it does not exist
+	CopyDirectoryToVolume(volume, src string) error
 	// Execute command allows the execution of commands in a running docker container
 	// id is the id of the container to execute the command in
 	// command is a slice of strings to execute
-	// timeout in seconds
+	// timeout in seconds, a negative timeout waits for the command to complete
 	// writer [optional] will be used to write any output from the command execution.
 	ExecuteCommand(id string, command []string, env []string, workingDirectory string, user, group string, timeout int, writer io.Writer) (int, error)
 	// ExecuteScript allows the execution of a script in a running docker container
@@ -102,9 +116,13 @@ type ContainerTasks interface {
 	ListNetworks(id string) []types.NetworkAttachment
 	// FindNetwork returns a network using the unique resource id
 	FindNetwork(id string) (types.NetworkAttachment, error)
+	// FindUnmanagedNetwork returns a network that was not created by jumppad
+	// using the Docker id or name
+	FindUnmanagedNetwork(ref string) (types.NetworkAttachment, error)
 
-	// CreateShell in the running container and attach
-	CreateShell(id string, command []string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error
+	// CreateShell in the running container and attach, when workingDirectory
+	// is empty the shell is started in the root of the filesystem
+	CreateShell(id string, command []string, env []string, workingDirectory string, user string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error
 
 	// TagImage tags an image with the given tag
 	TagImage(source, destination string) error
@@ -28,6 +28,7 @@ import (
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/go-connections/nat"
+	"github.com/jumppad-labs/hclconfig/resources"
 	dtypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/images"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
@@ -51,6 +52,16 @@ const (
 
 const defaultExitCode = 254
 
+// ExecError is returned when a command executed in a container exits with a
+// non zero exit code
+type ExecError struct {
+	ExitCode int
+}
+
+func (e *ExecError) Error() string {
+	return fmt.Sprintf("container exec failed with exit code %d", e.ExitCode)
+}
+
 // DockerTasks is a concrete implementation of ContainerTasks which uses the Docker SDK
 type DockerTasks struct {
 	engineType    string
@@ -619,6 +630,22 @@ func (d *DockerTasks) RemoveContainer(id string, force bool) error {
 	return d.c.ContainerRemove(context.Background(), id, container.RemoveOptions{Force: true, RemoveVolumes: true})
 }
 
+// StopContainer with the given id, the container is not removed and can
+// be started again with StartContainer
+func (d *DockerTasks) StopContainer(id string) error {
+	d.l.Debug("Stopping container", "container", id)
+
+	timeout := 30
+	return d.c.ContainerStop(context.Background(), id, container.StopOptions{Timeout: &timeout})
+}
+
+// StartContainer with the given id
+func (d *DockerTasks) StartContainer(id string) error {
+	d.l.Debug("Starting container", "container", id)
+
+	return d.c.ContainerStart(context.Background(), id, container.StartOptions{})
+}
+
 func (d *DockerTasks) RemoveImage(id string) error {
 	_, err := d.c.ImageRemove(context.Background(), id, image.RemoveOptions{Force: true})
 
@@ -880,10 +907,86 @@ func (d *DockerTasks) CopyLocalDockerImagesToVolume(images []string, volume stri
 // CopyFileToVolume copies a file to a Docker volume
 // returns the names of the stored files
 func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, path string, force bool) ([]string, error) {
+	tmpID, err := d.createVolumeContainer(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	defer d.RemoveContainer(tmpID, true)
+
+	// container is running copy the files
+
+	// create the directory paths ensure unix paths for containers
+	destPath := filepath.ToSlash(filepath.Join("/cache", path))
+	_, err = d.ExecuteCommand(tmpID, []string{"mkdir", "-p", destPath}, nil, "/", "", "", 300, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create destination path '%s' in volume: %w", destPath, err)
+	}
+
+	// add each file individually
+	imported := []string{}
+	for _, f := range filenames {
+		// get the filename part
+		name := filepath.Base(f)
+		destFile := fmt.Sprintf("%s/%s", destPath, name)
+
+		// check if the image exists if we are not doing a forced update
+		if !d.force && !force {
+			_, err := d.ExecuteCommand(tmpID, []string{"find", destFile}, nil, "/", "", "", 300, nil)
+			if err == nil {
+				// we have the image already
+				d.l.Debug("File already cached", "name", name, "path", path)
+				imported = append(imported, destFile)
+				continue
+			}
+		}
+
+		err = d.CopyFileToContainer(tmpID, f, destPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to copy file %s to container: %w", f, err)
+		}
+
+		imported = append(imported, destFile)
+	}
+
+	return imported, nil
+}
+
+// CopyFromVolume copies the contents of the volume to the local directory dst,
+// any existing files at dst are replaced
+func (d *DockerTasks) CopyFromVolume(volumeID, dst string) error {
+	d.l.Debug("Copying files from volume", "volume", volumeID, "dst", dst)
+
+	tmpID, err := d.createVolumeContainer(volumeID)
+	if err != nil {
+		return err
+	}
+	defer d.RemoveContainer(tmpID, true)
+
+	return d.CopyFromContainer(tmpID, "/cache", dst)
+}
+
+// CopyDirectoryToVolume copies the contents of the directory src to the root
+// of the volume
+func (d *DockerTasks) CopyDirectoryToVolume(volumeID, src string) error {
+	d.l.Debug("Copying directory to volume", "volume", volumeID, "src", src)
+
+	tmpID, err := d.createVolumeContainer(volumeID)
+	if err != nil {
+		return err
+	}
+	defer d.RemoveContainer(tmpID, true)
+
+	return d.copyTarToContainer(tmpID, src, "/cache", &ctar.TarGzOptions{OmitRoot: true})
+}
+
+// createVolumeContainer starts a container with the volume mounted at /cache
+// that is used to copy files to and from the volume, the caller must remove
+// the container
+func (d *DockerTasks) createVolumeContainer(volumeID string) (string, error) {
 	// make sure we have the alpine image needed to copy
 	err := d.PullImage(dtypes.Image{Name: "alpine:latest"}, false)
 	if err != nil {
-		return nil, fmt.Errorf("unable pull 'alpine:latest' needed to copy files to volume: %w", err)
+		return "", fmt.Errorf("unable pull 'alpine:latest' needed to copy files to volume: %w", err)
 	}
 
 	// create a dummy container to import to volume
@@ -904,9 +1007,8 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 
 	tmpID, err := d.CreateContainer(cc)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create dummy container for importing files: %w", err)
+		return "", fmt.Errorf("unable to create dummy container for importing files: %w", err)
 	}
-	defer d.RemoveContainer(tmpID, true)
 
 	// wait for container to start
 	successCount := 0
@@ -938,49 +1040,16 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 			d.l.Error("Timeout waiting for container to start", "ref", tmpID, "error", err)
 			startError = fmt.Errorf("timeout waiting for container to start: %w", startError)
 
-			return nil, startError
+			d.RemoveContainer(tmpID, true)
+
+			return "", startError
 		}
 
 		// still waiting for success wait
 		time.Sleep(d.defaultWait)
 	}
 
-	// container is running copy the files
-
-	// create the directory paths ensure unix paths for containers
-	destPath := filepath.ToSlash(filepath.Join("/cache", path))
-	_, err = d.ExecuteCommand(tmpID, []string{"mkdir", "-p", destPath}, nil, "/", "", "", 300, nil)
-	if err != nil {
-		return nil, fmt.Errorf("unable to create destination path '%s' in volume: %w", destPath, err)
-	}
-
-	// add each file individually
-	imported := []string{}
-	for _, f := range filenames {
-		// get the filename part
-		name := filepath.Base(f)
-		destFile := fmt.Sprintf("%s/%s", destPath, name)
-
-		// check if the image exists if we are not doing a forced update
-		if !d.force && !force {
-			_, err := d.ExecuteCommand(tmpID, []string{"find", destFile}, nil, "/", "", "", 300, nil)
-			if err == nil {
-				// we have the image already
-				d.l.Debug("File already cached", "name", name, "path", path)
-				imported = append(imported, destFile)
-				continue
-			}
-		}
-
-		err = d.CopyFileToContainer(cc.Name, f, destPath)
-		if err != nil {
-			return nil, fmt.Errorf("unable to copy file %s to container: %w", f, err)
-		}
-
-		imported = append(imported, destFile)
-	}
-
-	return imported, nil
+	return tmpID, nil
 }
 
 // CreateFileInContainer creates a file with the given contents and name in the container containerID and
@@ -1063,6 +1132,49 @@ func (d *DockerTasks) CopyFileToContainer(containerID, filename, path string) er
 	return nil
 }
 
+// CopyDirectoryToContainer copies the directory src to the container containerID,
+// the directory is created inside the existing directory path in the container
+func (d *DockerTasks) CopyDirectoryToContainer(containerID, src, path string) error {
+	d.l.Debug("Copying directory to container", "id", containerID, "src", src, "dst", path)
+
+	return d.copyTarToContainer(containerID, src, path, &ctar.TarGzOptions{})
+}
+
+// copyTarToContainer creates a tar archive of the directory src using the
+// given options and extracts it to path in the container
+func (d *DockerTasks) copyTarToContainer(containerID, src, path string, options *ctar.TarGzOptions) error {
+	// the tar is created relative to the parent of src so src must be absolute
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return fmt.Errorf("unable to determine absolute path for '%s': %w", src, err)
+	}
+
+	tmpTarFile, err := os.CreateTemp("", "")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary file for tar archive: %w", err)
+	}
+
+	defer func() {
+		tmpTarFile.Close()
+		os.Remove(tmpTarFile.Name())
+	}()
+
+	err = d.tg.Create(tmpTarFile, options, []string{src})
+	if err != nil {
+		return fmt.Errorf("unable to create tar archive for '%s': %w", src, err)
+	}
+
+	// reset the file seek so we can copy to the container
+	tmpTarFile.Seek(0, 0)
+
+	err = d.c.CopyToContainer(context.Background(), containerID, path, tmpTarFile, container.CopyToContainerOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to copy directory to container: %w", err)
+	}
+
+	return nil
+}
+
 // ExecuteCommand allows the execution of commands in a running docker container
 // id is the id of the container to execute the command in
 // command is a slice of strings to execute
@@ -1094,7 +1206,11 @@ func (d *DockerTasks) ExecuteCommand(id string, command []string, env []string,
 
 	defer stream.Close()
 
-	streamContext, cancelStream := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	// a negative timeout waits for the command to complete
+	streamContext, cancelStream := context.WithCancel(context.Background())
+	if timeout >= 0 {
+		streamContext, cancelStream = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	}
 	defer cancelStream()
 
 	// if we have a writer stream the logs from the container to the writer
@@ -1132,7 +1248,7 @@ func (d *DockerTasks) ExecuteCommand(id string, command []string, env []string,
 				return i.ExitCode, nil
 			}
 
-			return i.ExitCode, fmt.Errorf("container exec failed with exit code %d", i.ExitCode)
+			return i.ExitCode, &ExecError{ExitCode: i.ExitCode}
 		}
 
 		time.Sleep(d.defaultWait)
@@ -1171,10 +1287,16 @@ func (d *DockerTasks) ExecuteScript(id string, contents string, env []string, wo
 
 // CreateShell creates an interactive shell inside a container
 // https://github.com/docker/cli/blob/ae1618713f83e7da07317d579d0675f578de22fa/cli/command/container/exec.go
-func (d *DockerTasks) CreateShell(id string, command []string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error {
+func (d *DockerTasks) CreateShell(id string, command []string, env []string, workingDir string, user string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error {
+	if workingDir == "" {
+		workingDir = "/"
+	}
+
 	execid, err := d.c.ContainerExecCreate(context.Background(), id, container.ExecOptions{
 		Cmd:          command,
-		WorkingDir:   "/",
+		Env:          env,
+		WorkingDir:   workingDir,
+		User:         user,
 		AttachStdin:  true,
 		AttachStdout: true,
 		AttachStderr: true,
@@ -1243,7 +1365,7 @@ func (d *DockerTasks) CreateShell(id string, command []string, stdin io.ReadClos
 			}
 
 			streamCancel()
-			return fmt.Errorf("container exec failed with exit code %d", i.ExitCode)
+			return &ExecError{ExitCode: i.ExitCode}
 		}
 
 		time.Sleep(d.defaultWait)
@@ -1359,19 +1481,69 @@ func (d *DockerTasks) FindNetwork(id string) (dtypes.NetworkAttachment, error) {
 	}
 
 	for _, n := range nets {
-		if n.Labels["id"] == id {
-			return dtypes.NetworkAttachment{
-				ID:          n.ID,
-				Name:        n.Name,
-				Subnet:      n.IPAM.Config[0].Subnet,
-				IPv6Enabled: n.EnableIPv6,
-			}, nil
+		// networks created before workspaces existed do not have a workspace label
+		// and belong to the default workspace
+		ws := n.Labels["workspace"]
+		if ws == "" {
+			ws = utils.DefaultWorkspace
+		}
+
+		if n.Labels["id"] == id && ws == utils.CurrentWorkspace() {
+			return networkAttachment(n), nil
+		}
+	}
+
+	// networks imported into the state were not created by jumppad and do not
+	// have an id label, importing requires the network to use the name that
+	// jumppad gives to the network resource so only this name is matched
+	if fqrn, err := resources.ParseFQRN(id); err == nil && fqrn.Type == "network" {
+		name := utils.WorkspaceResourceName(fqrn.Resource)
+
+		for _, n := range nets {
+			if _, ok := n.Labels["id"]; !ok && n.Name == name {
+				return networkAttachment(n), nil
+			}
 		}
 	}
 
 	return dtypes.NetworkAttachment{}, fmt.Errorf("a network with the label id: %s, was not found", id)
 }
 
+// FindUnmanagedNetwork returns a network that was not created by jumppad using
+// the Docker id or name, it is used to import existing networks
+func (d *DockerTasks) FindUnmanagedNetwork(ref string) (dtypes.NetworkAttachment, error) {
+	nets, err := d.c.NetworkList(context.Background(), network.ListOptions{})
+	if err != nil {
+		return dtypes.NetworkAttachment{}, err
+	}
+
+	for _, n := range nets {
+		if _, ok := n.Labels["id"]; ok {
+			continue
+		}
+
+		if n.ID == ref || n.Name == ref {
+			return networkAttachment(n), nil
+		}
+	}
+
+	return dtypes.NetworkAttachment{}, fmt.Errorf("a network with the id or name: %s, that was not created by jumppad was not found", ref)
+}
+
+func networkAttachment(n network.Summary) dtypes.NetworkAttachment {
+	na := dtypes.NetworkAttachment{
+		ID:          n.ID,
+		Name:        n.Name,
+		IPv6Enabled: n.EnableIPv6,
+	}
+
+	if len(n.IPAM.Config) > 0 {
+		na.Subnet = n.IPAM.Config[0].Subnet
+	}
+
+	return na
+}
+
 func (d *DockerTasks) TagImage(source, destination string) error {
 	return d.c.ImageTag(context.Background(), source, destination)
 }
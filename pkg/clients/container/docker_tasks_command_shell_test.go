@@ -48,7 +48,7 @@ func TestCreateShellCreatesExec(t *testing.T) {
 	out := io.Discard
 	errW := io.Discard
 
-	err := p.CreateShell("abc", []string{"sh"}, in, out, errW)
+	err := p.CreateShell("abc", []string{"sh"}, nil, "", "", in, out, errW)
 	assert.NoError(t, err)
 
 	md.AssertCalled(t, "ContainerExecCreate", mock.Anything, "abc", mock.Anything)
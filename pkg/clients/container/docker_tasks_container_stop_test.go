@@ -0,0 +1,39 @@
+package container
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerStopStopsWithoutRemoving(t *testing.T) {
+	dt, md := setupRemoveTests(t)
+	md.On("ContainerStop", mock.Anything, "test", mock.Anything).Return(nil)
+
+	err := dt.StopContainer("test")
+	require.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ContainerStop", 1)
+	md.AssertNotCalled(t, "ContainerRemove", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestContainerStopReturnsError(t *testing.T) {
+	dt, md := setupRemoveTests(t)
+	md.On("ContainerStop", mock.Anything, "test", mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := dt.StopContainer("test")
+	require.Error(t, err)
+}
+
+func TestContainerStartStartsContainer(t *testing.T) {
+	dt, md := setupRemoveTests(t)
+	md.On("ContainerStart", mock.Anything, "test", container.StartOptions{}).Return(nil)
+
+	err := dt.StartContainer("test")
+	require.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ContainerStart", 1)
+}
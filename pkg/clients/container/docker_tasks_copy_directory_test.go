@@ -0,0 +1,71 @@
+package container
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/system"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	imocks "github.com/jumppad-labs/jumppad/pkg/clients/images/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	ctar "github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCopyDirectoryTests(t *testing.T) (*DockerTasks, *mocks.Docker, string) {
+	md := &mocks.Docker{}
+	md.On("ServerVersion", mock.Anything).Return(types.Version{}, nil)
+	md.On("Info", mock.Anything).Return(system.Info{Driver: StorageDriverOverlay2}, nil)
+
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "config", "sub"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "config", "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "config", "sub", "b.txt"), []byte("b"), 0644)
+
+	dt, _ := NewDockerTasks(md, &imocks.ImageLog{}, &ctar.TarGz{}, logger.NewTestLogger(t))
+
+	return dt, md, tmpDir
+}
+
+func TestCopyDirectoryToContainerCopiesTar(t *testing.T) {
+	dt, md, tmpDir := setupCopyDirectoryTests(t)
+
+	files := []string{}
+	md.On("CopyToContainer", mock.Anything, "abc", "/etc", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		tr := tar.NewReader(args.Get(3).(io.Reader))
+		for {
+			hdr, err := tr.Next()
+			if err != nil {
+				break
+			}
+
+			files = append(files, hdr.Name)
+		}
+	}).Return(nil)
+
+	err := dt.CopyDirectoryToContainer("abc", filepath.Join(tmpDir, "config"), "/etc")
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"config", "config/a.txt", "config/sub", "config/sub/b.txt"}, files)
+}
+
+func TestCopyDirectoryToContainerReturnsErrorOnDockerError(t *testing.T) {
+	dt, md, tmpDir := setupCopyDirectoryTests(t)
+	md.On("CopyToContainer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := dt.CopyDirectoryToContainer("abc", filepath.Join(tmpDir, "config"), "/etc")
+	require.Error(t, err)
+}
+
+func TestCopyDirectoryToContainerReturnsErrorWhenSourceMissing(t *testing.T) {
+	dt, _, tmpDir := setupCopyDirectoryTests(t)
+
+	err := dt.CopyDirectoryToContainer("abc", filepath.Join(tmpDir, "missing"), "/etc")
+	require.Error(t, err)
+}
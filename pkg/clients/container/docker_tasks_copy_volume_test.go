@@ -0,0 +1,75 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	ctar "github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyDirectoryToVolumeCopiesContentsToRoot(t *testing.T) {
+	dt, mk := testSetupCopyLocal(t)
+
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "sub"), 0755)
+	os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(src, "sub", "b.txt"), []byte("b"), 0644)
+
+	files := []string{}
+	testutils.RemoveOn(&mk.Mock, "CopyToContainer")
+	mk.On("CopyToContainer", mock.Anything, "myid", "/cache", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		tr := tar.NewReader(args.Get(3).(io.Reader))
+		for {
+			hdr, err := tr.Next()
+			if err != nil {
+				break
+			}
+
+			files = append(files, hdr.Name)
+		}
+	}).Return(nil)
+
+	err := dt.CopyDirectoryToVolume("data", src)
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"a.txt", "sub", "sub/b.txt"}, files)
+
+	// the temporary container must be removed
+	mk.AssertCalled(t, "ContainerRemove", mock.Anything, "myid", mock.Anything)
+}
+
+func TestCopyFromVolumeCopiesContents(t *testing.T) {
+	dt, mk := testSetupCopyLocal(t)
+
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "cache"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "cache", "a.txt"), []byte("a"), 0644)
+
+	buf := bytes.NewBuffer(nil)
+	err := (&ctar.TarGz{}).Create(buf, &ctar.TarGzOptions{}, []string{filepath.Join(tmpDir, "cache")})
+	require.NoError(t, err)
+
+	mk.On("CopyFromContainer", mock.Anything, "myid", "/cache").Return(
+		io.NopCloser(buf),
+		container.PathStat{},
+		nil,
+	)
+
+	dst := filepath.Join(tmpDir, "output")
+	err = dt.CopyFromVolume("data", dst)
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(filepath.Join(dst, "a.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "a", string(d))
+
+	mk.AssertCalled(t, "ContainerRemove", mock.Anything, "myid", mock.Anything)
+}
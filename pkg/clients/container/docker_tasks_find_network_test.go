@@ -0,0 +1,87 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupFindNetworkTests(t *testing.T) *DockerTasks {
+	md := &mocks.Docker{}
+	md.On("ServerVersion", mock.Anything).Return(types.Version{}, nil)
+	md.On("Info", mock.Anything).Return(system.Info{Driver: StorageDriverOverlay2}, nil)
+	md.On("NetworkList", mock.Anything, mock.Anything).Return(
+		[]network.Summary{
+			{ID: "abc", Name: "cloud", Labels: map[string]string{"id": "resource.network.cloud"}, IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "10.0.0.0/24"}}}},
+			{ID: "123", Name: "onprem", IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "10.2.0.0/24"}}}},
+			{ID: "456", Name: "empty"},
+		}, nil)
+
+	dt, _ := NewDockerTasks(md, nil, &tar.TarGz{}, logger.NewTestLogger(t))
+
+	return dt
+}
+
+func TestFindNetworkReturnsNetworkWithIDLabel(t *testing.T) {
+	dt := setupFindNetworkTests(t)
+
+	n, err := dt.FindNetwork("resource.network.cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", n.ID)
+	assert.Equal(t, "10.0.0.0/24", n.Subnet)
+}
+
+func TestFindNetworkReturnsNetworkWithoutLabelsUsingResourceName(t *testing.T) {
+	dt := setupFindNetworkTests(t)
+
+	n, err := dt.FindNetwork("resource.network.onprem")
+	assert.NoError(t, err)
+	assert.Equal(t, "123", n.ID)
+}
+
+func TestFindNetworkDoesNotMatchNetworksWithoutLabelsUsingDockerIDOrName(t *testing.T) {
+	dt := setupFindNetworkTests(t)
+
+	_, err := dt.FindNetwork("123")
+	assert.Error(t, err)
+
+	_, err = dt.FindNetwork("empty")
+	assert.Error(t, err)
+}
+
+func TestFindNetworkDoesNotMatchLabelledNetworksByName(t *testing.T) {
+	dt := setupFindNetworkTests(t)
+
+	_, err := dt.FindNetwork("cloud")
+	assert.Error(t, err)
+}
+
+func TestFindUnmanagedNetworkReturnsNetworkUsingDockerIDOrName(t *testing.T) {
+	dt := setupFindNetworkTests(t)
+
+	n, err := dt.FindUnmanagedNetwork("123")
+	assert.NoError(t, err)
+	assert.Equal(t, "onprem", n.Name)
+
+	n, err = dt.FindUnmanagedNetwork("empty")
+	assert.NoError(t, err)
+	assert.Equal(t, "456", n.ID)
+	assert.Empty(t, n.Subnet)
+}
+
+func TestFindUnmanagedNetworkDoesNotMatchLabelledNetworks(t *testing.T) {
+	dt := setupFindNetworkTests(t)
+
+	_, err := dt.FindUnmanagedNetwork("cloud")
+	assert.Error(t, err)
+
+	_, err = dt.FindUnmanagedNetwork("abc")
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package container
+
+import (
+	"fmt"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+)
+
+// ContainerDrift returns the differences between a container that should be
+// running and the Docker container with the given name, an empty list is
+// returned when the container exists and is running
+func ContainerDrift(c ContainerTasks, name string) ([]string, error) {
+	ids, err := c.FindContainerIDs(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []string{fmt.Sprintf("container %s does not exist", name)}, nil
+	}
+
+	info, err := c.ContainerInfo(ids[0])
+	if err != nil {
+		return nil, err
+	}
+
+	ci, ok := info.(dcontainer.InspectResponse)
+	if !ok || ci.ContainerJSONBase == nil || ci.State == nil {
+		return nil, nil
+	}
+
+	if !ci.State.Running {
+		return []string{fmt.Sprintf("container %s is %s", name, ci.State.Status)}, nil
+	}
+
+	return nil, nil
+}
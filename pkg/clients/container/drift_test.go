@@ -0,0 +1,53 @@
+package container
+
+import (
+	"fmt"
+	"testing"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDriftTests(ids []string, state *dcontainer.State) *mocks.ContainerTasks {
+	mc := &mocks.ContainerTasks{}
+	mc.On("FindContainerIDs", "test.container.local.jmpd.in").Return(ids, nil)
+	mc.On("ContainerInfo", "abc").Return(
+		dcontainer.InspectResponse{ContainerJSONBase: &dcontainer.ContainerJSONBase{State: state}},
+		nil,
+	)
+
+	return mc
+}
+
+func TestContainerDriftReturnsNothingWhenRunning(t *testing.T) {
+	mc := setupDriftTests([]string{"abc"}, &dcontainer.State{Running: true, Status: "running"})
+
+	d, err := ContainerDrift(mc, "test.container.local.jmpd.in")
+	require.NoError(t, err)
+	require.Empty(t, d)
+}
+
+func TestContainerDriftReturnsMissingContainer(t *testing.T) {
+	mc := setupDriftTests(nil, nil)
+
+	d, err := ContainerDrift(mc, "test.container.local.jmpd.in")
+	require.NoError(t, err)
+	require.Equal(t, []string{"container test.container.local.jmpd.in does not exist"}, d)
+}
+
+func TestContainerDriftReturnsStoppedContainer(t *testing.T) {
+	mc := setupDriftTests([]string{"abc"}, &dcontainer.State{Running: false, Status: "exited"})
+
+	d, err := ContainerDrift(mc, "test.container.local.jmpd.in")
+	require.NoError(t, err)
+	require.Equal(t, []string{"container test.container.local.jmpd.in is exited"}, d)
+}
+
+func TestContainerDriftReturnsError(t *testing.T) {
+	mc := &mocks.ContainerTasks{}
+	mc.On("FindContainerIDs", "test.container.local.jmpd.in").Return(nil, fmt.Errorf("boom"))
+
+	_, err := ContainerDrift(mc, "test.container.local.jmpd.in")
+	require.Error(t, err)
+}
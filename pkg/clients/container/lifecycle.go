@@ -0,0 +1,45 @@
+package container
+
+import "fmt"
+
+// StopContainers stops the Docker containers with the given names, the
+// containers are not removed, names that do not have a container are ignored
+func StopContainers(c ContainerTasks, names ...string) error {
+	for _, n := range names {
+		ids, err := c.FindContainerIDs(n)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := c.StopContainer(id); err != nil {
+				return fmt.Errorf("unable to stop container %s: %w", n, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// StartContainers starts the stopped Docker containers with the given names,
+// an error is returned when a container does not exist
+func StartContainers(c ContainerTasks, names ...string) error {
+	for _, n := range names {
+		ids, err := c.FindContainerIDs(n)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return fmt.Errorf("container %s does not exist", n)
+		}
+
+		for _, id := range ids {
+			if err := c.StartContainer(id); err != nil {
+				return fmt.Errorf("unable to start container %s: %w", n, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package container
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLifecycleTests(ids []string) *mocks.ContainerTasks {
+	mc := &mocks.ContainerTasks{}
+	mc.On("FindContainerIDs", "test.container.local.jmpd.in").Return(ids, nil)
+	mc.On("StopContainer", "abc").Return(nil)
+	mc.On("StartContainer", "abc").Return(nil)
+
+	return mc
+}
+
+func TestStopContainersStopsEachContainer(t *testing.T) {
+	mc := setupLifecycleTests([]string{"abc"})
+
+	err := StopContainers(mc, "test.container.local.jmpd.in")
+	require.NoError(t, err)
+
+	mc.AssertCalled(t, "StopContainer", "abc")
+}
+
+func TestStopContainersIgnoresMissingContainers(t *testing.T) {
+	mc := setupLifecycleTests(nil)
+
+	err := StopContainers(mc, "test.container.local.jmpd.in")
+	require.NoError(t, err)
+
+	mc.AssertNotCalled(t, "StopContainer", "abc")
+}
+
+func TestStartContainersStartsEachContainer(t *testing.T) {
+	mc := setupLifecycleTests([]string{"abc"})
+
+	err := StartContainers(mc, "test.container.local.jmpd.in")
+	require.NoError(t, err)
+
+	mc.AssertCalled(t, "StartContainer", "abc")
+}
+
+func TestStartContainersReturnsErrorForMissingContainer(t *testing.T) {
+	mc := setupLifecycleTests(nil)
+
+	err := StartContainers(mc, "test.container.local.jmpd.in")
+	require.ErrorContains(t, err, "does not exist")
+}
+
+func TestStartContainersReturnsStartError(t *testing.T) {
+	mc := &mocks.ContainerTasks{}
+	mc.On("FindContainerIDs", "test.container.local.jmpd.in").Return([]string{"abc"}, nil)
+	mc.On("StartContainer", "abc").Return(fmt.Errorf("boom"))
+
+	err := StartContainers(mc, "test.container.local.jmpd.in")
+	require.ErrorContains(t, err, "boom")
+}
@@ -138,6 +138,42 @@ func (_m *ContainerTasks) CopyFileToContainer(id string, src string, dst string)
 	return r0
 }
 
+// CopyDirectoryToContainer provides a mock function with given fields: id, src, dst
+func (_m *ContainerTasks) CopyDirectoryToContainer(id string, src string, dst string) error {
+	ret := _m.Called(id, src, dst)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CopyDirectoryToContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(id, src, dst)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CopyDirectoryToVolume provides a mock function with given fields: volume, src
+func (_m *ContainerTasks) CopyDirectoryToVolume(volume string, src string) error {
+	ret := _m.Called(volume, src)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CopyDirectoryToVolume")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(volume, src)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CopyFilesToVolume provides a mock function with given fields: volume, files, path, force
 func (_m *ContainerTasks) CopyFilesToVolume(volume string, files []string, path string, force bool) ([]string, error) {
 	ret := _m.Called(volume, files, path, force)
@@ -186,6 +222,24 @@ func (_m *ContainerTasks) CopyFromContainer(id string, src string, dst string) e
 	return r0
 }
 
+// CopyFromVolume provides a mock function with given fields: volume, dst
+func (_m *ContainerTasks) CopyFromVolume(volume string, dst string) error {
+	ret := _m.Called(volume, dst)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CopyFromVolume")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(volume, dst)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CopyLocalDockerImagesToVolume provides a mock function with given fields: images, volume, force
 func (_m *ContainerTasks) CopyLocalDockerImagesToVolume(images []string, volume string, force bool) ([]string, error) {
 	ret := _m.Called(images, volume, force)
@@ -262,17 +316,17 @@ func (_m *ContainerTasks) CreateFileInContainer(containerID string, contents str
 	return r0
 }
 
-// CreateShell provides a mock function with given fields: id, command, stdin, stdout, stderr
-func (_m *ContainerTasks) CreateShell(id string, command []string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error {
-	ret := _m.Called(id, command, stdin, stdout, stderr)
+// CreateShell provides a mock function with given fields: id, command, env, workingDirectory, user, stdin, stdout, stderr
+func (_m *ContainerTasks) CreateShell(id string, command []string, env []string, workingDirectory string, user string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error {
+	ret := _m.Called(id, command, env, workingDirectory, user, stdin, stdout, stderr)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateShell")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, []string, io.ReadCloser, io.Writer, io.Writer) error); ok {
-		r0 = rf(id, command, stdin, stdout, stderr)
+	if rf, ok := ret.Get(0).(func(string, []string, []string, string, string, io.ReadCloser, io.Writer, io.Writer) error); ok {
+		r0 = rf(id, command, env, workingDirectory, user, stdin, stdout, stderr)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -518,6 +572,34 @@ func (_m *ContainerTasks) FindNetwork(id string) (types.NetworkAttachment, error
 	return r0, r1
 }
 
+// FindUnmanagedNetwork provides a mock function with given fields: ref
+func (_m *ContainerTasks) FindUnmanagedNetwork(ref string) (types.NetworkAttachment, error) {
+	ret := _m.Called(ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUnmanagedNetwork")
+	}
+
+	var r0 types.NetworkAttachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (types.NetworkAttachment, error)); ok {
+		return rf(ref)
+	}
+	if rf, ok := ret.Get(0).(func(string) types.NetworkAttachment); ok {
+		r0 = rf(ref)
+	} else {
+		r0 = ret.Get(0).(types.NetworkAttachment)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListNetworks provides a mock function with given fields: id
 func (_m *ContainerTasks) ListNetworks(id string) []types.NetworkAttachment {
 	ret := _m.Called(id)
@@ -633,6 +715,42 @@ func (_m *ContainerTasks) SetForce(_a0 bool) {
 	_m.Called(_a0)
 }
 
+// StartContainer provides a mock function with given fields: id
+func (_m *ContainerTasks) StartContainer(id string) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StopContainer provides a mock function with given fields: id
+func (_m *ContainerTasks) StopContainer(id string) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StopContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TagImage provides a mock function with given fields: source, destination
 func (_m *ContainerTasks) TagImage(source string, destination string) error {
 	ret := _m.Called(source, destination)
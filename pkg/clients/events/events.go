@@ -0,0 +1,196 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Type is the type of an engine event
+type Type string
+
+const (
+	// ResourceCreateStarted is published before the provider creates a resource
+	ResourceCreateStarted Type = "resource.create.started"
+	// ResourceCreateFinished is published when a resource has been created
+	ResourceCreateFinished Type = "resource.create.finished"
+	// ResourceCreateFailed is published when the provider fails to create a resource
+	ResourceCreateFailed Type = "resource.create.failed"
+	// ResourceCreateRetrying is published when a failed create will be retried,
+	// the message contains the attempt and the time until the next attempt
+	ResourceCreateRetrying Type = "resource.create.retrying"
+
+	// ResourceRefreshStarted is published before the provider refreshes a resource
+	ResourceRefreshStarted Type = "resource.refresh.started"
+	// ResourceRefreshFinished is published when a resource has been refreshed
+	ResourceRefreshFinished Type = "resource.refresh.finished"
+	// ResourceRefreshFailed is published when the provider fails to refresh a resource
+	ResourceRefreshFailed Type = "resource.refresh.failed"
+
+	// ResourceDestroyStarted is published before the provider destroys a resource
+	ResourceDestroyStarted Type = "resource.destroy.started"
+	// ResourceDestroyFinished is published when a resource has been destroyed
+	ResourceDestroyFinished Type = "resource.destroy.finished"
+	// ResourceDestroyFailed is published when the provider fails to destroy a resource
+	ResourceDestroyFailed Type = "resource.destroy.failed"
+
+	// ResourceStopStarted is published before the provider stops a resource
+	ResourceStopStarted Type = "resource.stop.started"
+	// ResourceStopFinished is published when a resource has been stopped
+	ResourceStopFinished Type = "resource.stop.finished"
+	// ResourceStopFailed is published when the provider fails to stop a resource
+	ResourceStopFailed Type = "resource.stop.failed"
+
+	// ResourceStartStarted is published before the provider starts a stopped resource
+	ResourceStartStarted Type = "resource.start.started"
+	// ResourceStartFinished is published when a stopped resource has been started
+	ResourceStartFinished Type = "resource.start.finished"
+	// ResourceStartFailed is published when the provider fails to start a resource
+	ResourceStartFailed Type = "resource.start.failed"
+
+	// HealthCheckStarted is published when a provider starts a health check
+	HealthCheckStarted Type = "healthcheck.started"
+	// HealthCheckProgress is published while a provider waits for a health check
+	HealthCheckProgress Type = "healthcheck.progress"
+	// HealthCheckPassed is published when a health check succeeds
+	HealthCheckPassed Type = "healthcheck.passed"
+	// HealthCheckFailed is published when a health check fails or times out
+	HealthCheckFailed Type = "healthcheck.failed"
+)
+
+// Event is a typed notification published by the engine or a provider
+type Event struct {
+	Type         Type      `json:"type"`
+	Time         time.Time `json:"time"`
+	ResourceID   string    `json:"resource_id,omitempty"`
+	ResourceType string    `json:"resource_type,omitempty"`
+	// Message contains additional detail such as the health check being run
+	Message string `json:"message,omitempty"`
+	// Error is set for failed events
+	Error string `json:"error,omitempty"`
+	// DurationMS is the duration of the operation in milliseconds, it is set
+	// for finished and failed events
+	DurationMS int64 `json:"duration_ms,omitempty"`
+}
+
+// Duration returns the duration of the operation
+func (e Event) Duration() time.Duration {
+	return time.Duration(e.DurationMS) * time.Millisecond
+}
+
+// Handler is called for every event published to the bus
+type Handler func(Event)
+
+// Bus distributes events to subscribers, handlers are called synchronously
+// in the order they subscribed and must not block. Resources are processed
+// in parallel so handlers can be called concurrently and must be safe for
+// concurrent use.
+// A nil Bus discards all events
+type Bus struct {
+	mutex    sync.RWMutex
+	handlers map[int]Handler
+	next     int
+}
+
+// NewBus creates a new event bus
+func NewBus() *Bus {
+	return &Bus{handlers: map[int]Handler{}}
+}
+
+// Subscribe registers a handler that receives all events published after it
+// was registered, the returned function removes the subscription
+func (b *Bus) Subscribe(h Handler) func() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	id := b.next
+	b.next++
+	b.handlers[id] = h
+
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+
+		delete(b.handlers, id)
+	}
+}
+
+// Publish sends the event to all subscribers, if the event does not have a
+// time the current time is used
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	// copy the handlers so that a handler can subscribe or unsubscribe
+	// without deadlocking the bus
+	b.mutex.RLock()
+	handlers := make([]Handler, 0, len(b.handlers))
+	for i := 0; i < b.next; i++ {
+		if h, ok := b.handlers[i]; ok {
+			handlers = append(handlers, h)
+		}
+	}
+	b.mutex.RUnlock()
+
+	for _, h := range handlers {
+		h(e)
+	}
+}
+
+type contextKey struct{}
+
+type resourceContext struct {
+	bus          *Bus
+	resourceID   string
+	resourceType string
+}
+
+// WithResource returns a context that allows providers to publish events for
+// the given resource using Publish
+func WithResource(ctx context.Context, b *Bus, id, resourceType string) context.Context {
+	return context.WithValue(ctx, contextKey{}, resourceContext{b, id, resourceType})
+}
+
+// Publish sends an event to the bus in the context, the resource details are
+// added from the context. If the context does not contain a bus the event
+// is discarded
+func Publish(ctx context.Context, e Event) {
+	if ctx == nil {
+		return
+	}
+
+	rc, ok := ctx.Value(contextKey{}).(resourceContext)
+	if !ok {
+		return
+	}
+
+	if e.ResourceID == "" {
+		e.ResourceID = rc.resourceID
+		e.ResourceType = rc.resourceType
+	}
+
+	rc.bus.Publish(e)
+}
+
+// HealthCheck runs the given check publishing HealthCheckStarted, followed by
+// HealthCheckPassed or HealthCheckFailed, the message describes the check
+// e.g. "http http://localhost:8500"
+func HealthCheck(ctx context.Context, message string, check func() error) error {
+	st := time.Now()
+	Publish(ctx, Event{Type: HealthCheckStarted, Message: message})
+
+	err := check()
+	if err != nil {
+		Publish(ctx, Event{Type: HealthCheckFailed, Message: message, Error: err.Error(), DurationMS: time.Since(st).Milliseconds()})
+		return err
+	}
+
+	Publish(ctx, Event{Type: HealthCheckPassed, Message: message, DurationMS: time.Since(st).Milliseconds()})
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishSendsEventToSubscribers(t *testing.T) {
+	b := NewBus()
+
+	received := []Event{}
+	b.Subscribe(func(e Event) { received = append(received, e) })
+	b.Subscribe(func(e Event) { received = append(received, e) })
+
+	b.Publish(Event{Type: ResourceCreateStarted, ResourceID: "resource.container.api"})
+
+	require.Len(t, received, 2)
+	require.Equal(t, "resource.container.api", received[0].ResourceID)
+	require.False(t, received[0].Time.IsZero())
+}
+
+func TestUnsubscribeStopsEvents(t *testing.T) {
+	b := NewBus()
+
+	count := 0
+	unsubscribe := b.Subscribe(func(e Event) { count++ })
+
+	b.Publish(Event{Type: ResourceCreateStarted})
+	unsubscribe()
+	b.Publish(Event{Type: ResourceCreateFinished})
+
+	require.Equal(t, 1, count)
+}
+
+func TestHandlerCanUnsubscribeWhilePublishing(t *testing.T) {
+	b := NewBus()
+
+	count := 0
+	var unsubscribe func()
+	unsubscribe = b.Subscribe(func(e Event) {
+		count++
+		unsubscribe()
+	})
+
+	b.Publish(Event{Type: ResourceCreateStarted})
+	b.Publish(Event{Type: ResourceCreateFinished})
+
+	require.Equal(t, 1, count)
+}
+
+func TestPublishWithNilBusDoesNothing(t *testing.T) {
+	var b *Bus
+
+	require.NotPanics(t, func() {
+		b.Publish(Event{Type: ResourceCreateStarted})
+	})
+}
+
+func TestContextPublishAddsResourceDetails(t *testing.T) {
+	b := NewBus()
+
+	received := []Event{}
+	b.Subscribe(func(e Event) { received = append(received, e) })
+
+	ctx := WithResource(context.Background(), b, "resource.container.api", "container")
+	Publish(ctx, Event{Type: HealthCheckProgress})
+
+	require.Len(t, received, 1)
+	require.Equal(t, "resource.container.api", received[0].ResourceID)
+	require.Equal(t, "container", received[0].ResourceType)
+}
+
+func TestContextPublishWithoutBusDoesNothing(t *testing.T) {
+	require.NotPanics(t, func() {
+		Publish(context.Background(), Event{Type: HealthCheckProgress})
+	})
+}
+
+func TestHealthCheckPublishesStartedAndPassed(t *testing.T) {
+	b := NewBus()
+
+	received := []Event{}
+	b.Subscribe(func(e Event) { received = append(received, e) })
+
+	ctx := WithResource(context.Background(), b, "resource.container.api", "container")
+	err := HealthCheck(ctx, "http http://localhost", func() error { return nil })
+	require.NoError(t, err)
+
+	require.Len(t, received, 2)
+	require.Equal(t, HealthCheckStarted, received[0].Type)
+	require.Equal(t, HealthCheckPassed, received[1].Type)
+	require.Equal(t, "http http://localhost", received[1].Message)
+}
+
+func TestHealthCheckPublishesFailedWithError(t *testing.T) {
+	b := NewBus()
+
+	received := []Event{}
+	b.Subscribe(func(e Event) { received = append(received, e) })
+
+	ctx := WithResource(context.Background(), b, "resource.container.api", "container")
+	err := HealthCheck(ctx, "exec", func() error { return fmt.Errorf("boom") })
+	require.Error(t, err)
+
+	require.Len(t, received, 2)
+	require.Equal(t, HealthCheckFailed, received[1].Type)
+	require.Equal(t, "boom", received[1].Error)
+}
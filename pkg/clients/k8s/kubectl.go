@@ -25,7 +25,7 @@ type Kubernetes interface {
 	HealthCheckPods(ctx context.Context, selectors []string, timeout time.Duration) error
 	Apply(files []string, waitUntilReady bool) error
 	Delete(files []string) error
-	GetPodLogs(ctx context.Context, podName, nameSpace string) (io.ReadCloser, error)
+	GetPodLogs(ctx context.Context, podName, nameSpace string, opts *v1.PodLogOptions) (io.ReadCloser, error)
 }
 
 // KubernetesImpl is a concrete implementation of a Kubernetes client
@@ -91,10 +91,14 @@ func (k *KubernetesImpl) setConfig() error {
 	return nil
 }
 
-// GetPodLogs returns a io.ReadCloser,err for a given pods' logs
-func (k *KubernetesImpl) GetPodLogs(ctx context.Context, podName, nameSpace string) (io.ReadCloser, error) {
-	var plOpts v1.PodLogOptions
-	return k.clientset.CoreV1().Pods(nameSpace).GetLogs(podName, &plOpts).Stream(ctx)
+// GetPodLogs returns a io.ReadCloser,err for a given pods' logs, opts can be
+// nil to return the logs for the only container in the pod
+func (k *KubernetesImpl) GetPodLogs(ctx context.Context, podName, nameSpace string, opts *v1.PodLogOptions) (io.ReadCloser, error) {
+	if opts == nil {
+		opts = &v1.PodLogOptions{}
+	}
+
+	return k.clientset.CoreV1().Pods(nameSpace).GetLogs(podName, opts).Stream(ctx)
 }
 
 // GetPods returns the Kubernetes pods based on the label selector
@@ -30,8 +30,13 @@ func (m *MockKubernetes) GetPods(selector string) (*v1.PodList, error) {
 	return nil, args.Error(1)
 }
 
-func (m *MockKubernetes) GetPodLogs(ctx context.Context, podName, nameSpace string) (io.ReadCloser, error) {
-	args := m.Called(ctx, podName, nameSpace)
+func (m *MockKubernetes) GetPodLogs(ctx context.Context, podName, nameSpace string, opts *v1.PodLogOptions) (io.ReadCloser, error) {
+	args := m.Called(ctx, podName, nameSpace, opts)
+
+	if rc, ok := args.Get(0).(io.ReadCloser); ok {
+		return rc, args.Error(1)
+	}
+
 	ior := io.NopCloser(bytes.NewBufferString("Running pod ..."))
 	return ior, args.Error(1)
 }
@@ -60,7 +60,11 @@ type CharmLogger struct {
 }
 
 func NewLogger(w io.Writer, level string) Logger {
-	l := log.New(w)
+	// the renderer is created for the underlying writer so that the color
+	// profile of the terminal is detected
+	l := log.NewWithOptions(&redactWriter{w}, log.Options{
+		Renderer: lipgloss.NewRenderer(w, termenv.WithColorCache(true)),
+	})
 	ll, err := log.ParseLevel(level)
 	if err != nil {
 		ll = log.InfoLevel
@@ -73,7 +77,7 @@ func NewLogger(w io.Writer, level string) Logger {
 // NewTTYLogger creates a new logger with full TTY colors
 func NewTTYLogger(w io.Writer, level string) Logger {
 	r := lipgloss.NewRenderer(w, termenv.WithColorCache(true), termenv.WithTTY(true))
-	l := log.NewWithOptions(w, log.Options{
+	l := log.NewWithOptions(&redactWriter{w}, log.Options{
 		Level:           log.InfoLevel,
 		ReportTimestamp: false,
 		Renderer:        r,
@@ -84,7 +88,9 @@ func NewTTYLogger(w io.Writer, level string) Logger {
 
 func (l *CharmLogger) SetOutput(w io.Writer) {
 	l.writer = w
-	l.internal.SetOutput(w)
+	rw := &redactWriter{w}
+	l.internal.SetRenderer(rw, lipgloss.NewRenderer(w, termenv.WithColorCache(true)))
+	l.internal.SetOutput(rw)
 }
 
 func (l *CharmLogger) Output() io.Writer {
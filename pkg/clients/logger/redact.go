@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces sensitive values in the log output
+const RedactedValue = "(sensitive)"
+
+// minSensitiveLength is the minimum length of a value that is redacted,
+// redacting shorter values would make the output unreadable
+const minSensitiveLength = 4
+
+var sensitive = &sensitiveValues{values: map[string]bool{}}
+
+type sensitiveValues struct {
+	sync.RWMutex
+	values map[string]bool
+	show   bool
+}
+
+// AddSensitiveValues registers values that are replaced in the output of all
+// loggers
+func AddSensitiveValues(values ...string) {
+	sensitive.Lock()
+	defer sensitive.Unlock()
+
+	for _, v := range values {
+		if len(v) >= minSensitiveLength {
+			sensitive.values[v] = true
+		}
+	}
+}
+
+// ShowSensitiveValues disables the redaction of sensitive values
+func ShowSensitiveValues(show bool) {
+	sensitive.Lock()
+	defer sensitive.Unlock()
+
+	sensitive.show = show
+}
+
+// Redact replaces any registered sensitive values in s
+func Redact(s string) string {
+	sensitive.RLock()
+	defer sensitive.RUnlock()
+
+	if sensitive.show || len(sensitive.values) == 0 {
+		return s
+	}
+
+	// replace the longest values first so that values containing other
+	// values are fully redacted
+	values := []string{}
+	for v := range sensitive.values {
+		values = append(values, v)
+	}
+
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, RedactedValue)
+	}
+
+	return s
+}
+
+// redactWriter replaces sensitive values before writing to the underlying
+// writer
+type redactWriter struct {
+	w io.Writer
+}
+
+func (r *redactWriter) Write(p []byte) (int, error) {
+	_, err := r.w.Write([]byte(Redact(string(p))))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setupRedactTests(t *testing.T) {
+	old := sensitive
+	sensitive = &sensitiveValues{values: map[string]bool{}}
+
+	t.Cleanup(func() {
+		sensitive = old
+	})
+}
+
+func TestRedactReplacesSensitiveValues(t *testing.T) {
+	setupRedactTests(t)
+
+	AddSensitiveValues("secret123", "token456")
+
+	out := Redact("password=secret123 token=token456 user=admin")
+	require.Equal(t, "password=(sensitive) token=(sensitive) user=admin", out)
+}
+
+func TestRedactWithoutValuesReturnsInput(t *testing.T) {
+	setupRedactTests(t)
+
+	require.Equal(t, "password=secret123", Redact("password=secret123"))
+}
+
+func TestRedactIgnoresShortValues(t *testing.T) {
+	setupRedactTests(t)
+
+	AddSensitiveValues("abc", "")
+
+	require.Equal(t, "abc def", Redact("abc def"))
+}
+
+func TestRedactReplacesLongestValuesFirst(t *testing.T) {
+	setupRedactTests(t)
+
+	AddSensitiveValues("secret", "secret123")
+
+	require.Equal(t, "(sensitive) (sensitive)", Redact("secret123 secret"))
+}
+
+func TestRedactWithShowSensitiveReturnsInput(t *testing.T) {
+	setupRedactTests(t)
+
+	AddSensitiveValues("secret123")
+	ShowSensitiveValues(true)
+
+	require.Equal(t, "password=secret123", Redact("password=secret123"))
+}
+
+func TestRedactWriterRedactsOutput(t *testing.T) {
+	setupRedactTests(t)
+
+	AddSensitiveValues("secret123")
+
+	buf := bytes.NewBuffer(nil)
+	w := &redactWriter{buf}
+
+	n, err := w.Write([]byte("password=secret123"))
+	require.NoError(t, err)
+
+	// the length of the input is returned so that callers do not report a
+	// short write
+	require.Equal(t, len("password=secret123"), n)
+	require.Equal(t, "password=(sensitive)", buf.String())
+}
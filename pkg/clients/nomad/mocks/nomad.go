@@ -4,9 +4,12 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 
 	mock "github.com/stretchr/testify/mock"
 
+	nomad "github.com/jumppad-labs/jumppad/pkg/clients/nomad"
+
 	time "time"
 )
 
@@ -15,6 +18,32 @@ type Nomad struct {
 	mock.Mock
 }
 
+// AllocationTasks provides a mock function with given fields:
+func (_m *Nomad) AllocationTasks() ([]nomad.AllocationTask, error) {
+	ret := _m.Called()
+
+	var r0 []nomad.AllocationTask
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]nomad.AllocationTask, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []nomad.AllocationTask); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]nomad.AllocationTask)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: files
 func (_m *Nomad) Create(files []string) error {
 	ret := _m.Called(files)
@@ -147,6 +176,32 @@ func (_m *Nomad) Stop(files []string) error {
 	return r0
 }
 
+// TaskLogs provides a mock function with given fields: ctx, allocationID, task, logType, follow, fromEnd
+func (_m *Nomad) TaskLogs(ctx context.Context, allocationID string, task string, logType string, follow bool, fromEnd bool) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, allocationID, task, logType, follow, fromEnd)
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool, bool) (io.ReadCloser, error)); ok {
+		return rf(ctx, allocationID, task, logType, follow, fromEnd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool, bool) io.ReadCloser); ok {
+		r0 = rf(ctx, allocationID, task, logType, follow, fromEnd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, bool, bool) error); ok {
+		r1 = rf(ctx, allocationID, task, logType, follow, fromEnd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewNomad interface {
 	mock.TestingT
 	Cleanup(func())
@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
 	"time"
 
 	chttp "github.com/jumppad-labs/jumppad/pkg/clients/http"
@@ -34,6 +37,21 @@ type Nomad interface {
 	HealthCheckAPI(context.Context, time.Duration) error
 	// Endpoints returns a list of endpoints for a cluster
 	Endpoints(job, group, task string) ([]map[string]string, error)
+	// AllocationTasks returns the tasks for all running allocations in the cluster
+	AllocationTasks() ([]AllocationTask, error)
+	// TaskLogs returns the logs for a task in an allocation, logType is either
+	// stdout or stderr. When fromEnd is true only logs written after the call
+	// are returned, when follow is true the stream is kept open until ctx is
+	// cancelled.
+	TaskLogs(ctx context.Context, allocationID, task, logType string, follow, fromEnd bool) (io.ReadCloser, error)
+}
+
+// AllocationTask is a task in a running allocation
+type AllocationTask struct {
+	AllocationID string
+	Job          string
+	Group        string
+	Task         string
 }
 
 // NomadImpl is an implementation of the Nomad interface
@@ -391,6 +409,92 @@ func (n *NomadImpl) Endpoints(job, group, task string) ([]map[string]string, err
 	return endpoints, nil
 }
 
+// AllocationTasks returns the tasks for all running allocations in the cluster
+func (n *NomadImpl) AllocationTasks() ([]AllocationTask, error) {
+	r, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s:%d/v1/allocations", n.address, n.port), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create http request: %w", err)
+	}
+
+	resp, err := n.httpClient.Do(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query allocations: %w", err)
+	}
+
+	if resp.Body == nil {
+		return nil, fmt.Errorf("no body returned from Nomad API")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to query allocations, Nomad API returned status %d", resp.StatusCode)
+	}
+
+	allocs := []allocationStub{}
+	err = json.NewDecoder(resp.Body).Decode(&allocs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query allocations in Nomad server: %s: %s", n.address, err)
+	}
+
+	tasks := []AllocationTask{}
+	for _, a := range allocs {
+		if a.ClientStatus != "running" {
+			continue
+		}
+
+		// sort the task names so the order is stable
+		names := []string{}
+		for t := range a.TaskStates {
+			names = append(names, t)
+		}
+		sort.Strings(names)
+
+		for _, t := range names {
+			tasks = append(tasks, AllocationTask{AllocationID: a.ID, Job: a.JobID, Group: a.TaskGroup, Task: t})
+		}
+	}
+
+	return tasks, nil
+}
+
+// TaskLogs returns the logs for a task in an allocation
+func (n *NomadImpl) TaskLogs(ctx context.Context, allocationID, task, logType string, follow, fromEnd bool) (io.ReadCloser, error) {
+	origin := "start"
+	if fromEnd {
+		origin = "end"
+	}
+
+	q := url.Values{}
+	q.Set("task", task)
+	q.Set("type", logType)
+	q.Set("follow", strconv.FormatBool(follow))
+	q.Set("origin", origin)
+	q.Set("offset", "0")
+	q.Set("plain", "true")
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s:%d/v1/client/fs/logs/%s?%s", n.address, n.port, allocationID, q.Encode()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create http request: %w", err)
+	}
+
+	resp, err := n.httpClient.Do(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get logs for task %s: %w", task, err)
+	}
+
+	if resp.Body == nil {
+		return nil, fmt.Errorf("no body returned from Nomad API")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unable to get logs for task %s, Nomad API returned status %d", task, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
 func (n *NomadImpl) getJobAllocations(job string) ([]map[string]interface{}, error) {
 	// get the allocations for the job
 	r, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s:%d/v1/job/%s/allocations", n.address, n.port, job), nil)
@@ -435,6 +539,14 @@ func (n *NomadImpl) getJobID(file string) (string, error) {
 	return jobMap["ID"].(string), nil
 }
 
+type allocationStub struct {
+	ID           string
+	JobID        string
+	TaskGroup    string
+	ClientStatus string
+	TaskStates   map[string]any
+}
+
 type allocation struct {
 	ID        string
 	Job       job
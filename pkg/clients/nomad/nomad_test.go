@@ -432,6 +432,115 @@ func TestNomadEndpointsReturnsConnectEndpoints(t *testing.T) {
 	assert.Equal(t, "10.5.0.4:9090", e[0]["http"])
 }
 
+func TestNomadAllocationTasksReturnsRunningTasks(t *testing.T) {
+	c, _, mh := setupNomadTests(t)
+	c.SetConfig("http://localhost", 4646, 1)
+
+	testutils.RemoveOn(&mh.Mock, "Do")
+	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(allocationTasksResponse))),
+		},
+		nil,
+	)
+
+	tasks, err := c.AllocationTasks()
+	assert.NoError(t, err)
+	assert.Equal(t, []AllocationTask{
+		{AllocationID: "da975cd1", Job: "example_1", Group: "fake_service", Task: "connect-proxy"},
+		{AllocationID: "da975cd1", Job: "example_1", Group: "fake_service", Task: "fake_service"},
+	}, tasks)
+
+	r := mh.Calls[0].Arguments[0].(*http.Request)
+	assert.Equal(t, "/v1/allocations", r.URL.Path)
+}
+
+func TestNomadAllocationTasksErrorsOnNot200(t *testing.T) {
+	c, _, mh := setupNomadTests(t)
+
+	testutils.RemoveOn(&mh.Mock, "Do")
+	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       io.NopCloser(bytes.NewReader([]byte(""))),
+		},
+		nil,
+	)
+
+	_, err := c.AllocationTasks()
+	assert.Error(t, err)
+}
+
+func TestNomadTaskLogsRequestsLogs(t *testing.T) {
+	c, _, mh := setupNomadTests(t)
+	c.SetConfig("http://localhost", 4646, 1)
+
+	testutils.RemoveOn(&mh.Mock, "Do")
+	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte("hello world\n"))),
+		},
+		nil,
+	)
+
+	rc, err := c.TaskLogs(context.Background(), "da975cd1", "fake_service", "stderr", true, true)
+	assert.NoError(t, err)
+
+	d, err := io.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world\n", string(d))
+
+	r := mh.Calls[0].Arguments[0].(*http.Request)
+	assert.Equal(t, "/v1/client/fs/logs/da975cd1", r.URL.Path)
+	assert.Equal(t, "fake_service", r.URL.Query().Get("task"))
+	assert.Equal(t, "stderr", r.URL.Query().Get("type"))
+	assert.Equal(t, "true", r.URL.Query().Get("follow"))
+	assert.Equal(t, "end", r.URL.Query().Get("origin"))
+	assert.Equal(t, "true", r.URL.Query().Get("plain"))
+}
+
+func TestNomadTaskLogsErrorsOnNot200(t *testing.T) {
+	c, _, mh := setupNomadTests(t)
+
+	testutils.RemoveOn(&mh.Mock, "Do")
+	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(bytes.NewReader([]byte("unknown allocation"))),
+		},
+		nil,
+	)
+
+	_, err := c.TaskLogs(context.Background(), "da975cd1", "fake_service", "stdout", false, false)
+	assert.Error(t, err)
+}
+
+var allocationTasksResponse = `
+[
+  {
+    "ID": "da975cd1",
+    "JobID": "example_1",
+    "TaskGroup": "fake_service",
+    "ClientStatus": "running",
+    "TaskStates": {
+      "fake_service": {"State": "running"},
+      "connect-proxy": {"State": "running"}
+    }
+  },
+  {
+    "ID": "b1a2c3d4",
+    "JobID": "example_1",
+    "TaskGroup": "fake_service",
+    "ClientStatus": "complete",
+    "TaskStates": {
+      "fake_service": {"State": "dead"}
+    }
+  }
+]
+`
+
 var aliveResponse = `
 [
 	{
@@ -0,0 +1,63 @@
+package clients
+
+import (
+	"sync"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+)
+
+// Pool holds a single set of clients that is shared by all the providers in
+// an engine run. The clients are created the first time they are requested.
+type Pool struct {
+	log      logger.Logger
+	generate func(l logger.Logger) (*Clients, error)
+
+	once    sync.Once
+	clients *Clients
+	err     error
+}
+
+// NewPool creates a pool that generates the clients when they are first
+// requested
+func NewPool(l logger.Logger) *Pool {
+	return &Pool{log: l, generate: GenerateClients}
+}
+
+var (
+	defaultPool     *Pool
+	defaultPoolOnce sync.Once
+)
+
+// DefaultPool returns the pool that is shared by the process, it is used by
+// the root command and by providers that are initialized without clients.
+// The logger is only used when the pool is first created.
+func DefaultPool(l logger.Logger) *Pool {
+	defaultPoolOnce.Do(func() {
+		defaultPool = NewPool(l)
+	})
+
+	return defaultPool
+}
+
+// NewPoolWithClients creates a pool that shares existing clients
+func NewPoolWithClients(c *Clients) *Pool {
+	p := &Pool{log: c.Logger, clients: c}
+	p.once.Do(func() {})
+
+	return p
+}
+
+// Clients returns the clients for the pool, the clients are generated on
+// the first call and the same clients are returned for every subsequent call
+func (p *Pool) Clients() (*Clients, error) {
+	p.once.Do(func() {
+		p.clients, p.err = p.generate(p.log)
+	})
+
+	return p.clients, p.err
+}
+
+// Logger returns the logger used by the clients
+func (p *Pool) Logger() logger.Logger {
+	return p.log
+}
@@ -0,0 +1,86 @@
+package clients
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPoolTests(t *testing.T, err error) (*Pool, *int32) {
+	count := int32(0)
+
+	p := NewPool(logger.NewTestLogger(t))
+	p.generate = func(l logger.Logger) (*Clients, error) {
+		atomic.AddInt32(&count, 1)
+		return &Clients{Logger: l}, err
+	}
+
+	return p, &count
+}
+
+func TestPoolDoesNotCreateClientsUntilRequested(t *testing.T) {
+	_, count := setupPoolTests(t, nil)
+
+	require.Equal(t, int32(0), atomic.LoadInt32(count))
+}
+
+func TestPoolCreatesClientsOnce(t *testing.T) {
+	p, count := setupPoolTests(t, nil)
+
+	wg := sync.WaitGroup{}
+	results := make([]*Clients, 10)
+
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			c, err := p.Clients()
+			require.NoError(t, err)
+
+			results[i] = c
+		}(i)
+	}
+
+	wg.Wait()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(count))
+
+	for _, c := range results {
+		require.Same(t, results[0], c)
+	}
+}
+
+func TestPoolReturnsGenerateError(t *testing.T) {
+	p, count := setupPoolTests(t, fmt.Errorf("boom"))
+
+	_, err := p.Clients()
+	require.Error(t, err)
+
+	_, err = p.Clients()
+	require.Error(t, err)
+
+	require.Equal(t, int32(1), atomic.LoadInt32(count))
+}
+
+func TestPoolWithClientsReturnsExistingClients(t *testing.T) {
+	c := &Clients{Logger: logger.NewTestLogger(t)}
+
+	p := NewPoolWithClients(c)
+
+	pc, err := p.Clients()
+	require.NoError(t, err)
+	require.Same(t, c, pc)
+	require.Equal(t, c.Logger, p.Logger())
+}
+
+func TestNewNomadClientReturnsNewClient(t *testing.T) {
+	c := &Clients{Logger: logger.NewTestLogger(t)}
+
+	// each provider configures its client for a different cluster
+	require.NotSame(t, c.NewNomadClient(), c.NewNomadClient())
+}
@@ -0,0 +1,154 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer used for all jumppad spans
+const TracerName = "github.com/jumppad-labs/jumppad"
+
+// Attribute keys added to spans
+const (
+	AttributeResourceID   = attribute.Key("jumppad.resource.id")
+	AttributeResourceType = attribute.Key("jumppad.resource.type")
+	AttributeOperation    = attribute.Key("jumppad.operation")
+	AttributePath         = attribute.Key("jumppad.path")
+	AttributeImage        = attribute.Key("jumppad.image")
+)
+
+// Setup configures the global tracer provider. Spans are exported with OTLP
+// when OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is
+// set, the protocol is set with OTEL_EXPORTER_OTLP_PROTOCOL and defaults to
+// http/protobuf. When traceFile is not empty spans are also written to the
+// file as JSON, one span per line.
+// When no exporter is configured tracing is disabled and spans are not
+// recorded.
+//
+// The returned function flushes any pending spans and must be called before
+// the process exits.
+func Setup(ctx context.Context, traceFile, version string) (func(context.Context) error, error) {
+	exporters := []sdktrace.SpanExporter{}
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+		e, err := otlpExporter(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		exporters = append(exporters, e)
+	}
+
+	if traceFile != "" {
+		f, err := os.Create(traceFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create trace file '%s': %s", traceFile, err)
+		}
+
+		e, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("unable to create trace file exporter: %s", err)
+		}
+
+		exporters = append(exporters, &fileExporter{SpanExporter: e, file: f})
+	}
+
+	if len(exporters) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "jumppad"),
+			attribute.String("service.version", version),
+		)),
+	}
+
+	for _, e := range exporters {
+		opts = append(opts, sdktrace.WithBatcher(e))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// otlpExporter creates an exporter for the protocol set in the environment,
+// the endpoint, headers and other options are read from the environment by
+// the exporter
+func otlpExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	proto := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if proto == "" {
+		proto = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+
+	switch proto {
+	case "", "http/protobuf":
+		return otlptracehttp.New(ctx)
+	case "grpc":
+		return otlptracegrpc.New(ctx)
+	}
+
+	return nil, fmt.Errorf("unsupported OTLP protocol '%s', must be one of http/protobuf, grpc", proto)
+}
+
+// fileExporter closes the trace file when the exporter is shut down
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (f *fileExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(f.SpanExporter.Shutdown(ctx), f.file.Close())
+}
+
+// Tracer returns the tracer used for jumppad spans
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Start starts a span with the given name and attributes, the span is a
+// child of any span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends the span, when err is not nil it is recorded and the status of
+// the span is set to error
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Trace runs f in a span with the given name, the error returned by f is
+// recorded on the span
+func Trace(ctx context.Context, name string, f func(ctx context.Context) error, attrs ...attribute.KeyValue) error {
+	ctx, span := Start(ctx, name, attrs...)
+
+	err := f(ctx)
+	End(span, err)
+
+	return err
+}
@@ -0,0 +1,93 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupTelemetryTests(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+	})
+}
+
+func TestSetupWithoutExporterDoesNotRecordSpans(t *testing.T) {
+	setupTelemetryTests(t)
+
+	shutdown, err := Setup(context.Background(), "", "dev")
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	_, span := Start(context.Background(), "test")
+	defer span.End()
+
+	require.False(t, span.IsRecording())
+}
+
+func TestSetupWithTraceFileWritesSpans(t *testing.T) {
+	setupTelemetryTests(t)
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+
+	shutdown, err := Setup(context.Background(), path, "dev")
+	require.NoError(t, err)
+
+	ctx, parent := Start(context.Background(), "apply")
+	Trace(ctx, "docker.pull_image", func(context.Context) error { return nil }, AttributeImage.String("nginx"))
+	End(parent, nil)
+
+	err = shutdown(context.Background())
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, l := range strings.Split(strings.TrimSpace(string(d)), "\n") {
+		s := map[string]any{}
+		err := json.Unmarshal([]byte(l), &s)
+		require.NoError(t, err)
+
+		names = append(names, s["Name"].(string))
+	}
+
+	require.ElementsMatch(t, []string{"apply", "docker.pull_image"}, names)
+}
+
+func TestSetupReturnsErrorForUnknownProtocol(t *testing.T) {
+	setupTelemetryTests(t)
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/json")
+
+	_, err := Setup(context.Background(), "", "dev")
+	require.Error(t, err)
+}
+
+func TestTraceRecordsError(t *testing.T) {
+	setupTelemetryTests(t)
+
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	err := Trace(context.Background(), "helm.install", func(context.Context) error { return fmt.Errorf("boom") })
+	require.Error(t, err)
+
+	require.Len(t, sr.Ended(), 1)
+	require.Equal(t, codes.Error, sr.Ended()[0].Status().Code)
+	require.Len(t, sr.Ended()[0].Events(), 1)
+}
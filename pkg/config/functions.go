@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"strconv"
 
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -58,3 +59,11 @@ func customHCLFuncExists(path string) (bool, error) {
 
 	return true, nil
 }
+
+// customHCLFuncSensitive returns the value unchanged and marks it as sensitive
+// so that it is redacted from the output and logs, variables and outputs that
+// call the function are marked as sensitive
+func customHCLFuncSensitive(value string) (string, error) {
+	logger.AddSensitiveValues(value)
+	return value, nil
+}
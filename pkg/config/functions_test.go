@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,3 +23,11 @@ func TestExistsTrue(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, true, exists)
 }
+
+func TestSensitiveReturnsValueAndRedactsIt(t *testing.T) {
+	v, err := customHCLFuncSensitive("secret123")
+	require.NoError(t, err)
+	require.Equal(t, "secret123", v)
+
+	require.Equal(t, logger.RedactedValue, logger.Redact("secret123"))
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"reflect"
 
 	"github.com/jumppad-labs/hclconfig/types"
@@ -15,6 +16,17 @@ type Provider interface {
 	sdk.Provider
 }
 
+// DriftDetector is an optional interface implemented by providers that can
+// detect when the objects backing a resource have been changed or removed
+// outside of Jumppad, for example a container that has been removed with
+// docker rm
+type DriftDetector interface {
+	// Drift returns a description of each difference between the resource
+	// and the running objects, an empty list is returned when the resource
+	// has not drifted
+	Drift() ([]string, error)
+}
+
 // ConfigWrapper allows the provider config to be deserialized to a type
 type ConfigWrapper struct {
 	Type  string
@@ -25,12 +37,28 @@ type Providers interface {
 	GetProvider(c types.Resource) sdk.Provider
 }
 
+// ClientsInitializer is an optional interface implemented by providers that
+// use the Jumppad clients, rather than creating new clients in Init the
+// provider is initialized with the clients that are shared by the engine
+type ClientsInitializer interface {
+	InitWithClients(cfg types.Resource, l sdk.Logger, c *clients.Clients) error
+}
+
+// ProvidersImpl creates the providers for an engine run, the clients are
+// created once and shared by all the providers
 type ProvidersImpl struct {
-	clients *clients.Clients
+	pool *clients.Pool
 }
 
+// NewProviders creates providers that share the given clients
 func NewProviders(c *clients.Clients) Providers {
-	return &ProvidersImpl{c}
+	return &ProvidersImpl{clients.NewPoolWithClients(c)}
+}
+
+// NewProvidersWithPool creates providers that share the clients from the
+// pool, the clients are only created when a provider requires them
+func NewProvidersWithPool(p *clients.Pool) Providers {
+	return &ProvidersImpl{p}
 }
 
 func (p *ProvidersImpl) GetProvider(r types.Resource) sdk.Provider {
@@ -39,10 +67,53 @@ func (p *ProvidersImpl) GetProvider(r types.Resource) sdk.Provider {
 		ptr := reflect.New(reflect.TypeOf(t).Elem())
 
 		prov := ptr.Interface().(Provider)
-		prov.Init(r, p.clients.Logger)
+
+		ci, ok := prov.(ClientsInitializer)
+		if !ok {
+			err := prov.Init(r, p.pool.Logger())
+			if err != nil {
+				p.pool.Logger().Error("Unable to initialize provider", "ref", r.Metadata().ID, "error", err)
+				return nil
+			}
+
+			return prov
+		}
+
+		cli, err := p.pool.Clients()
+		if err != nil {
+			p.pool.Logger().Error("Unable to create clients", "error", err)
+			return nil
+		}
+
+		err = ci.InitWithClients(r, p.pool.Logger(), cli)
+		if err != nil {
+			p.pool.Logger().Error("Unable to initialize provider", "ref", r.Metadata().ID, "error", err)
+			return nil
+		}
 
 		return prov
 	}
 
 	return nil
 }
+
+// Importer is an optional interface implemented by providers that can adopt
+// an existing object that was not created by Jumppad, for example a container
+// started with docker run
+type Importer interface {
+	// Import checks that the object with the given Docker id or name matches
+	// the resource configuration and sets any computed attributes on the
+	// resource, an error is returned when the object does not match
+	Import(ref string) error
+}
+
+// Stopper is an optional interface implemented by providers that can stop
+// the objects backing a resource without destroying them, for example the
+// containers of a Kubernetes cluster
+type Stopper interface {
+	// Stop stops the objects for the resource, the objects and any data they
+	// contain are kept so that the resource can be started again
+	Stop(ctx context.Context) error
+	// Start starts the objects for a resource that has been stopped
+	Start(ctx context.Context) error
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
+	sdk "github.com/jumppad-labs/plugin-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+const typeTestClients = "test_clients"
+
+// receivedClients records the clients passed to each testClientsProvider,
+// providers are created by reflection so the instances can not be inspected
+var receivedClients []*clients.Clients
+
+// initError is returned by testClientsProvider.InitWithClients
+var initError error
+
+type testClientsProvider struct {
+	mocks.Provider
+}
+
+func (p *testClientsProvider) InitWithClients(cfg types.Resource, l sdk.Logger, c *clients.Clients) error {
+	receivedClients = append(receivedClients, c)
+	return initError
+}
+
+func setupProviderTests(t *testing.T) {
+	RegisterResource(typeTestClients, nil, &testClientsProvider{})
+	receivedClients = nil
+	initError = nil
+
+	t.Cleanup(func() {
+		delete(registeredProviders, typeTestClients)
+	})
+}
+
+func TestGetProviderSharesClientsBetweenProviders(t *testing.T) {
+	setupProviderTests(t)
+
+	p := NewProvidersWithPool(clients.NewPool(logger.NewTestLogger(t)))
+
+	for i := 0; i < 5; i++ {
+		r := &types.ResourceBase{Meta: types.Meta{Type: typeTestClients}}
+		require.NotNil(t, p.GetProvider(r))
+	}
+
+	// the clients are created once for the run and shared
+	require.Len(t, receivedClients, 5)
+	for _, c := range receivedClients {
+		require.NotNil(t, c)
+		require.Same(t, receivedClients[0], c)
+	}
+}
+
+func TestGetProviderUsesExistingClients(t *testing.T) {
+	setupProviderTests(t)
+
+	c := &clients.Clients{Logger: logger.NewTestLogger(t)}
+	p := NewProviders(c)
+
+	p.GetProvider(&types.ResourceBase{Meta: types.Meta{Type: typeTestClients}})
+
+	require.Len(t, receivedClients, 1)
+	require.Same(t, c, receivedClients[0])
+}
+
+func TestGetProviderReturnsNilForUnknownType(t *testing.T) {
+	p := NewProviders(&clients.Clients{Logger: logger.NewTestLogger(t)})
+
+	require.Nil(t, p.GetProvider(&types.ResourceBase{Meta: types.Meta{Type: "unknown"}}))
+}
+
+func TestGetProviderReturnsNilWhenInitFails(t *testing.T) {
+	setupProviderTests(t)
+	initError = fmt.Errorf("boom")
+
+	p := NewProviders(&clients.Clients{Logger: logger.NewTestLogger(t)})
+
+	require.Nil(t, p.GetProvider(&types.ResourceBase{Meta: types.Meta{Type: typeTestClients}}))
+}
@@ -28,10 +28,10 @@ type Build struct {
 	// outputs
 
 	// Image is the full local reference of the built image
-	Image string `hcl:"image,optional" json:"image"`
+	Image string `hcl:"image,optional" json:"image" computed:"true"`
 
 	// Checksum is calculated from the Context files
-	BuildChecksum string `hcl:"build_checksum,optional" json:"build_checksum,omitempty"`
+	BuildChecksum string `hcl:"build_checksum,optional" json:"build_checksum,omitempty" computed:"true"`
 }
 
 type BuildContainer struct {
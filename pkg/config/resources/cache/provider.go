@@ -28,16 +28,20 @@ type Provider struct {
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.DefaultPool(l).Clients()
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*ImageCache)
 	if !ok {
 		return fmt.Errorf("unable to initialize ImageCache provider, resource is not of type ImageCache")
 	}
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.config = c
 	p.client = cli.ContainerTasks
 	p.log = l
@@ -136,6 +140,25 @@ func (p *Provider) Lookup() ([]string, error) {
 	return p.client.FindContainerIDs(utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type))
 }
 
+// Drift checks that the cache container exists and is running
+func (p *Provider) Drift() ([]string, error) {
+	return container.ContainerDrift(p.client, utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type))
+}
+
+// Stop stops the cache container, the cached images are kept
+func (p *Provider) Stop(ctx context.Context) error {
+	p.log.Info("Stopping image cache", "ref", p.config.Meta.ID)
+
+	return container.StopContainers(p.client, utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type))
+}
+
+// Start starts the stopped cache container
+func (p *Provider) Start(ctx context.Context) error {
+	p.log.Info("Starting image cache", "ref", p.config.Meta.ID)
+
+	return container.StartContainers(p.client, utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type))
+}
+
 func (p *Provider) Changed() (bool, error) {
 	p.log.Debug("Checking changes", "ref", p.config.Meta.ID)
 
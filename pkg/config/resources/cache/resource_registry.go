@@ -17,5 +17,5 @@ type Registry struct {
 type RegistryAuth struct {
 	Hostname string `hcl:"hostname,optional" json:"hostname,omitempty"` // Hostname for authentication, can be different from registry hostname
 	Username string `hcl:"username" json:"username"`                    // Username for authentication
-	Password string `hcl:"password" json:"password"`                    // Password for authentication
+	Password string `hcl:"password" json:"password" sensitive:"true"`   // Password for authentication
 }
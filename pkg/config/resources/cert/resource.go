@@ -19,14 +19,14 @@ type CertificateCA struct {
 	// output parameters
 
 	// Key is the value related to the certificate key
-	PrivateKey File `hcl:"private_key,optional" json:"private_key"`
+	PrivateKey File `hcl:"private_key,optional" json:"private_key" sensitive:"contents" computed:"true"`
 
 	// Key is the value related to the certificate key
-	PublicKeyPEM File `hcl:"public_key_pem,optional" json:"public_key_pem"`
-	PublicKeySSH File `hcl:"public_key_ssh,optional" json:"public_key_ssh"`
+	PublicKeyPEM File `hcl:"public_key_pem,optional" json:"public_key_pem" computed:"true"`
+	PublicKeySSH File `hcl:"public_key_ssh,optional" json:"public_key_ssh" computed:"true"`
 
 	// Cert is the value related to the certificate
-	Cert File `hcl:"certificate,optional" json:"certificate"`
+	Cert File `hcl:"certificate,optional" json:"certificate" computed:"true"`
 }
 
 func (c *CertificateCA) Process() error {
@@ -72,14 +72,14 @@ type CertificateLeaf struct {
 	// output parameters
 
 	// Key is the value related to the certificate key
-	PrivateKey File `hcl:"private_key,optional" json:"private_key"`
+	PrivateKey File `hcl:"private_key,optional" json:"private_key" sensitive:"contents" computed:"true"`
 
 	// Key is the value related to the certificate key
-	PublicKeyPEM File `hcl:"public_key_pem,optional" json:"public_key_pem"`
-	PublicKeySSH File `hcl:"public_key_ssh,optional" json:"public_key_ssh"`
+	PublicKeyPEM File `hcl:"public_key_pem,optional" json:"public_key_pem" computed:"true"`
+	PublicKeySSH File `hcl:"public_key_ssh,optional" json:"public_key_ssh" computed:"true"`
 
 	// Cert is the value related to the certificate
-	Cert File `hcl:"certificate,optional" json:"certificate"`
+	Cert File `hcl:"certificate,optional" json:"certificate" computed:"true"`
 }
 
 func (c *CertificateLeaf) Process() error {
@@ -9,12 +9,15 @@ import (
 	"strings"
 	"time"
 
+	dcontainer "github.com/docker/docker/api/types/container"
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
 	"github.com/jumppad-labs/jumppad/pkg/clients/http"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/telemetry"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
@@ -29,11 +32,15 @@ type Provider struct {
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	cli, err := clients.GenerateClients(l)
+	cli, err := clients.DefaultPool(l).Clients()
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	p.client = cli.ContainerTasks
 	p.httpClient = cli.HTTP
 	p.log = l
@@ -97,6 +104,95 @@ func (p *Provider) Lookup() ([]string, error) {
 	return p.client.FindContainerIDs(p.config.ContainerName)
 }
 
+// Drift checks that the container exists and is running
+func (p *Provider) Drift() ([]string, error) {
+	return container.ContainerDrift(p.client, p.config.ContainerName)
+}
+
+// Stop stops the container without removing it
+func (p *Provider) Stop(ctx context.Context) error {
+	p.log.Info("Stopping container", "ref", p.config.Meta.ID)
+
+	return container.StopContainers(p.client, p.config.ContainerName)
+}
+
+// Start starts a stopped container
+func (p *Provider) Start(ctx context.Context) error {
+	p.log.Info("Starting container", "ref", p.config.Meta.ID)
+
+	return container.StartContainers(p.client, p.config.ContainerName)
+}
+
+// Import adopts an existing Docker container, the container must be running,
+// use the name that Jumppad gives to the resource and match the image and
+// networks in the configuration
+func (p *Provider) Import(ref string) error {
+	if p.sidecar != nil {
+		return fmt.Errorf("importing sidecar resources is not supported")
+	}
+
+	info, err := p.client.ContainerInfo(ref)
+	if err != nil {
+		return err
+	}
+
+	ci, ok := info.(dcontainer.InspectResponse)
+	if !ok || ci.ContainerJSONBase == nil || ci.Config == nil {
+		return fmt.Errorf("unable to read information about container %s", ref)
+	}
+
+	fqdn := utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type)
+	name := strings.TrimPrefix(ci.Name, "/")
+
+	if name != fqdn {
+		return fmt.Errorf(`container name "%s" does not match the name "%s" used by the resource, rename the container with "docker rename %s %s"`, name, fqdn, name, fqdn)
+	}
+
+	if ci.State == nil || !ci.State.Running {
+		return fmt.Errorf("container %s is not running", name)
+	}
+
+	if ci.Config.Image != p.config.Image.Name {
+		return fmt.Errorf(`container image "%s" does not match the image "%s" in the configuration`, ci.Config.Image, p.config.Image.Name)
+	}
+
+	attached := p.client.ListNetworks(ci.ID)
+
+	for i, n := range p.config.Networks {
+		net, err := p.client.FindNetwork(n.ID)
+		if err != nil {
+			return fmt.Errorf("unable to find network %s: %s", n.ID, err)
+		}
+
+		found := false
+		for _, a := range attached {
+			if a.Name != net.Name {
+				continue
+			}
+
+			// remove the netmask
+			ip, _, _ := strings.Cut(a.IPAddress, "/")
+			if n.IPAddress != "" && n.IPAddress != ip {
+				return fmt.Errorf(`container address "%s" on network %s does not match the address "%s" in the configuration`, ip, net.Name, n.IPAddress)
+			}
+
+			p.config.Networks[i].AssignedAddress = ip
+			p.config.Networks[i].Name = net.Name
+			found = true
+		}
+
+		if !found {
+			return fmt.Errorf("container %s is not attached to the network %s", name, net.Name)
+		}
+	}
+
+	// the image id is used to detect changes to the image
+	p.config.Image.ID = ci.Image
+	p.config.ContainerName = fqdn
+
+	return nil
+}
+
 func (c *Provider) Refresh(ctx context.Context) error {
 	if ctx.Err() != nil {
 		c.log.Debug("Context cancelled, skipping container refresh", "ref", c.config.Meta.ID)
@@ -163,7 +259,10 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		Password: c.config.Image.Password,
 	}
 
-	err := c.client.PullImage(img, false)
+	err := telemetry.Trace(ctx, "docker.pull_image", func(context.Context) error {
+		return c.client.PullImage(img, false)
+	}, telemetry.AttributeImage.String(img.Name))
+
 	if err != nil {
 		c.log.Error("Error pulling container image", "ref", c.config.Meta.ID, "image", c.config.Image.Name)
 
@@ -261,7 +360,11 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		}
 	}
 
-	id, err = c.client.CreateContainer(&new)
+	err = telemetry.Trace(ctx, "docker.create_container", func(context.Context) error {
+		id, err = c.client.CreateContainer(&new)
+		return err
+	}, telemetry.AttributeImage.String(img.Name))
+
 	if err != nil {
 		c.log.Error("Unable to create container", "ref", c.config.Meta.ID, "error", err)
 		return err
@@ -297,10 +400,12 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 
 	// execute tcp health checks
 	for _, hc := range c.config.HealthCheck.TCP {
-		err := c.httpClient.HealthCheckTCP(
-			hc.Address,
-			timeout,
-		)
+		err := events.HealthCheck(ctx, fmt.Sprintf("tcp %s", hc.Address), func() error {
+			return c.httpClient.HealthCheckTCP(
+				hc.Address,
+				timeout,
+			)
+		})
 
 		if err != nil {
 			return err
@@ -309,14 +414,16 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 
 	// execute http health checks
 	for _, hc := range c.config.HealthCheck.HTTP {
-		err := c.httpClient.HealthCheckHTTP(
-			hc.Address,
-			hc.Method,
-			hc.Headers,
-			hc.Body,
-			hc.SuccessCodes,
-			timeout,
-		)
+		err := events.HealthCheck(ctx, fmt.Sprintf("http %s", hc.Address), func() error {
+			return c.httpClient.HealthCheckHTTP(
+				hc.Address,
+				hc.Method,
+				hc.Headers,
+				hc.Body,
+				hc.SuccessCodes,
+				timeout,
+			)
+		})
 
 		if err != nil {
 			return err
@@ -324,7 +431,10 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 	}
 
 	for _, hc := range c.config.HealthCheck.Exec {
-		err := c.runExecHealthCheck(ctx, id, hc.Command, hc.Script, hc.ExitCode, timeout)
+		err := events.HealthCheck(ctx, "exec", func() error {
+			return c.runExecHealthCheck(ctx, id, hc.Command, hc.Script, hc.ExitCode, timeout)
+		})
+
 		if err != nil {
 			return err
 		}
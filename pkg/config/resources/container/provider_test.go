@@ -6,12 +6,14 @@ import (
 	"testing"
 	"time"
 
+	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	hmocks "github.com/jumppad-labs/jumppad/pkg/clients/http/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/jumppad-labs/jumppad/testutils"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
@@ -252,6 +254,31 @@ func TestContainerLooksupIDs(t *testing.T) {
 	assert.Equal(t, []string{"abc"}, ids)
 }
 
+func TestContainerStopStopsContainer(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	md.On("StopContainer", "abc").Return(nil)
+
+	err := p.Stop(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "StopContainer", "abc")
+	md.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
+}
+
+func TestContainerStartStartsContainer(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	md.On("StartContainer", "abc").Return(nil)
+
+	err := p.Start(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "StartContainer", "abc")
+}
+
 func TestContainerAddsResources(t *testing.T) {
 	cc, md, hc := setupContainerTests(t)
 	cc.Networks = []NetworkAttachment{NetworkAttachment{Name: "cloud"}}
@@ -275,3 +302,63 @@ func TestContainerAddsResources(t *testing.T) {
 	assert.Equal(t, "nvidia", ac.Resources.GPU.Driver)
 	assert.Equal(t, []string{"1"}, ac.Resources.GPU.DeviceIDs)
 }
+
+func setupImportTests(t *testing.T, name, image string) (*Provider, *mocks.ContainerTasks) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Image = Image{Name: "consul:1.16"}
+	cc.Networks = []NetworkAttachment{{ID: "resource.network.cloud"}}
+
+	md.On("ContainerInfo", "abc").Return(dcontainer.InspectResponse{
+		ContainerJSONBase: &dcontainer.ContainerJSONBase{
+			ID:    "abc",
+			Name:  "/" + name,
+			Image: "sha256:1234",
+			State: &dcontainer.State{Running: true, Status: "running"},
+		},
+		Config: &dcontainer.Config{Image: image},
+	}, nil)
+
+	md.On("FindNetwork", "resource.network.cloud").Return(ctypes.NetworkAttachment{ID: "net1", Name: "cloud"}, nil)
+	md.On("ListNetworks", "abc").Return([]ctypes.NetworkAttachment{{Name: "cloud", IPAddress: "10.5.0.2/16"}})
+
+	return &Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}, md
+}
+
+func TestContainerImportSetsComputedAttributes(t *testing.T) {
+	fqdn := utils.FQDN("tests", "", TypeContainer)
+	p, _ := setupImportTests(t, fqdn, "consul:1.16")
+
+	err := p.Import("abc")
+	assert.NoError(t, err)
+
+	assert.Equal(t, fqdn, p.config.ContainerName)
+	assert.Equal(t, "sha256:1234", p.config.Image.ID)
+	assert.Equal(t, "10.5.0.2", p.config.Networks[0].AssignedAddress)
+	assert.Equal(t, "cloud", p.config.Networks[0].Name)
+}
+
+func TestContainerImportWithDifferentNameReturnsError(t *testing.T) {
+	p, _ := setupImportTests(t, "mycontainer", "consul:1.16")
+
+	err := p.Import("abc")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "docker rename mycontainer")
+}
+
+func TestContainerImportWithDifferentImageReturnsError(t *testing.T) {
+	p, _ := setupImportTests(t, utils.FQDN("tests", "", TypeContainer), "consul:1.15")
+
+	err := p.Import("abc")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "consul:1.15")
+}
+
+func TestContainerImportNotAttachedToNetworkReturnsError(t *testing.T) {
+	p, md := setupImportTests(t, utils.FQDN("tests", "", TypeContainer), "consul:1.16")
+	testutils.RemoveOn(&md.Mock, "ListNetworks")
+	md.On("ListNetworks", "abc").Return([]ctypes.NetworkAttachment{{Name: "bridge"}})
+
+	err := p.Import("abc")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not attached to the network cloud")
+}
@@ -44,7 +44,7 @@ type Container struct {
 
 	// ContainerName is the fully qualified domain name for the container, this can be used
 	// to access the container from other sources
-	ContainerName string `hcl:"container_name,optional" json:"container_name,omitempty"`
+	ContainerName string `hcl:"container_name,optional" json:"container_name,omitempty" computed:"true"`
 }
 
 type User struct {
@@ -62,11 +62,11 @@ type NetworkAttachment struct {
 	// output
 
 	// Name will equal the name of the network as created by jumppad
-	Name string `hcl:"name,optional" json:"name,omitempty"`
+	Name string `hcl:"name,optional" json:"name,omitempty" computed:"true"`
 
 	// AssignedAddress will equal if IPAddress is set, else it will be the value automatically
 	// assigned from the network
-	AssignedAddress string `hcl:"assigned_address,optional" json:"assigned_address,omitempty"`
+	AssignedAddress string `hcl:"assigned_address,optional" json:"assigned_address,omitempty" computed:"true"`
 }
 
 type NetworkAttachments []NetworkAttachment
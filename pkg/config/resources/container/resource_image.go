@@ -7,14 +7,14 @@ type Image struct {
 	// Username is the Docker registry user to use for private repositories
 	Username string `hcl:"username,optional" json:"username,omitempty"`
 	// Password is the Docker registry password to use for private repositories
-	Password string `hcl:"password,optional" json:"password,omitempty"`
+	Password string `hcl:"password,optional" json:"password,omitempty" sensitive:"true"`
 
 	// output
 
 	// ID is the unique identifier for the image, this is independent of tag
 	// and changes each time the image is built. An image that has been tagged
 	// multiple times also shares the same ID.
-	ID string `hcl:"id,optional" json:"id,omitempty"`
+	ID string `hcl:"id,optional" json:"id,omitempty" computed:"true"`
 }
 
 type Images []Image
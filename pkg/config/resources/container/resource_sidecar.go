@@ -38,7 +38,7 @@ type Sidecar struct {
 
 	// ContainerName is the fully qualified domain name for the container the sidecar is linked to, this can be used
 	// to access the sidecar from other sources
-	ContainerName string `hcl:"container_name,optional" json:"container_name,omitempty"`
+	ContainerName string `hcl:"container_name,optional" json:"container_name,omitempty" computed:"true"`
 }
 
 func (c *Sidecar) Process() error {
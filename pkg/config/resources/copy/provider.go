@@ -24,16 +24,20 @@ type Provider struct {
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.DefaultPool(l).Clients()
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*Copy)
 	if !ok {
 		return fmt.Errorf("unable to initialize Copy provider, resource is not an instance of Copy")
 	}
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.getter = cli.Getter
 	p.config = c
 	p.log = l
@@ -86,16 +86,20 @@ type DocsProvider struct {
 }
 
 func (p *DocsProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.DefaultPool(l).Clients()
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *DocsProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*Docs)
 	if !ok {
 		return fmt.Errorf("unable to initialize Docs provider, resource is not of type Docs")
 	}
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.config = c
 	p.client = cli.ContainerTasks
 	p.log = l
@@ -154,6 +158,25 @@ func (p *DocsProvider) Lookup() ([]string, error) {
 	return p.client.FindContainerIDs(p.config.ContainerName)
 }
 
+// Drift checks that the docs container exists and is running
+func (p *DocsProvider) Drift() ([]string, error) {
+	return container.ContainerDrift(p.client, p.config.ContainerName)
+}
+
+// Stop stops the docs container without removing it
+func (p *DocsProvider) Stop(ctx context.Context) error {
+	p.log.Info("Stopping documentation", "ref", p.config.Meta.ID)
+
+	return container.StopContainers(p.client, p.config.ContainerName)
+}
+
+// Start starts the stopped docs container
+func (p *DocsProvider) Start(ctx context.Context) error {
+	p.log.Info("Starting documentation", "ref", p.config.Meta.ID)
+
+	return container.StartContainers(p.client, p.config.ContainerName)
+}
+
 func (p *DocsProvider) Refresh(ctx context.Context) error {
 	if ctx.Err() != nil {
 		p.log.Debug("Context is cancelled, skipping refresh", "ref", p.config.Meta.ID)
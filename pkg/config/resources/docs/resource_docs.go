@@ -31,11 +31,11 @@ type Docs struct {
 
 	// ContainerName is the fully qualified resource name for the container, this can be used
 	// to access the container from other sources
-	ContainerName string `hcl:"fqdn,optional" json:"fqdn,omitempty"`
+	ContainerName string `hcl:"fqdn,optional" json:"fqdn,omitempty" computed:"true"`
 
 	// ContentChecksum is the checksum of the content directory, this is used to determine if the
 	// docs need to be recreated
-	ContentChecksum string `hcl:"content_checksum,optional" json:"content_checksum,omitempty"`
+	ContentChecksum string `hcl:"content_checksum,optional" json:"content_checksum,omitempty" computed:"true"`
 }
 
 type Logo struct {
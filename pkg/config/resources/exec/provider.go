@@ -36,16 +36,20 @@ type Provider struct {
 
 // Intit creates a new Exec provider
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.DefaultPool(l).Clients()
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*Exec)
 	if !ok {
 		return fmt.Errorf("unable to initialize provider, resource is not of type Exec")
 	}
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.config = c
 	p.command = cli.Command
 	p.container = cli.ContainerTasks
@@ -34,10 +34,10 @@ type Exec struct {
 	RunAs    *ctypes.User               `hcl:"run_as,block" json:"run_as,omitempty"`    // User block for mapping the user id and group id inside the container
 
 	// output
-	PID      int       `hcl:"pid,optional" json:"pid,omitempty"`             // PID stores the ID of the created connector service if it is a local exec
-	ExitCode int       `hcl:"exit_code,optional" json:"exit_code,omitempty"` // Exit code of the process
-	Output   cty.Value `hcl:"output,optional" json:"output,omitempty"`       // output values returned from exec
-	Checksum string    `hcl:"checksum,optional" json:"checksum,omitempty"`   // Checksum of the script
+	PID      int       `hcl:"pid,optional" json:"pid,omitempty" computed:"true"`             // PID stores the ID of the created connector service if it is a local exec
+	ExitCode int       `hcl:"exit_code,optional" json:"exit_code,omitempty" computed:"true"` // Exit code of the process
+	Output   cty.Value `hcl:"output,optional" json:"output,omitempty" computed:"true"`       // output values returned from exec
+	Checksum string    `hcl:"checksum,optional" json:"checksum,omitempty" computed:"true"`   // Checksum of the script
 }
 
 func (e *Exec) Process() error {
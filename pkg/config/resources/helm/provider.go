@@ -11,6 +11,7 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/helm"
 	"github.com/jumppad-labs/jumppad/pkg/clients/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/telemetry"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
@@ -26,6 +27,15 @@ type Provider struct {
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.DefaultPool(l).Clients()
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	h, ok := cfg.(*Helm)
 
 	if !ok {
@@ -34,11 +44,6 @@ func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
 
 	p.config = h
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.config = h
 	p.kubeClient = cli.Kubernetes
 	p.helmClient = cli.Helm
@@ -123,16 +128,18 @@ func (p *Provider) Create(ctx context.Context) error {
 				errChan <- err
 			}
 
-			err = p.helmClient.Create(
-				p.config.Cluster.KubeConfig.ConfigPath,
-				newName,
-				p.config.Namespace,
-				p.config.CreateNamespace,
-				p.config.SkipCRDs,
-				p.config.Chart,
-				p.config.Version,
-				p.config.Values,
-				p.config.ValuesString)
+			err = telemetry.Trace(ctx, "helm.install", func(context.Context) error {
+				return p.helmClient.Create(
+					p.config.Cluster.KubeConfig.ConfigPath,
+					newName,
+					p.config.Namespace,
+					p.config.CreateNamespace,
+					p.config.SkipCRDs,
+					p.config.Chart,
+					p.config.Version,
+					p.config.Values,
+					p.config.ValuesString)
+			})
 
 			if err == nil {
 				doneChan <- struct{}{}
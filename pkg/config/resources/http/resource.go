@@ -18,8 +18,8 @@ type HTTP struct {
 	Timeout string            `hcl:"timeout,optional" json:"timeout,omitempty"`
 
 	// Output parameters
-	Status int    `hcl:"status,optional" json:"status"`
-	Body   string `hcl:"body,optional" json:"body"`
+	Status int    `hcl:"status,optional" json:"status" computed:"true"`
+	Body   string `hcl:"body,optional" json:"body" computed:"true"`
 }
 
 func (t *HTTP) Process() error {
@@ -31,15 +31,15 @@ type Ingress struct {
 	// --- Output Params ----
 
 	// IngressId stores the ID of the created connector service
-	IngressID string `hcl:"ingress_id,optional" json:"ingress_id,omitempty"`
+	IngressID string `hcl:"ingress_id,optional" json:"ingress_id,omitempty" computed:"true"`
 
 	// LocalAddress is the fully qualified uri for accessing the resource from
 	// the local machine
-	LocalAddress string `hcl:"local_address,optional" json:"local_address,omitempty"`
+	LocalAddress string `hcl:"local_address,optional" json:"local_address,omitempty" computed:"true"`
 
 	// RemoteAddress is the fully qualified uri for accessing the resource
 	// in the remote machine
-	RemoteAddress string `hcl:"remote_address,optional" json:"remote_address,omitempty"`
+	RemoteAddress string `hcl:"remote_address,optional" json:"remote_address,omitempty" computed:"true"`
 }
 
 type TargetConfig struct {
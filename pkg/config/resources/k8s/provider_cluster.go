@@ -23,6 +23,7 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/http"
 	"github.com/jumppad-labs/jumppad/pkg/clients/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/telemetry"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 	"gopkg.in/yaml.v3"
@@ -47,16 +48,20 @@ type ClusterProvider struct {
 }
 
 func (p *ClusterProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.DefaultPool(l).Clients()
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *ClusterProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*Cluster)
 	if !ok {
 		return fmt.Errorf("unable to initialize Kubernetes cluster provider, resource is not of type K8sCluster")
 	}
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.config = c
 	p.client = cli.ContainerTasks
 	p.kubeClient = cli.Kubernetes
@@ -92,6 +97,37 @@ func (p *ClusterProvider) Lookup() ([]string, error) {
 	return p.client.FindContainerIDs(utils.FQDN(fmt.Sprintf("server.%s", p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type))
 }
 
+// Drift checks that the server container exists and is running
+func (p *ClusterProvider) Drift() ([]string, error) {
+	return cclient.ContainerDrift(p.client, utils.FQDN(fmt.Sprintf("server.%s", p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type))
+}
+
+// Stop stops the server container, the cluster state is kept in the
+// container so the cluster can be started again
+func (p *ClusterProvider) Stop(ctx context.Context) error {
+	p.log.Info("Stopping cluster", "ref", p.config.Meta.ID)
+
+	return cclient.StopContainers(p.client, utils.FQDN(fmt.Sprintf("server.%s", p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type))
+}
+
+// Start starts the server container and waits for the essential pods to be
+// running again
+func (p *ClusterProvider) Start(ctx context.Context) error {
+	p.log.Info("Starting cluster", "ref", p.config.Meta.ID)
+
+	err := cclient.StartContainers(p.client, utils.FQDN(fmt.Sprintf("server.%s", p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type))
+	if err != nil {
+		return err
+	}
+
+	p.kubeClient, err = p.kubeClient.SetConfig(p.config.KubeConfig.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	return p.kubeClient.HealthCheckPods(ctx, []string{"app=local-path-provisioner", "k8s-app=kube-dns"}, startTimeout)
+}
+
 func (p *ClusterProvider) Refresh(ctx context.Context) error {
 	if ctx.Err() != nil {
 		p.log.Debug("Skipping refresh, context cancelled", "ref", p.config.Meta.ID)
@@ -259,7 +295,10 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 
 	img := ctypes.Image{Name: p.config.Image.Name, Username: p.config.Image.Username, Password: p.config.Image.Password}
 	// pull the container image
-	err = p.client.PullImage(img, false)
+	err = telemetry.Trace(ctx, "docker.pull_image", func(context.Context) error {
+		return p.client.PullImage(img, false)
+	}, telemetry.AttributeImage.String(img.Name))
+
 	if err != nil {
 		return err
 	}
@@ -480,7 +519,12 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 
 	cc.Command = args
 
-	id, err := p.client.CreateContainer(cc)
+	var id string
+	err = telemetry.Trace(ctx, "docker.create_container", func(context.Context) error {
+		id, err = p.client.CreateContainer(cc)
+		return err
+	}, telemetry.AttributeImage.String(img.Name))
+
 	if err != nil {
 		return err
 	}
@@ -756,7 +800,10 @@ func (p *ClusterProvider) deployConnector(ctx context.Context, grpcPort, httpPor
 	}
 
 	// deploy the application config
-	err = p.kubeClient.Apply(files, true)
+	err = telemetry.Trace(ctx, "kubernetes.apply", func(context.Context) error {
+		return p.kubeClient.Apply(files, true)
+	})
+
 	if err != nil {
 		return fmt.Errorf("unable to apply configuration: %s", err)
 	}
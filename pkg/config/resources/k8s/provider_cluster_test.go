@@ -80,7 +80,7 @@ func setupClusterMocks(t *testing.T) (
 	mk.Mock.On("SetConfig", mock.Anything).Return(nil)
 	mk.Mock.On("HealthCheckPods", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mk.Mock.On("Apply", mock.Anything, mock.Anything).Return(nil)
-	mk.Mock.On("GetPodLogs", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mk.Mock.On("GetPodLogs", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 
 	rc, err := os.CreateTemp(tmpDir, "root.cert")
 	if err != nil {
@@ -440,7 +440,7 @@ func TestClusterK3sStreamsLogsWhenRunning(t *testing.T) {
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	logReader, err := mk.GetPodLogs(context.TODO(), mock.Anything, mock.Anything)
+	logReader, err := mk.GetPodLogs(context.TODO(), mock.Anything, mock.Anything, nil)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, logReader)
 	assert.NoError(t, logReader.Close())
@@ -686,6 +686,51 @@ func TestClusterK3sDestroyRemovesConfig(t *testing.T) {
 	assert.NoDirExists(t, dir)
 }
 
+func TestClusterK3sStopStopsServer(t *testing.T) {
+	cc, md, mk, mc := setupClusterMocks(t)
+	testutils.RemoveOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything).Return([]string{"found"}, nil)
+	md.On("StopContainer", "found").Return(nil)
+
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+
+	err := p.Stop(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "FindContainerIDs", "server.test.k8s-cluster.local.jmpd.in")
+	md.AssertCalled(t, "StopContainer", "found")
+	md.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
+}
+
+func TestClusterK3sStartStartsServerAndWaitsForPods(t *testing.T) {
+	cc, md, mk, mc := setupClusterMocks(t)
+	testutils.RemoveOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything).Return([]string{"found"}, nil)
+	md.On("StartContainer", "found").Return(nil)
+	cc.KubeConfig.ConfigPath = "/tmp/kubeconfig.yaml"
+
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+
+	err := p.Start(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "StartContainer", "found")
+	mk.AssertCalled(t, "SetConfig", "/tmp/kubeconfig.yaml")
+	mk.AssertCalled(t, "HealthCheckPods", mock.Anything, []string{"app=local-path-provisioner", "k8s-app=kube-dns"}, startTimeout)
+}
+
+func TestClusterK3sStartErrorsWhenPodsFail(t *testing.T) {
+	cc, md, mk, mc := setupClusterMocks(t)
+	testutils.RemoveOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything).Return([]string{"found"}, nil)
+	md.On("StartContainer", "found").Return(nil)
+	testutils.RemoveOn(&mk.Mock, "HealthCheckPods")
+	mk.On("HealthCheckPods", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+
+	err := p.Start(context.Background())
+	assert.Error(t, err)
+}
+
 func TestLookupReturnsIDs(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
 	"github.com/jumppad-labs/jumppad/pkg/clients/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/clients/telemetry"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
@@ -22,16 +25,20 @@ type ConfigProvider struct {
 }
 
 func (p *ConfigProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.DefaultPool(l).Clients()
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *ConfigProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*Config)
 	if !ok {
 		return fmt.Errorf("unable to initialize Config provider, resource is not of type K8sConfig")
 	}
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.config = c
 	p.client = cli.Kubernetes
 	p.log = l
@@ -56,7 +63,10 @@ func (p *ConfigProvider) create(ctx context.Context) error {
 		return err
 	}
 
-	err = p.client.Apply(p.config.Paths, p.config.WaitUntilReady)
+	err = telemetry.Trace(ctx, "kubernetes.apply", func(context.Context) error {
+		return p.client.Apply(p.config.Paths, p.config.WaitUntilReady)
+	})
+
 	if err != nil {
 		return err
 	}
@@ -68,7 +78,9 @@ func (p *ConfigProvider) create(ctx context.Context) error {
 			return fmt.Errorf("unable to parse healthcheck duration: %w", err)
 		}
 
-		err = p.client.HealthCheckPods(ctx, p.config.HealthCheck.Pods, to)
+		err = events.HealthCheck(ctx, fmt.Sprintf("pods %s", strings.Join(p.config.HealthCheck.Pods, ", ")), func() error {
+			return p.client.HealthCheckPods(ctx, p.config.HealthCheck.Pods, to)
+		})
 		if err != nil {
 			return fmt.Errorf("healthcheck failed after helm chart setup: %w", err)
 		}
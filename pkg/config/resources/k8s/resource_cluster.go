@@ -49,21 +49,21 @@ type Cluster struct {
 	// output parameters
 
 	// Kubernetes config details
-	KubeConfig KubeConfig `hcl:"kube_config,optional" json:"kube_config,omitempty"`
+	KubeConfig KubeConfig `hcl:"kube_config,optional" json:"kube_config,omitempty" computed:"true"`
 
 	// Port the API server is running on
-	APIPort int `hcl:"api_port,optional" json:"api_port,omitempty"`
+	APIPort int `hcl:"api_port,optional" json:"api_port,omitempty" computed:"true"`
 
 	// Port the connector is running on
-	ConnectorPort int `hcl:"connector_port,optional" json:"connector_port,omitempty"`
+	ConnectorPort int `hcl:"connector_port,optional" json:"connector_port,omitempty" computed:"true"`
 
 	// Fully qualified domain name for the container, this address can be
 	// used to reference the container within docker and from other containers
-	ContainerName string `hcl:"container_name,optional" json:"container_name,omitempty"`
+	ContainerName string `hcl:"container_name,optional" json:"container_name,omitempty" computed:"true"`
 
 	// ExternalIP is the ip address of the cluster, this generally resolves
 	// to the docker ip
-	ExternalIP string `hcl:"external_ip,optional" json:"external_ip,omitempty"`
+	ExternalIP string `hcl:"external_ip,optional" json:"external_ip,omitempty" computed:"true"`
 }
 
 type ClusterConfig struct {
@@ -29,7 +29,7 @@ type Config struct {
 
 	// JobChecksums store a checksum of the files or paths referenced in the Paths field
 	// this is used to detect when a file changes so that it can be re-applied
-	JobChecksums map[string]string `hcl:"job_checksums,optional" json:"job_checksums,omitempty"`
+	JobChecksums map[string]string `hcl:"job_checksums,optional" json:"job_checksums,omitempty" computed:"true"`
 }
 
 func (k *Config) Process() error {
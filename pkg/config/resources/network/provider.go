@@ -10,6 +10,7 @@ import (
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
 
@@ -19,22 +20,28 @@ var _ sdk.Provider = &Provider{}
 type Provider struct {
 	config *Network
 	client container.Docker
+	tasks  container.ContainerTasks
 	log    sdk.Logger
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.DefaultPool(l).Clients()
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*Network)
 	if !ok {
 		return fmt.Errorf("unable to initialize Network provider, resource is not of type Network")
 	}
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.config = c
 	p.client = cli.Docker
+	p.tasks = cli.ContainerTasks
 	p.log = l
 
 	return nil
@@ -75,7 +82,7 @@ func (p *Provider) Create(ctx context.Context) error {
 
 	// is the network name and subnet equal to one which already exists
 	for _, ne := range nets {
-		if ne.Name == p.config.Meta.Name {
+		if ne.Name == p.networkName() {
 			return fmt.Errorf("a Network already exists with the name: %s ref:%s", p.config.Meta.Name, p.config.Meta.ID)
 		}
 	}
@@ -126,7 +133,7 @@ func (p *Provider) Destroy(ctx context.Context, force bool) error {
 	}
 
 	if len(ids) == 1 {
-		return p.client.NetworkRemove(context.Background(), p.config.Meta.Name)
+		return p.client.NetworkRemove(context.Background(), p.networkName())
 	}
 
 	return nil
@@ -134,7 +141,7 @@ func (p *Provider) Destroy(ctx context.Context, force bool) error {
 
 // Lookup the ID for a network
 func (p *Provider) Lookup() ([]string, error) {
-	nets, err := p.getNetworks(p.config.Meta.Name)
+	nets, err := p.getNetworks(p.networkName())
 
 	if err != nil {
 		return nil, err
@@ -148,6 +155,44 @@ func (p *Provider) Lookup() ([]string, error) {
 	return ids, nil
 }
 
+// Drift checks that the Docker network exists
+func (p *Provider) Drift() ([]string, error) {
+	ids, err := p.Lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []string{fmt.Sprintf("network %s does not exist", p.networkName())}, nil
+	}
+
+	return nil, nil
+}
+
+// Import adopts an existing Docker network, Docker networks can not be
+// renamed so the network must use the name that Jumppad gives to the
+// resource and match the subnet in the configuration
+func (p *Provider) Import(ref string) error {
+	n, err := p.tasks.FindUnmanagedNetwork(ref)
+	if err != nil {
+		return err
+	}
+
+	if n.Name != p.networkName() {
+		return fmt.Errorf(`network name "%s" does not match the name "%s" used by the resource`, n.Name, p.networkName())
+	}
+
+	if n.Subnet != p.config.Subnet {
+		return fmt.Errorf(`network subnet "%s" does not match the subnet "%s" in the configuration`, n.Subnet, p.config.Subnet)
+	}
+
+	if n.IPv6Enabled != p.config.EnableIPv6 {
+		return fmt.Errorf(`network enable_ipv6 "%t" does not match the value "%t" in the configuration`, n.IPv6Enabled, p.config.EnableIPv6)
+	}
+
+	return nil
+}
+
 func (p *Provider) Refresh(ctx context.Context) error {
 	if ctx.Err() != nil {
 		p.log.Debug("Skipping refresh, context cancelled", "ref", p.config.Meta.ID)
@@ -181,15 +226,22 @@ func (p *Provider) createWithDriver(driver string) error {
 		Labels: map[string]string{
 			"created_by": "jumppad",
 			"id":         p.config.Meta.ID,
+			"workspace":  utils.CurrentWorkspace(),
 		},
 		Attachable: true,
 	}
 
-	_, err := p.client.NetworkCreate(context.Background(), p.config.Meta.Name, opts)
+	_, err := p.client.NetworkCreate(context.Background(), p.networkName(), opts)
 
 	return err
 }
 
+// networkName returns the name of the Docker network, networks share a
+// global namespace so the name is prefixed with the active workspace
+func (p *Provider) networkName() string {
+	return utils.WorkspaceResourceName(p.config.Meta.Name)
+}
+
 func (p *Provider) getNetworks(name string) ([]network.Summary, error) {
 	args := filters.NewArgs()
 	args.Add("name", name)
@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	ctypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/testutils"
 	"github.com/stretchr/testify/mock"
@@ -181,3 +182,40 @@ func TestCreateWithOverlappingSubnetReturnsError(t *testing.T) {
 	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
+
+func setupNetworkImportTests(t *testing.T, na ctypes.NetworkAttachment) *Provider {
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "testnetwork"}},
+	}
+	c.Subnet = "10.1.2.0/24"
+
+	_, p := setupNetworkTests(t, c)
+
+	mt := &mocks.ContainerTasks{}
+	mt.On("FindUnmanagedNetwork", "abc").Return(na, nil)
+	p.tasks = mt
+
+	return p
+}
+
+func TestNetworkImportWithMatchingNetworkReturnsNoError(t *testing.T) {
+	p := setupNetworkImportTests(t, ctypes.NetworkAttachment{ID: "abc", Name: "testnetwork", Subnet: "10.1.2.0/24"})
+
+	err := p.Import("abc")
+	assert.NoError(t, err)
+}
+
+func TestNetworkImportWithDifferentSubnetReturnsError(t *testing.T) {
+	p := setupNetworkImportTests(t, ctypes.NetworkAttachment{ID: "abc", Name: "testnetwork", Subnet: "10.1.0.0/16"})
+
+	err := p.Import("abc")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "10.1.0.0/16")
+}
+
+func TestNetworkImportWithDifferentNameReturnsError(t *testing.T) {
+	p := setupNetworkImportTests(t, ctypes.NetworkAttachment{ID: "abc", Name: "other", Subnet: "10.1.2.0/24"})
+
+	err := p.Import("abc")
+	assert.Error(t, err)
+}
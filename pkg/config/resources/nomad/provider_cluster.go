@@ -40,11 +40,15 @@ type ClusterProvider struct {
 var startTimeout = (300 * time.Second)
 
 func (p *ClusterProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	cli, err := clients.GenerateClients(l)
+	cli, err := clients.DefaultPool(l).Clients()
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *ClusterProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*NomadCluster)
 	if !ok {
 		return fmt.Errorf("unable to initialize NomadCluster provider, resource is not of type NomadCluster")
@@ -52,7 +56,7 @@ func (p *ClusterProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
 
 	p.config = c
 	p.client = cli.ContainerTasks
-	p.nomadClient = cli.Nomad
+	p.nomadClient = cli.NewNomadClient()
 	p.connector = cli.Connector
 	p.log = l
 
@@ -103,6 +107,48 @@ func (p *ClusterProvider) Lookup() ([]string, error) {
 	return ids, nil
 }
 
+// Drift checks that the server and client containers exist and are running
+func (p *ClusterProvider) Drift() ([]string, error) {
+	drift := []string{}
+
+	for _, n := range append([]string{p.config.ServerContainerName}, p.config.ClientContainerName...) {
+		d, err := cclients.ContainerDrift(p.client, n)
+		if err != nil {
+			return nil, err
+		}
+
+		drift = append(drift, d...)
+	}
+
+	return drift, nil
+}
+
+// Stop stops the client nodes and then the server, the containers are kept
+// so that the cluster can be started again
+func (p *ClusterProvider) Stop(ctx context.Context) error {
+	p.log.Info("Stopping cluster", "ref", p.config.Meta.ID)
+
+	names := append([]string{}, p.config.ClientContainerName...)
+	names = append(names, p.config.ServerContainerName)
+
+	return cclients.StopContainers(p.client, names...)
+}
+
+// Start starts the server and then the client nodes and waits for the nodes
+// to become healthy
+func (p *ClusterProvider) Start(ctx context.Context) error {
+	p.log.Info("Starting cluster", "ref", p.config.Meta.ID)
+
+	err := cclients.StartContainers(p.client, append([]string{p.config.ServerContainerName}, p.config.ClientContainerName...)...)
+	if err != nil {
+		return err
+	}
+
+	p.nomadClient.SetConfig(fmt.Sprintf("http://%s", p.config.ExternalIP), p.config.APIPort, p.config.ClientNodes+1)
+
+	return p.nomadClient.HealthCheckAPI(ctx, startTimeout)
+}
+
 // Refresh is called when `up` is run and the resource has been marked as created
 // checks the nodes are healthy and replaces if needed.
 func (p *ClusterProvider) Refresh(ctx context.Context) error {
@@ -362,7 +408,7 @@ func (p *ClusterProvider) createNomad(ctx context.Context) error {
 
 	// set the API server port to a random number
 	p.config.ConnectorPort = rand.Intn(utils.MaxRandomPort-utils.MinRandomPort) + utils.MinRandomPort
-	p.config.ConfigDir = path.Join(utils.JumppadHome(), strings.Replace(p.config.Meta.ID, ".", "_", -1), "config")
+	p.config.ConfigDir = path.Join(utils.WorkspaceDir(utils.CurrentWorkspace()), strings.Replace(p.config.Meta.ID, ".", "_", -1), "config")
 
 	// set the external IP to the address where the docker daemon is running
 	p.config.ExternalIP = utils.GetDockerIP()
@@ -8,6 +8,7 @@ import (
 
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
 	"github.com/jumppad-labs/jumppad/pkg/clients/nomad"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
@@ -24,18 +25,22 @@ type JobProvider struct {
 }
 
 func (p *JobProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	cli, err := clients.GenerateClients(l)
+	cli, err := clients.DefaultPool(l).Clients()
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *JobProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*NomadJob)
 	if !ok {
 		return fmt.Errorf("unable to initialize NomadJob provider, resource is not of type NomadJob")
 	}
 
 	p.config = c
-	p.client = cli.Nomad
+	p.client = cli.NewNomadClient()
 	p.log = l
 
 	return nil
@@ -69,24 +74,36 @@ func (p *JobProvider) Create(ctx context.Context) error {
 		}
 
 		for _, j := range p.config.HealthCheck.Jobs {
-			for {
-				if ctx.Err() != nil {
-					return fmt.Errorf("context cancelled, unable to wait for job health")
-				}
-
-				if time.Since(st) >= dur {
-					return fmt.Errorf("timeout waiting for job '%s' to start", j)
-				}
-
-				p.log.Debug("Checking health for", "ref", p.config.Meta.ID, "job", j)
-
-				s, err := p.client.JobRunning(j)
-				if err == nil && s {
-					p.log.Debug("Health passed for", "ref", p.config.Meta.ID, "job", j)
-					break
+			err := events.HealthCheck(ctx, fmt.Sprintf("job %s", j), func() error {
+				for {
+					if ctx.Err() != nil {
+						return fmt.Errorf("context cancelled, unable to wait for job health")
+					}
+
+					if time.Since(st) >= dur {
+						return fmt.Errorf("timeout waiting for job '%s' to start", j)
+					}
+
+					p.log.Debug("Checking health for", "ref", p.config.Meta.ID, "job", j)
+
+					s, err := p.client.JobRunning(j)
+					if err == nil && s {
+						p.log.Debug("Health passed for", "ref", p.config.Meta.ID, "job", j)
+						return nil
+					}
+
+					events.Publish(ctx, events.Event{
+						Type:       events.HealthCheckProgress,
+						Message:    fmt.Sprintf("job %s", j),
+						DurationMS: time.Since(st).Milliseconds(),
+					})
+
+					time.Sleep(1 * time.Second)
 				}
+			})
 
-				time.Sleep(1 * time.Second)
+			if err != nil {
+				return err
 			}
 		}
 
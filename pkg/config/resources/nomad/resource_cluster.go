@@ -42,23 +42,23 @@ type NomadCluster struct {
 	// Output Parameters
 
 	// The APIPort the server is running on
-	APIPort int `hcl:"api_port,optional" json:"api_port,omitempty"`
+	APIPort int `hcl:"api_port,optional" json:"api_port,omitempty" computed:"true"`
 
 	// The Port where the connector is running
-	ConnectorPort int `hcl:"connector_port,optional" json:"connector_port,omitempty"`
+	ConnectorPort int `hcl:"connector_port,optional" json:"connector_port,omitempty" computed:"true"`
 
 	// The directory where the server and client config is written to
-	ConfigDir string `hcl:"config_dir,optional" json:"config_dir,omitempty"`
+	ConfigDir string `hcl:"config_dir,optional" json:"config_dir,omitempty" computed:"true"`
 
 	// The fully qualified docker address for the server
-	ServerContainerName string `hcl:"server_container_name,optional" json:"server_container_name,omitempty"`
+	ServerContainerName string `hcl:"server_container_name,optional" json:"server_container_name,omitempty" computed:"true"`
 
 	// The fully qualified docker address for the client nodes
-	ClientContainerName []string `hcl:"client_container_name,optional" json:"client_container_name,omitempty"`
+	ClientContainerName []string `hcl:"client_container_name,optional" json:"client_container_name,omitempty" computed:"true"`
 
 	// ExternalIP is the ip address of the cluster, this generally resolves
 	// to the docker ip
-	ExternalIP string `hcl:"external_ip,optional" json:"external_ip,omitempty"`
+	ExternalIP string `hcl:"external_ip,optional" json:"external_ip,omitempty" computed:"true"`
 }
 
 const nomadBaseImage = "ghcr.io/jumppad-labs/nomad"
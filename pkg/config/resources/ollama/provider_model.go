@@ -24,16 +24,20 @@ type ModelProvider struct {
 
 // Init initializes the provider with the given configuration
 func (p *ModelProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.DefaultPool(l).Clients()
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *ModelProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*OllamaModel)
 	if !ok {
 		return fmt.Errorf("unable to cast resource to OllamaModel")
 	}
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.config = c
 	p.log = l
 	p.httpClient = cli.HTTP
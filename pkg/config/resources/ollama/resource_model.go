@@ -13,8 +13,8 @@ type OllamaModel struct {
 	Insecure bool   `json:"insecure" hcl:"insecure"`
 
 	// output fields
-	Digest string `json:"digest" hcl:"digest"`
-	Size   int64  `json:"size" hcl:"size"`
+	Digest string `json:"digest" hcl:"digest" computed:"true"`
+	Size   int64  `json:"size" hcl:"size" computed:"true"`
 }
 
 func (m *OllamaModel) Process() error {
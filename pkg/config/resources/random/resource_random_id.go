@@ -15,9 +15,9 @@ type RandomID struct {
 	ByteLength int64 `hcl:"byte_length" json:"byte_length"`
 
 	// Output parameters
-	Base64 string `hcl:"base64,optional" json:"base64"`
-	Hex    string `hcl:"hex,optional" json:"hex"`
-	Dec    string `hcl:"dec,optional" json:"dec"`
+	Base64 string `hcl:"base64,optional" json:"base64" computed:"true"`
+	Hex    string `hcl:"hex,optional" json:"hex" computed:"true"`
+	Dec    string `hcl:"dec,optional" json:"dec" computed:"true"`
 }
 
 func (c *RandomID) Process() error {
@@ -16,7 +16,7 @@ type RandomNumber struct {
 	Maximum int `hcl:"maximum" json:"maximum"`
 
 	// Output parameters
-	Value int `hcl:"value,optional" json:"value"`
+	Value int `hcl:"value,optional" json:"value" computed:"true"`
 }
 
 func (c *RandomNumber) Process() error {
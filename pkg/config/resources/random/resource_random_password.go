@@ -26,7 +26,7 @@ type RandomPassword struct {
 	MinUpper   int64 `hcl:"min_upper,optional" json:"min_upper"`
 
 	// Output parameters
-	Value string `hcl:"value,optional" json:"value"`
+	Value string `hcl:"value,optional" json:"value" sensitive:"true" computed:"true"`
 }
 
 func (c *RandomPassword) Process() error {
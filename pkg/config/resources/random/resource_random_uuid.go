@@ -13,7 +13,7 @@ type RandomUUID struct {
 	types.ResourceBase `hcl:",remain"`
 
 	// Output parameters
-	Value string `hcl:"value,optional" json:"value"`
+	Value string `hcl:"value,optional" json:"value" computed:"true"`
 }
 
 func (c *RandomUUID) Process() error {
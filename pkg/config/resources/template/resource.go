@@ -21,7 +21,7 @@ type Template struct {
 	Destination string               `hcl:"destination" json:"destination"`                // Destination filename to write
 	Variables   map[string]cty.Value `hcl:"variables,optional" json:"variables,omitempty"` // Variables to be processed in the template
 
-	Checksum string `hcl:"checksum,optional" json:"checksum,omitempty"` // Checksum of the parsed template
+	Checksum string `hcl:"checksum,optional" json:"checksum,omitempty" computed:"true"` // Checksum of the parsed template
 }
 
 func (t *Template) Process() error {
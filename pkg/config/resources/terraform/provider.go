@@ -35,16 +35,20 @@ type TerraformProvider struct {
 }
 
 func (p *TerraformProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.DefaultPool(l).Clients()
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *TerraformProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*Terraform)
 	if !ok {
 		return fmt.Errorf("unable to initialize Terraform provider, resource is not of type Terraform")
 	}
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.config = c
 	p.client = cli.ContainerTasks
 	p.log = l
@@ -29,9 +29,9 @@ type Terraform struct {
 
 	// Computed values
 
-	Output         cty.Value `hcl:"output,optional"`                                           // output values returned from Terraform
-	SourceChecksum string    `hcl:"source_checksum,optional" json:"source_checksum,omitempty"` // checksum of the source directory
-	ApplyOutput    string    `hcl:"apply_output,optional"`                                     // output from the terraform apply
+	Output         cty.Value `hcl:"output,optional" json:"output" computed:"true"`                             // output values returned from Terraform
+	SourceChecksum string    `hcl:"source_checksum,optional" json:"source_checksum,omitempty" computed:"true"` // checksum of the source directory
+	ApplyOutput    string    `hcl:"apply_output,optional" json:"apply_output,omitempty" computed:"true"`       // output from the terraform apply
 }
 
 func (t *Terraform) Process() error {
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jumppad-labs/hclconfig/types"
+)
+
+// computedTag marks a resource field as being set by the provider when the
+// resource is created rather than from the configuration e.g.
+//
+//	ContainerName string `hcl:"container_name,optional" json:"container_name,omitempty" computed:"true"`
+const computedTag = "computed"
+
+var taggedPathCache sync.Map
+
+// taggedType is the key for the cache of the paths of tagged fields
+type taggedType struct {
+	t   reflect.Type
+	tag string
+}
+
+// ComputedPaths returns the JSON paths of the attributes for the resource
+// that are set by the provider, e.g. "networks.assigned_address"
+func ComputedPaths(r types.Resource) []string {
+	return taggedTypePaths(reflect.TypeOf(r), computedTag)
+}
+
+// taggedTypePaths returns the paths of the fields with the given tag for the
+// type, the paths are cached as the types do not change
+func taggedTypePaths(t reflect.Type, tag string) []string {
+	key := taggedType{t, tag}
+	if p, ok := taggedPathCache.Load(key); ok {
+		return p.([]string)
+	}
+
+	paths := []string{}
+	walkTaggedFields(t, tag, "", map[reflect.Type]bool{}, &paths)
+
+	taggedPathCache.Store(key, paths)
+
+	return paths
+}
+
+// walkTaggedFields adds the paths of the fields with the given tag, when the
+// value of the tag is "true" the path of the field is added, otherwise the
+// value is the path of the attribute in the field
+func walkTaggedFields(t reflect.Type, tag, prefix string, visited map[reflect.Type]bool, paths *[]string) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || visited[t] {
+		return
+	}
+
+	// guard against recursive types
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		// embedded structs without a name are inlined by the JSON encoder
+		if f.Anonymous && name == "" {
+			walkTaggedFields(f.Type, tag, prefix, visited, paths)
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		path := prefix + name
+
+		switch s := f.Tag.Get(tag); s {
+		case "":
+			walkTaggedFields(f.Type, tag, path+".", visited, paths)
+		case "true":
+			*paths = append(*paths, path)
+		default:
+			*paths = append(*paths, path+"."+s)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testComputed struct {
+	types.ResourceBase `hcl:",remain"`
+
+	Image    string                `hcl:"image" json:"image"`
+	ID       string                `hcl:"id,optional" json:"id" computed:"true"`
+	Networks []testComputedNetwork `hcl:"network,block" json:"networks"`
+}
+
+type testComputedNetwork struct {
+	Name            string `hcl:"name" json:"name"`
+	AssignedAddress string `hcl:"assigned_address,optional" json:"assigned_address" computed:"true"`
+}
+
+func TestComputedPathsReturnsTaggedFields(t *testing.T) {
+	p := ComputedPaths(&testComputed{})
+
+	require.ElementsMatch(t, []string{"id", "networks.assigned_address"}, p)
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+)
+
+// SensitiveValue is shown in place of sensitive values
+const SensitiveValue = "(sensitive)"
+
+// sensitiveTag marks a resource field as containing sensitive data, when the
+// value of the tag is "true" the entire field is sensitive, otherwise the
+// value is the path of the sensitive attribute in the field e.g.
+//
+//	PrivateKey File `hcl:"private_key" json:"private_key" sensitive:"contents"`
+const sensitiveTag = "sensitive"
+
+// SensitiveFunction is the name of the function that marks a value as
+// sensitive e.g.
+//
+//	variable "db_password" {
+//	  default = sensitive("password")
+//	}
+const SensitiveFunction = "sensitive"
+
+// outputValuePath is the path of the value of an output resource
+const outputValuePath = "value"
+
+// variableDefaultPath is the path of the default value of a variable
+const variableDefaultPath = "default"
+
+var indexRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// SensitivePaths returns the JSON paths of the attributes for the resource
+// that contain sensitive data, e.g. "image.password"
+func SensitivePaths(r types.Resource) []string {
+	// copy the cached paths before appending
+	paths := append([]string{}, taggedTypePaths(reflect.TypeOf(r), sensitiveTag)...)
+
+	return append(paths, sensitiveValuePaths(r.Metadata().Type, r.Metadata().Properties)...)
+}
+
+// sensitiveValuePaths returns the path of the value for outputs and
+// variables that have been marked as sensitive
+func sensitiveValuePaths(typ string, props map[string]any) []string {
+	if props[constants.PropertySensitive] != true {
+		return nil
+	}
+
+	switch typ {
+	case resources.TypeOutput:
+		return []string{outputValuePath}
+	case resources.TypeVariable:
+		return []string{variableDefaultPath}
+	}
+
+	return nil
+}
+
+// IsSensitiveReference returns true when the given reference, e.g.
+// resource.random_password.db.value, refers to an attribute that contains
+// sensitive data
+func IsSensitiveReference(ref string) bool {
+	fqrn, err := resources.ParseFQRN(ref)
+	if err != nil {
+		return false
+	}
+
+	rt, ok := registeredTypes[fqrn.Type]
+	if !ok {
+		return false
+	}
+
+	attr := indexRegex.ReplaceAllString(fqrn.Attribute, "")
+
+	for _, p := range taggedTypePaths(reflect.TypeOf(rt), sensitiveTag) {
+		// referencing the parent of a sensitive attribute also references
+		// the sensitive value
+		if attr == "" || attr == p || strings.HasPrefix(attr, p+".") || strings.HasPrefix(p, attr+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RedactResource returns the JSON representation of the resource with the
+// sensitive values replaced by SensitiveValue
+func RedactResource(r types.Resource) (map[string]any, error) {
+	m, err := resourceMap(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range SensitivePaths(r) {
+		replaceSensitivePath(m, strings.Split(p, "."), func(v any) (any, error) {
+			return SensitiveValue, nil
+		})
+	}
+
+	return m, nil
+}
+
+// SensitiveValues returns all the strings in the resource that contain
+// sensitive data
+func SensitiveValues(r types.Resource) []string {
+	// the default of a variable is the expression rather than the value, the
+	// values of sensitive variables are added when the config is parsed
+	if r.Metadata().Type == resources.TypeVariable {
+		return nil
+	}
+
+	paths := SensitivePaths(r)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	m, err := resourceMap(r)
+	if err != nil {
+		return nil
+	}
+
+	values := []string{}
+	for _, p := range paths {
+		replaceSensitivePath(m, strings.Split(p, "."), func(v any) (any, error) {
+			values = append(values, stringValues(v)...)
+			return v, nil
+		})
+	}
+
+	return values
+}
+
+// resourceMap returns the JSON representation of the resource as a map
+func resourceMap(r types.Resource) (map[string]any, error) {
+	d, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize resource to JSON: %s", err)
+	}
+
+	m := map[string]any{}
+	err = json.Unmarshal(d, &m)
+	if err != nil {
+		return nil, fmt.Errorf("unable to deserialize resource: %s", err)
+	}
+
+	return m, nil
+}
+
+// replaceSensitivePath calls f for each value at the given path and replaces
+// the value with the result, lists are traversed so the path does not
+// contain indexes
+func replaceSensitivePath(v any, path []string, f func(v any) (any, error)) error {
+	switch vv := v.(type) {
+	case []any:
+		for _, i := range vv {
+			err := replaceSensitivePath(i, path, f)
+			if err != nil {
+				return err
+			}
+		}
+
+	case map[string]any:
+		c, ok := vv[path[0]]
+		if !ok || c == nil {
+			return nil
+		}
+
+		if len(path) > 1 {
+			return replaceSensitivePath(c, path[1:], f)
+		}
+
+		nv, err := f(c)
+		if err != nil {
+			return err
+		}
+
+		vv[path[0]] = nv
+	}
+
+	return nil
+}
+
+// stringValues returns the non empty strings contained in v
+func stringValues(v any) []string {
+	values := []string{}
+
+	switch vv := v.(type) {
+	case string:
+		if vv != "" {
+			values = append(values, vv)
+		}
+	case []any:
+		for _, i := range vv {
+			values = append(values, stringValues(i)...)
+		}
+	case map[string]any:
+		for _, i := range vv {
+			values = append(values, stringValues(i)...)
+		}
+	}
+
+	return values
+}
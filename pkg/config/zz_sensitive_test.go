@@ -0,0 +1,105 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/stretchr/testify/require"
+)
+
+const typeTestSecret = "test_secret"
+
+type testSecret struct {
+	types.ResourceBase `hcl:",remain"`
+
+	Username string           `hcl:"username" json:"username"`
+	Password string           `hcl:"password" json:"password" sensitive:"true"`
+	Auth     []testSecretAuth `hcl:"auth,block" json:"auth"`
+	Key      testSecretFile   `hcl:"key,optional" json:"key" sensitive:"contents"`
+}
+
+type testSecretAuth struct {
+	Token *string `hcl:"token" json:"token" sensitive:"true"`
+}
+
+type testSecretFile struct {
+	Path     string `hcl:"path" json:"path"`
+	Contents string `hcl:"contents" json:"contents"`
+}
+
+func setupSensitiveTests(t *testing.T) *testSecret {
+	RegisterResource(typeTestSecret, &testSecret{}, nil)
+
+	t.Cleanup(func() {
+		delete(registeredTypes, typeTestSecret)
+	})
+
+	token := "token123"
+
+	return &testSecret{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.test_secret.db", Name: "db", Type: typeTestSecret}},
+		Username:     "admin",
+		Password:     "secret123",
+		Auth:         []testSecretAuth{{Token: &token}},
+		Key:          testSecretFile{Path: "/certs/key.pem", Contents: "private-key"},
+	}
+}
+
+func TestSensitivePathsReturnsTaggedFields(t *testing.T) {
+	s := setupSensitiveTests(t)
+
+	require.ElementsMatch(t, []string{"password", "auth.token", "key.contents"}, SensitivePaths(s))
+}
+
+func TestSensitivePathsIncludesValueForSensitiveOutputs(t *testing.T) {
+	o := &resources.Output{ResourceBase: types.ResourceBase{Meta: types.Meta{Type: resources.TypeOutput}}}
+	require.Empty(t, SensitivePaths(o))
+
+	o.Meta.Properties = map[string]any{constants.PropertySensitive: true}
+	require.Equal(t, []string{"value"}, SensitivePaths(o))
+}
+
+func TestIsSensitiveReference(t *testing.T) {
+	setupSensitiveTests(t)
+
+	tt := map[string]bool{
+		"resource.test_secret.db.password":            true,
+		"resource.test_secret.db.auth[0].token":       true,
+		"resource.test_secret.db.key":                 true,
+		"resource.test_secret.db.key.contents":        true,
+		"module.app.resource.test_secret.db.password": true,
+		"resource.test_secret.db":                     true,
+		"resource.test_secret.db.username":            false,
+		"resource.test_secret.db.key.path":            false,
+		"resource.container.db.password":              false,
+		"not a reference":                             false,
+	}
+
+	for ref, expected := range tt {
+		require.Equal(t, expected, IsSensitiveReference(ref), ref)
+	}
+}
+
+func TestRedactResourceReplacesSensitiveValues(t *testing.T) {
+	s := setupSensitiveTests(t)
+
+	m, err := RedactResource(s)
+	require.NoError(t, err)
+
+	require.Equal(t, "admin", m["username"])
+	require.Equal(t, SensitiveValue, m["password"])
+	require.Equal(t, SensitiveValue, m["auth"].([]any)[0].(map[string]any)["token"])
+	require.Equal(t, "/certs/key.pem", m["key"].(map[string]any)["path"])
+	require.Equal(t, SensitiveValue, m["key"].(map[string]any)["contents"])
+
+	// the resource is not modified
+	require.Equal(t, "secret123", s.Password)
+}
+
+func TestSensitiveValuesReturnsSensitiveStrings(t *testing.T) {
+	s := setupSensitiveTests(t)
+
+	require.ElementsMatch(t, []string{"secret123", "token123", "private-key"}, SensitiveValues(s))
+}
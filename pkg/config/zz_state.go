@@ -5,11 +5,34 @@ import (
 	"os"
 
 	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
+// LoadState reads the state for the active workspace from the configured
+// backend. The state lock is not acquired, writes replace the state
+// atomically so reads always return a complete state, commands that modify
+// the state must hold the lock from LockState for the whole read, modify,
+// write cycle. State written by older versions of jumppad is migrated to the
+// current StateVersion. When there is no state the returned error satisfies
+// errors.Is(err, os.ErrNotExist)
 func LoadState() (*hclconfig.Config, error) {
-	d, err := os.ReadFile(utils.StatePath())
+	b, err := stateBackend()
+	if err != nil {
+		return hclconfig.NewConfig(), err
+	}
+
+	d, err := b.Read()
+	if err != nil {
+		return hclconfig.NewConfig(), fmt.Errorf("unable to read state file: %w", err)
+	}
+
+	d, err = decryptState(d)
+	if err != nil {
+		return hclconfig.NewConfig(), fmt.Errorf("unable to read state file: %s", err)
+	}
+
+	d, err = migrateState(d)
 	if err != nil {
 		return hclconfig.NewConfig(), fmt.Errorf("unable to read state file: %s", err)
 	}
@@ -20,25 +43,141 @@ func LoadState() (*hclconfig.Config, error) {
 		return hclconfig.NewConfig(), fmt.Errorf("unable to unmarshal state file: %s", err)
 	}
 
+	// ensure the sensitive values in the state are never logged
+	for _, r := range c.Resources {
+		logger.AddSensitiveValues(SensitiveValues(r)...)
+	}
+
 	return c, nil
 }
 
+// SaveState writes the state for the active workspace, the state lock is
+// held while the file is written. A copy of the state is added to the
+// state history. When the state key exists the sensitive values are
+// encrypted.
+// The local backend writes the state to a temporary file which replaces the
+// existing state once complete, this ensures that the state is never
+// truncated should the process exit during the write
 func SaveState(c *hclconfig.Config) error {
 	// save the state regardless of error
-	d, err := c.ToJSON()
+	d, err := serializeState(c)
+	if err != nil {
+		return err
+	}
+
+	d, err = encryptState(d)
+	if err != nil {
+		return fmt.Errorf("unable to encrypt state: %s", err)
+	}
+
+	b, err := stateBackend()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := LockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = b.Write(d)
+	if err != nil {
+		return fmt.Errorf("unable to write state file '%s', error: %s", b.Location(), err)
+	}
+
+	return saveStateSnapshot(d)
+}
+
+// RemoveState deletes the state file for the active workspace, an empty
+// state is added to the state history so that the previous state can be
+// restored
+func RemoveState() error {
+	b, err := stateBackend()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := LockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = b.Delete()
+	if err != nil {
+		return err
+	}
+
+	d, err := serializeState(hclconfig.NewConfig())
+	if err != nil {
+		return err
+	}
+
+	return saveStateSnapshot(d)
+}
+
+// writeFileAtomic writes the data to a temporary file in the same folder
+// as path and then renames it, rename is atomic when both files are on
+// the same volume
+func writeFileAtomic(path string, d []byte) error {
+	f, err := os.CreateTemp(utils.StateDir(), "state-*.tmp")
+	if err != nil {
+		return err
+	}
+
+	// clean up the temp file if anything fails
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(d)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Sync()
 	if err != nil {
-		return fmt.Errorf("unable to serialize config to JSON: %s", err)
+		f.Close()
+		return err
 	}
 
-	err = os.MkdirAll(utils.StateDir(), os.ModePerm)
+	err = f.Close()
 	if err != nil {
-		return fmt.Errorf("unable to create directory for state file '%s', error: %s", utils.StateDir(), err)
+		return err
 	}
 
-	err = os.WriteFile(utils.StatePath(), d, os.ModePerm)
+	err = os.Chmod(f.Name(), 0644)
 	if err != nil {
-		return fmt.Errorf("unable to write state file '%s', error: %s", utils.StatePath(), err)
+		return err
 	}
 
-	return nil
+	return os.Rename(f.Name(), path)
+}
+
+// MarshalState serializes the state to the versioned document that is written
+// by SaveState, sensitive values are not encrypted
+func MarshalState(c *hclconfig.Config) ([]byte, error) {
+	return serializeState(c)
+}
+
+// ParseState parses state that has been serialized with MarshalState, state
+// written by older versions of jumppad is migrated
+func ParseState(d []byte) (*hclconfig.Config, error) {
+	d, err := decryptState(d)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state: %s", err)
+	}
+
+	d, err = migrateState(d)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state: %s", err)
+	}
+
+	p := NewParser(nil, nil, nil)
+	c, err := p.UnmarshalJSON(d)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal state: %s", err)
+	}
+
+	return c, nil
 }
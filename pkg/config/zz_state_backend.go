@@ -0,0 +1,289 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+const (
+	BackendTypeLocal = "local"
+	BackendTypeHTTP  = "http"
+	BackendTypeS3    = "s3"
+)
+
+// BackendEnvVar is the environment variable that sets the type of backend
+// used to store the state, the attributes for the backend are set with
+// variables prefixed with BackendEnvVar, e.g. JUMPPAD_BACKEND_ADDRESS
+const BackendEnvVar = "JUMPPAD_BACKEND"
+
+// StateBackend stores the state and the state lock for the active workspace
+type StateBackend interface {
+	// Read returns the state, an error satisfying os.IsNotExist is returned
+	// when there is no state
+	Read() ([]byte, error)
+
+	// Write replaces the state
+	Write(d []byte) error
+
+	// Delete removes the state, an error satisfying os.IsNotExist is
+	// returned when there is no state
+	Delete() error
+
+	// Lock creates the state lock, an error satisfying errors.Is(err,
+	// os.ErrExist) is returned when the state is already locked
+	Lock(l StateLock) error
+
+	// ReadLock returns the current state lock, an error satisfying
+	// os.IsNotExist is returned when the state is not locked
+	ReadLock() (*StateLock, error)
+
+	// Unlock removes the state lock, it is not an error to unlock state
+	// that is not locked
+	Unlock() error
+
+	// Location returns a description of where the state is stored
+	Location() string
+}
+
+// BackendConfig configures the backend used to store the state, only the
+// attributes for the given type are used
+type BackendConfig struct {
+	Type string `hcl:"type,label"`
+
+	// http backend
+	Address       string `hcl:"address,optional"`
+	LockAddress   string `hcl:"lock_address,optional"`
+	UnlockAddress string `hcl:"unlock_address,optional"`
+	Username      string `hcl:"username,optional"`
+	Password      string `hcl:"password,optional"`
+
+	// s3 backend, credentials are read from the standard AWS environment
+	// variables and config files when access_key is not set
+	Bucket       string `hcl:"bucket,optional"`
+	Key          string `hcl:"key,optional"`
+	Region       string `hcl:"region,optional"`
+	Endpoint     string `hcl:"endpoint,optional"`
+	UsePathStyle bool   `hcl:"use_path_style,optional"`
+	AccessKey    string `hcl:"access_key,optional"`
+	SecretKey    string `hcl:"secret_key,optional"`
+}
+
+type settingsFile struct {
+	Jumppad *jumppadSettings `hcl:"jumppad,block"`
+	Remain  hcl.Body         `hcl:",remain"`
+}
+
+type jumppadSettings struct {
+	Backend *BackendConfig `hcl:"backend,block"`
+	Remain  hcl.Body       `hcl:",remain"`
+}
+
+// LoadBackendConfig returns the backend configuration from the settings
+// file, any attributes set with environment variables override the values
+// in the file. When no backend is configured the local backend is returned.
+func LoadBackendConfig() (*BackendConfig, error) {
+	bc := &BackendConfig{Type: BackendTypeLocal}
+
+	if _, err := os.Stat(utils.SettingsPath()); err == nil {
+		fc, err := readSettingsBackend(utils.SettingsPath())
+		if err != nil {
+			return nil, err
+		}
+
+		if fc != nil {
+			bc = fc
+		}
+	}
+
+	// changing the type with the environment does not use the attributes
+	// from the file as they are for a different backend
+	if t := os.Getenv(BackendEnvVar); t != "" && t != bc.Type {
+		bc = &BackendConfig{Type: t}
+	}
+
+	envs := map[string]*string{
+		"ADDRESS":        &bc.Address,
+		"LOCK_ADDRESS":   &bc.LockAddress,
+		"UNLOCK_ADDRESS": &bc.UnlockAddress,
+		"USERNAME":       &bc.Username,
+		"PASSWORD":       &bc.Password,
+		"BUCKET":         &bc.Bucket,
+		"KEY":            &bc.Key,
+		"REGION":         &bc.Region,
+		"ENDPOINT":       &bc.Endpoint,
+		"ACCESS_KEY":     &bc.AccessKey,
+		"SECRET_KEY":     &bc.SecretKey,
+	}
+
+	for k, v := range envs {
+		if e := os.Getenv(BackendEnvVar + "_" + k); e != "" {
+			*v = e
+		}
+	}
+
+	if e := os.Getenv(BackendEnvVar + "_USE_PATH_STYLE"); e != "" {
+		b, err := strconv.ParseBool(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s_USE_PATH_STYLE '%s', must be true or false", BackendEnvVar, e)
+		}
+
+		bc.UsePathStyle = b
+	}
+
+	return bc, nil
+}
+
+// readSettingsBackend returns the backend block from the settings file, nil
+// is returned when the file does not contain a backend
+func readSettingsBackend(path string) (*BackendConfig, error) {
+	f, diags := hclparse.NewParser().ParseHCLFile(path)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("unable to parse settings file '%s', error: %s", path, diags.Error())
+	}
+
+	s := &settingsFile{}
+	diags = gohcl.DecodeBody(f.Body, nil, s)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("unable to parse settings file '%s', error: %s", path, diags.Error())
+	}
+
+	if s.Jumppad == nil {
+		return nil, nil
+	}
+
+	return s.Jumppad.Backend, nil
+}
+
+// NewStateBackend returns the backend for the given configuration
+func NewStateBackend(bc *BackendConfig) (StateBackend, error) {
+	switch bc.Type {
+	case BackendTypeLocal:
+		return &localBackend{}, nil
+	case BackendTypeHTTP:
+		return newHTTPBackend(bc)
+	case BackendTypeS3:
+		return newS3Backend(bc)
+	}
+
+	return nil, fmt.Errorf("unknown state backend '%s', must be one of local, http, s3", bc.Type)
+}
+
+// the backend is created once and reused until the configuration or the
+// active workspace changes, creating the s3 backend loads the AWS config
+var backendMutex sync.Mutex
+var cachedBackend StateBackend
+var cachedBackendConfig BackendConfig
+var cachedBackendWorkspace string
+
+// stateBackend returns the configured backend for the state
+func stateBackend() (StateBackend, error) {
+	bc, err := LoadBackendConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	backendMutex.Lock()
+	defer backendMutex.Unlock()
+
+	ws := utils.CurrentWorkspace()
+	if cachedBackend != nil && cachedBackendConfig == *bc && cachedBackendWorkspace == ws {
+		return cachedBackend, nil
+	}
+
+	b, err := NewStateBackend(bc)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedBackend = b
+	cachedBackendConfig = *bc
+	cachedBackendWorkspace = ws
+
+	return b, nil
+}
+
+// workspaceKey prefixes the key for remote backends with the name of the
+// active workspace, the state for the default workspace is stored at key
+func workspaceKey(key string) string {
+	if utils.IsDefaultWorkspace() {
+		return key
+	}
+
+	return "workspaces/" + utils.CurrentWorkspace() + "/" + key
+}
+
+// notExist returns an error satisfying os.IsNotExist for the given location
+func notExist(op, location string) error {
+	return &os.PathError{Op: op, Path: location, Err: os.ErrNotExist}
+}
+
+// localBackend stores the state in the state folder for the active workspace
+type localBackend struct{}
+
+func (l *localBackend) Read() ([]byte, error) {
+	return os.ReadFile(utils.StatePath())
+}
+
+func (l *localBackend) Write(d []byte) error {
+	err := os.MkdirAll(utils.StateDir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for state file '%s', error: %s", utils.StateDir(), err)
+	}
+
+	return writeFileAtomic(utils.StatePath(), d)
+}
+
+func (l *localBackend) Delete() error {
+	return os.Remove(utils.StatePath())
+}
+
+func (l *localBackend) Lock(sl StateLock) error {
+	err := os.MkdirAll(utils.StateDir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for state lock '%s', error: %s", utils.StateDir(), err)
+	}
+
+	d, err := marshalStateLock(sl)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(utils.StateLockPath(), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(d)
+	return err
+}
+
+func (l *localBackend) ReadLock() (*StateLock, error) {
+	d, err := os.ReadFile(utils.StateLockPath())
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalStateLock(d, utils.StateLockPath())
+}
+
+func (l *localBackend) Unlock() error {
+	err := os.Remove(utils.StateLockPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (l *localBackend) Location() string {
+	return utils.StatePath()
+}
@@ -0,0 +1,320 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// httpBackendTimeout is the maximum time for a request to the http backend
+var httpBackendTimeout = 30 * time.Second
+
+// httpBackend stores the state with a REST endpoint using the same protocol
+// as the Terraform http backend. The state is read with GET, written with
+// POST and removed with DELETE. The state is locked and unlocked with the
+// LOCK and UNLOCK methods, when the state is already locked the server
+// returns 423 or 409 with the current lock as the body. The lock is sent as a
+// Terraform LockInfo.
+// For workspaces other than the default a workspace query parameter is added
+// to every request.
+type httpBackend struct {
+	address       string
+	lockAddress   string
+	unlockAddress string
+	username      string
+	password      string
+
+	client *http.Client
+
+	// lock is the lock created by this backend, it is sent with the UNLOCK
+	// request so the server can check the lock is held by the caller
+	lock *httpLockInfo
+
+	// conflict is the lock returned by the server when the last LOCK
+	// request was rejected
+	conflict *StateLock
+}
+
+// httpLockInfo has the fields of the Terraform LockInfo, servers for the
+// Terraform http backend use the ID to check that the UNLOCK request is
+// sent by the holder of the lock. Who is set to <pid>@<hostname> so that
+// stale locks can be detected.
+type httpLockInfo struct {
+	ID        string    `json:"ID"`
+	Operation string    `json:"Operation"`
+	Info      string    `json:"Info"`
+	Who       string    `json:"Who"`
+	Version   string    `json:"Version"`
+	Created   time.Time `json:"Created"`
+	Path      string    `json:"Path"`
+}
+
+func newHTTPLockInfo(l StateLock, path string) httpLockInfo {
+	return httpLockInfo{
+		ID:        uuid.NewString(),
+		Operation: l.Command,
+		Who:       fmt.Sprintf("%d@%s", l.PID, l.Hostname),
+		Created:   l.Created,
+		Path:      path,
+	}
+}
+
+// stateLock returns the lock details, locks created by other clients such
+// as Terraform do not contain a pid and are never considered stale
+func (i httpLockInfo) stateLock() *StateLock {
+	l := &StateLock{Hostname: i.Who, Command: i.Operation, Created: i.Created}
+
+	if pid, host, ok := strings.Cut(i.Who, "@"); ok {
+		if p, err := strconv.Atoi(pid); err == nil {
+			l.PID = p
+			l.Hostname = host
+		}
+	}
+
+	return l
+}
+
+func unmarshalHTTPLockInfo(d []byte, location string) (*httpLockInfo, error) {
+	i := &httpLockInfo{}
+	err := json.Unmarshal(d, i)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state lock '%s', error: %s", location, err)
+	}
+
+	return i, nil
+}
+
+func newHTTPBackend(bc *BackendConfig) (*httpBackend, error) {
+	if bc.Address == "" {
+		return nil, fmt.Errorf("address must be set for the http state backend")
+	}
+
+	h := &httpBackend{
+		address:       bc.Address,
+		lockAddress:   bc.LockAddress,
+		unlockAddress: bc.UnlockAddress,
+		username:      bc.Username,
+		password:      bc.Password,
+		client:        &http.Client{Timeout: httpBackendTimeout},
+	}
+
+	if h.lockAddress == "" {
+		h.lockAddress = h.address
+	}
+
+	if h.unlockAddress == "" {
+		h.unlockAddress = h.lockAddress
+	}
+
+	return h, nil
+}
+
+func (h *httpBackend) Read() ([]byte, error) {
+	resp, d, err := h.do(http.MethodGet, h.address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if len(d) == 0 {
+			return nil, notExist("read", h.Location())
+		}
+
+		return d, nil
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil, notExist("read", h.Location())
+	}
+
+	return nil, unexpectedStatus(resp, d)
+}
+
+func (h *httpBackend) Write(d []byte) error {
+	resp, body, err := h.do(http.MethodPost, h.address, d)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	}
+
+	return unexpectedStatus(resp, body)
+}
+
+func (h *httpBackend) Delete() error {
+	resp, body, err := h.do(http.MethodDelete, h.address, nil)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return notExist("delete", h.Location())
+	}
+
+	return unexpectedStatus(resp, body)
+}
+
+// Lock sends the LOCK request, when the state is already locked the lock
+// returned by the server is kept so that it can be returned by ReadLock
+func (h *httpBackend) Lock(l StateLock) error {
+	li := newHTTPLockInfo(l, h.Location())
+
+	d, err := json.Marshal(li)
+	if err != nil {
+		return fmt.Errorf("unable to serialize state lock, error: %s", err)
+	}
+
+	resp, body, err := h.do("LOCK", h.lockAddress, d)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		h.lock = &li
+		h.conflict = nil
+		return nil
+	case http.StatusLocked, http.StatusConflict:
+		cl, err := unmarshalHTTPLockInfo(body, h.lockAddress)
+		if err != nil {
+			return err
+		}
+
+		h.conflict = cl.stateLock()
+		return fmt.Errorf("state at '%s' is locked: %w", h.Location(), os.ErrExist)
+	}
+
+	return unexpectedStatus(resp, body)
+}
+
+// ReadLock returns the current lock with a GET request to the lock address.
+// The protocol does not define how to read the lock, when the server does
+// not return a lock the lock from the last rejected LOCK request is returned.
+func (h *httpBackend) ReadLock() (*StateLock, error) {
+	resp, d, err := h.do(http.MethodGet, h.lockAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// when the lock and state share an address the state is returned
+		li, err := unmarshalHTTPLockInfo(d, h.lockAddress)
+		if err == nil && li.ID != "" {
+			return li.stateLock(), nil
+		}
+	case http.StatusNoContent, http.StatusNotFound, http.StatusMethodNotAllowed:
+	default:
+		return nil, unexpectedStatus(resp, d)
+	}
+
+	if h.conflict != nil {
+		return h.conflict, nil
+	}
+
+	return nil, notExist("read lock", h.Location())
+}
+
+// Unlock sends the UNLOCK request with the lock created by this backend, when
+// the backend did not create the lock the request has no body which forces
+// the lock to be removed
+func (h *httpBackend) Unlock() error {
+	var d []byte
+	if h.lock != nil {
+		var err error
+		d, err = json.Marshal(h.lock)
+		if err != nil {
+			return fmt.Errorf("unable to serialize state lock, error: %s", err)
+		}
+	}
+
+	resp, body, err := h.do("UNLOCK", h.unlockAddress, d)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		h.lock = nil
+		h.conflict = nil
+		return nil
+	}
+
+	return unexpectedStatus(resp, body)
+}
+
+func (h *httpBackend) Location() string {
+	return workspaceURL(h.address)
+}
+
+// do sends the request and returns the response along with the body
+func (h *httpBackend) do(method, address string, body []byte) (*http.Response, []byte, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, workspaceURL(address), r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create request for http state backend: %s", err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if h.username != "" {
+		req.SetBasicAuth(h.username, h.password)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to connect to http state backend: %s", err)
+	}
+	defer resp.Body.Close()
+
+	d, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read response from http state backend: %s", err)
+	}
+
+	return resp, d, nil
+}
+
+// workspaceURL adds the workspace query parameter to the address when the
+// active workspace is not the default
+func workspaceURL(address string) string {
+	if utils.IsDefaultWorkspace() {
+		return address
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return address
+	}
+
+	q := u.Query()
+	q.Set("workspace", utils.CurrentWorkspace())
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func unexpectedStatus(resp *http.Response, body []byte) error {
+	return fmt.Errorf("unexpected response from http state backend %s %s, status: %d, body: %s", resp.Request.Method, resp.Request.URL, resp.StatusCode, string(body))
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// testHTTPBackendServer implements the http backend protocol, the state
+// and lock are stored in memory for each workspace
+type testHTTPBackendServer struct {
+	mutex   sync.Mutex
+	states  map[string][]byte
+	locks   map[string][]byte
+	users   []string
+	methods []string
+}
+
+func setupHTTPBackendTests(t *testing.T) *testHTTPBackendServer {
+	setupStateLockTests(t)
+
+	s := &testHTTPBackendServer{states: map[string][]byte{}, locks: map[string][]byte{}}
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	t.Setenv(BackendEnvVar, BackendTypeHTTP)
+	t.Setenv(BackendEnvVar+"_ADDRESS", ts.URL+"/state")
+	t.Setenv(BackendEnvVar+"_LOCK_ADDRESS", ts.URL+"/lock")
+	t.Setenv(BackendEnvVar+"_USERNAME", "nic")
+
+	return s
+}
+
+func (s *testHTTPBackendServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	u, _, _ := r.BasicAuth()
+	s.users = append(s.users, u)
+	s.methods = append(s.methods, r.Method)
+
+	ws := r.URL.Query().Get("workspace")
+	body, _ := io.ReadAll(r.Body)
+
+	switch r.Method {
+	case http.MethodGet:
+		if r.URL.Path == "/lock" {
+			if l, ok := s.locks[ws]; ok {
+				w.Write(l)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if d, ok := s.states[ws]; ok {
+			w.Write(d)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	case http.MethodPost:
+		s.states[ws] = body
+	case http.MethodDelete:
+		delete(s.states, ws)
+	case "LOCK":
+		if l, ok := s.locks[ws]; ok {
+			w.WriteHeader(http.StatusLocked)
+			w.Write(l)
+			return
+		}
+
+		s.locks[ws] = body
+	case "UNLOCK":
+		// like the Terraform http backend servers the lock is only removed
+		// when the ID matches or when no lock is sent to force the unlock
+		if len(body) > 0 {
+			held := httpLockInfo{}
+			json.Unmarshal(s.locks[ws], &held)
+
+			sent := httpLockInfo{}
+			json.Unmarshal(body, &sent)
+
+			if held.ID != sent.ID {
+				w.WriteHeader(http.StatusConflict)
+				w.Write(s.locks[ws])
+				return
+			}
+		}
+
+		delete(s.locks, ws)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *testHTTPBackendServer) setLock(t *testing.T, ws string, l StateLock) {
+	d, err := json.Marshal(newHTTPLockInfo(l, ""))
+	require.NoError(t, err)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.locks[ws] = d
+}
+
+func (s *testHTTPBackendServer) isLocked(ws string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, ok := s.locks[ws]
+	return ok
+}
+
+func TestHTTPBackendSavesAndLoadsState(t *testing.T) {
+	s := setupHTTPBackendTests(t)
+
+	c := hclconfig.NewConfig()
+	c.AppendResource(&resources.Output{ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "main", Type: resources.TypeOutput}}})
+
+	err := SaveState(c)
+	require.NoError(t, err)
+	require.Contains(t, string(s.states[""]), "output.main")
+	require.False(t, s.isLocked(""))
+
+	// the state is not written locally
+	require.NoFileExists(t, utils.StatePath())
+
+	c, err = LoadState()
+	require.NoError(t, err)
+	require.Len(t, c.Resources, 1)
+	require.False(t, s.isLocked(""))
+
+	require.Contains(t, s.users, "nic")
+}
+
+func TestHTTPBackendLoadStateDoesNotLock(t *testing.T) {
+	s := setupHTTPBackendTests(t)
+
+	err := SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+
+	s.setLock(t, "", StateLock{PID: 1, Hostname: "some-other-host", Command: "jumppad up"})
+	s.methods = nil
+
+	_, err = LoadState()
+	require.NoError(t, err)
+	require.Equal(t, []string{http.MethodGet}, s.methods)
+}
+
+func TestHTTPBackendLoadStateReturnsNotExistWhenNoState(t *testing.T) {
+	setupHTTPBackendTests(t)
+
+	_, err := LoadState()
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestHTTPBackendReturnsErrorWhenLocked(t *testing.T) {
+	s := setupHTTPBackendTests(t)
+
+	s.setLock(t, "", StateLock{PID: 1, Hostname: "some-other-host", Command: "jumppad up"})
+
+	err := SaveState(hclconfig.NewConfig())
+	require.ErrorAs(t, err, &StateLockedError{})
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, "some-other-host", l.Hostname)
+}
+
+func TestHTTPBackendReadStateLockDoesNotLock(t *testing.T) {
+	s := setupHTTPBackendTests(t)
+
+	_, err := ReadStateLock()
+	require.True(t, os.IsNotExist(err))
+	require.False(t, s.isLocked(""))
+
+	s.setLock(t, "", StateLock{PID: 1, Hostname: "some-other-host", Command: "jumppad up"})
+	s.methods = nil
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, 1, l.PID)
+	require.Equal(t, "some-other-host", l.Hostname)
+	require.Equal(t, []string{http.MethodGet}, s.methods)
+}
+
+func TestHTTPBackendSendsLockInfoWithID(t *testing.T) {
+	s := setupHTTPBackendTests(t)
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+
+	li := httpLockInfo{}
+	err = json.Unmarshal(s.locks[""], &li)
+	require.NoError(t, err)
+	require.NotEmpty(t, li.ID)
+	require.Equal(t, fmt.Sprintf("%d@%s", os.Getpid(), utils.GetHostname()), li.Who)
+
+	// the server only removes the lock when the ID matches
+	unlock()
+	require.False(t, s.isLocked(""))
+}
+
+func TestHTTPBackendReadsTerraformLock(t *testing.T) {
+	s := setupHTTPBackendTests(t)
+
+	s.mutex.Lock()
+	s.locks[""] = []byte(`{"ID":"1234","Operation":"OperationTypeApply","Who":"nic@laptop","Version":"1.9.0"}`)
+	s.mutex.Unlock()
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, "nic@laptop", l.Hostname)
+	require.False(t, l.IsStale())
+}
+
+func TestHTTPBackendForceUnlockRemovesLock(t *testing.T) {
+	s := setupHTTPBackendTests(t)
+
+	s.setLock(t, "", StateLock{PID: 1, Hostname: "some-other-host", Command: "jumppad up"})
+
+	err := ForceUnlockState()
+	require.NoError(t, err)
+	require.False(t, s.isLocked(""))
+}
+
+func TestHTTPBackendAddsWorkspaceToRequests(t *testing.T) {
+	s := setupHTTPBackendTests(t)
+	t.Setenv(utils.WorkspaceEnvVar, "nomad")
+
+	err := SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+
+	require.Contains(t, s.states, "nomad")
+	require.NotContains(t, s.states, "")
+}
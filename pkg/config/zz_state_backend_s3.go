@@ -0,0 +1,203 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+// s3BackendTimeout is the maximum time for a request to the s3 backend
+var s3BackendTimeout = 30 * time.Second
+
+const (
+	s3DefaultKey    = "jumppad/state.json"
+	s3DefaultRegion = "us-east-1"
+)
+
+// s3Backend stores the state as an object in an S3 compatible bucket, the
+// lock is stored as a second object next to the state which is created with
+// a conditional write so that only one process can hold the lock.
+// For workspaces other than the default the key is prefixed with
+// workspaces/<name>/.
+type s3Backend struct {
+	bucket string
+	key    string
+	client *s3.Client
+}
+
+func newS3Backend(bc *BackendConfig) (*s3Backend, error) {
+	if bc.Bucket == "" {
+		return nil, fmt.Errorf("bucket must be set for the s3 state backend")
+	}
+
+	key := bc.Key
+	if key == "" {
+		key = s3DefaultKey
+	}
+
+	opts := []func(*awsconfig.LoadOptions) error{}
+
+	if bc.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(bc.Region))
+	}
+
+	if bc.AccessKey != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(bc.AccessKey, bc.SecretKey, "")))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s3BackendTimeout)
+	defer cancel()
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load configuration for the s3 state backend: %s", err)
+	}
+
+	// S3 compatible servers such as MinIO do not need a region but the
+	// client will not sign requests without one
+	if cfg.Region == "" {
+		cfg.Region = s3DefaultRegion
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if bc.Endpoint != "" {
+			o.BaseEndpoint = aws.String(bc.Endpoint)
+		}
+
+		o.UsePathStyle = bc.UsePathStyle
+	})
+
+	return &s3Backend{bucket: bc.Bucket, key: workspaceKey(key), client: client}, nil
+}
+
+func (s *s3Backend) Read() ([]byte, error) {
+	return s.get(s.key)
+}
+
+func (s *s3Backend) Write(d []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s3BackendTimeout)
+	defer cancel()
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.key),
+		Body:        bytes.NewReader(d),
+		ContentType: aws.String("application/json"),
+	})
+
+	if err != nil {
+		return fmt.Errorf("unable to write state to s3: %s", err)
+	}
+
+	return nil
+}
+
+func (s *s3Backend) Delete() error {
+	// delete does not return an error when the object does not exist
+	_, err := s.get(s.key)
+	if err != nil {
+		return err
+	}
+
+	return s.delete(s.key)
+}
+
+func (s *s3Backend) Lock(l StateLock) error {
+	d, err := marshalStateLock(l)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s3BackendTimeout)
+	defer cancel()
+
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.lockKey()),
+		Body:        bytes.NewReader(d),
+		ContentType: aws.String("application/json"),
+		IfNoneMatch: aws.String("*"),
+	})
+
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && (ae.ErrorCode() == "PreconditionFailed" || ae.ErrorCode() == "ConditionalRequestConflict") {
+			return fmt.Errorf("state at '%s' is locked: %w", s.Location(), os.ErrExist)
+		}
+
+		return fmt.Errorf("unable to write state lock to s3: %s", err)
+	}
+
+	return nil
+}
+
+func (s *s3Backend) ReadLock() (*StateLock, error) {
+	d, err := s.get(s.lockKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalStateLock(d, "s3://"+s.bucket+"/"+s.lockKey())
+}
+
+func (s *s3Backend) Unlock() error {
+	return s.delete(s.lockKey())
+}
+
+func (s *s3Backend) Location() string {
+	return "s3://" + s.bucket + "/" + s.key
+}
+
+func (s *s3Backend) lockKey() string {
+	return s.key + ".lock"
+}
+
+// get returns the object with the given key, an error satisfying
+// os.IsNotExist is returned when the object does not exist
+func (s *s3Backend) get(key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s3BackendTimeout)
+	defer cancel()
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && (ae.ErrorCode() == "NoSuchKey" || ae.ErrorCode() == "NotFound") {
+			return nil, notExist("read", "s3://"+s.bucket+"/"+key)
+		}
+
+		return nil, fmt.Errorf("unable to read '%s' from s3: %s", key, err)
+	}
+	defer out.Body.Close()
+
+	return io.ReadAll(out.Body)
+}
+
+func (s *s3Backend) delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s3BackendTimeout)
+	defer cancel()
+
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		return fmt.Errorf("unable to delete '%s' from s3: %s", key, err)
+	}
+
+	return nil
+}
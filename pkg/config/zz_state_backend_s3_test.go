@@ -0,0 +1,150 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testS3Server implements the S3 object requests used by the backend, the
+// objects are stored in memory
+type testS3Server struct {
+	mutex   sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *testS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// requests use path style, /bucket/key
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if key == "" {
+		// create bucket
+		return
+	}
+
+	k := bucket + "/" + key
+
+	switch r.Method {
+	case http.MethodGet:
+		d, ok := s.objects[k]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+
+		w.Write(d)
+	case http.MethodPut:
+		if _, ok := s.objects[k]; ok && r.Header.Get("If-None-Match") == "*" {
+			writeS3Error(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+
+		d, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "InvalidRequest")
+			return
+		}
+
+		s.objects[k] = d
+	case http.MethodDelete:
+		delete(s.objects, k)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+}
+
+// setupS3BackendTests configures the s3 backend, the tests use an in memory
+// server unless JUMPPAD_TEST_S3_ENDPOINT is set to the address of a MinIO
+// server, the server can be started with the blueprint in
+// examples/state_backend. A new bucket is created for each test.
+func setupS3BackendTests(t *testing.T) {
+	setupStateLockTests(t)
+
+	endpoint := os.Getenv("JUMPPAD_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		ts := httptest.NewServer(&testS3Server{objects: map[string][]byte{}})
+		t.Cleanup(ts.Close)
+
+		endpoint = ts.URL
+	}
+
+	bucket := fmt.Sprintf("jumppad-test-%d", time.Now().UnixNano())
+
+	t.Setenv(BackendEnvVar, BackendTypeS3)
+	t.Setenv(BackendEnvVar+"_BUCKET", bucket)
+	t.Setenv(BackendEnvVar+"_ENDPOINT", endpoint)
+	t.Setenv(BackendEnvVar+"_USE_PATH_STYLE", "true")
+	t.Setenv(BackendEnvVar+"_ACCESS_KEY", "minioadmin")
+	t.Setenv(BackendEnvVar+"_SECRET_KEY", "minioadmin")
+
+	b, err := stateBackend()
+	require.NoError(t, err)
+
+	_, err = b.(*s3Backend).client.CreateBucket(context.Background(), &s3.CreateBucketInput{Bucket: aws.String(bucket)})
+	require.NoError(t, err)
+}
+
+func TestS3BackendSavesAndLoadsState(t *testing.T) {
+	setupS3BackendTests(t)
+
+	_, err := LoadState()
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	c := hclconfig.NewConfig()
+	c.AppendResource(&resources.Output{ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "main", Type: resources.TypeOutput}}})
+
+	err = SaveState(c)
+	require.NoError(t, err)
+
+	c, err = LoadState()
+	require.NoError(t, err)
+	require.Len(t, c.Resources, 1)
+
+	err = RemoveState()
+	require.NoError(t, err)
+
+	_, err = LoadState()
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestS3BackendReturnsErrorWhenLocked(t *testing.T) {
+	setupS3BackendTests(t)
+
+	b, err := stateBackend()
+	require.NoError(t, err)
+
+	err = b.Lock(StateLock{PID: 1, Hostname: "some-other-host", Command: "jumppad up"})
+	require.NoError(t, err)
+
+	err = SaveState(hclconfig.NewConfig())
+	require.ErrorAs(t, err, &StateLockedError{})
+
+	err = ForceUnlockState()
+	require.NoError(t, err)
+
+	err = SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestSettings(t *testing.T, contents string) {
+	err := os.MkdirAll(utils.JumppadHome(), os.ModePerm)
+	require.NoError(t, err)
+
+	err = os.WriteFile(utils.SettingsPath(), []byte(contents), 0644)
+	require.NoError(t, err)
+}
+
+func TestLoadBackendConfigDefaultsToLocal(t *testing.T) {
+	setupStateLockTests(t)
+
+	bc, err := LoadBackendConfig()
+	require.NoError(t, err)
+	require.Equal(t, BackendTypeLocal, bc.Type)
+
+	b, err := NewStateBackend(bc)
+	require.NoError(t, err)
+	require.Equal(t, utils.StatePath(), b.Location())
+}
+
+func TestLoadBackendConfigReadsSettingsFile(t *testing.T) {
+	setupStateLockTests(t)
+
+	writeTestSettings(t, `
+jumppad {
+  backend "s3" {
+    bucket         = "state"
+    key            = "dev/state.json"
+    endpoint       = "http://localhost:9000"
+    use_path_style = true
+  }
+}
+`)
+
+	bc, err := LoadBackendConfig()
+	require.NoError(t, err)
+	require.Equal(t, BackendTypeS3, bc.Type)
+	require.Equal(t, "state", bc.Bucket)
+	require.Equal(t, "dev/state.json", bc.Key)
+	require.Equal(t, "http://localhost:9000", bc.Endpoint)
+	require.True(t, bc.UsePathStyle)
+}
+
+func TestLoadBackendConfigReturnsErrorForInvalidSettingsFile(t *testing.T) {
+	setupStateLockTests(t)
+
+	writeTestSettings(t, `
+jumppad {
+  backend "http" {
+    unknown = "value"
+  }
+}
+`)
+
+	_, err := LoadBackendConfig()
+	require.Error(t, err)
+}
+
+func TestLoadBackendConfigEnvironmentOverridesSettingsFile(t *testing.T) {
+	setupStateLockTests(t)
+
+	writeTestSettings(t, `
+jumppad {
+  backend "http" {
+    address  = "http://localhost/state"
+    username = "nic"
+  }
+}
+`)
+
+	t.Setenv(BackendEnvVar+"_PASSWORD", "secret")
+	t.Setenv(BackendEnvVar+"_ADDRESS", "http://remote/state")
+
+	bc, err := LoadBackendConfig()
+	require.NoError(t, err)
+	require.Equal(t, BackendTypeHTTP, bc.Type)
+	require.Equal(t, "http://remote/state", bc.Address)
+	require.Equal(t, "nic", bc.Username)
+	require.Equal(t, "secret", bc.Password)
+}
+
+func TestLoadBackendConfigEnvironmentTypeIgnoresSettingsForOtherType(t *testing.T) {
+	setupStateLockTests(t)
+
+	writeTestSettings(t, `
+jumppad {
+  backend "http" {
+    address = "http://localhost/state"
+  }
+}
+`)
+
+	t.Setenv(BackendEnvVar, BackendTypeS3)
+	t.Setenv(BackendEnvVar+"_BUCKET", "state")
+
+	bc, err := LoadBackendConfig()
+	require.NoError(t, err)
+	require.Equal(t, BackendTypeS3, bc.Type)
+	require.Equal(t, "state", bc.Bucket)
+	require.Empty(t, bc.Address)
+}
+
+func TestNewStateBackendReturnsErrorForUnknownType(t *testing.T) {
+	_, err := NewStateBackend(&BackendConfig{Type: "consul"})
+	require.Error(t, err)
+}
+
+func TestNewStateBackendReturnsErrorForMissingAttributes(t *testing.T) {
+	_, err := NewStateBackend(&BackendConfig{Type: BackendTypeHTTP})
+	require.Error(t, err)
+
+	_, err = NewStateBackend(&BackendConfig{Type: BackendTypeS3})
+	require.Error(t, err)
+}
+
+func TestWorkspaceKeyPrefixesNonDefaultWorkspaces(t *testing.T) {
+	setupStateLockTests(t)
+
+	require.Equal(t, "state.json", workspaceKey("state.json"))
+
+	t.Setenv(utils.WorkspaceEnvVar, "nomad")
+	require.Equal(t, "workspaces/nomad/state.json", workspaceKey("state.json"))
+}
+
+func TestStateBackendIsReusedUntilConfigChanges(t *testing.T) {
+	setupStateLockTests(t)
+	t.Setenv(BackendEnvVar, BackendTypeHTTP)
+	t.Setenv(BackendEnvVar+"_ADDRESS", "http://localhost:8080/state")
+
+	b1, err := stateBackend()
+	require.NoError(t, err)
+
+	b2, err := stateBackend()
+	require.NoError(t, err)
+	require.Same(t, b1, b2)
+
+	t.Setenv(BackendEnvVar+"_ADDRESS", "http://localhost:8081/state")
+
+	b3, err := stateBackend()
+	require.NoError(t, err)
+	require.NotSame(t, b1, b3)
+}
@@ -0,0 +1,248 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+)
+
+// RemoveStateResource removes the resource with the given id from the state
+// without destroying it. When the resource is a module all the resources in
+// the module are also removed. References to the removed resources are
+// removed from the links and dependencies of the remaining resources.
+//
+// The removed resources are returned.
+func RemoveStateResource(c *hclconfig.Config, id string) ([]types.Resource, error) {
+	r, err := c.FindResource(id)
+	if err != nil {
+		return nil, fmt.Errorf(`resource "%s" does not exist in the state`, id)
+	}
+
+	removed := []types.Resource{r}
+
+	if r.Metadata().Type == resources.TypeModule {
+		// resources in sub modules are also returned
+		children, _ := c.FindModuleResources(r.Metadata().ID, true)
+		removed = append(removed, children...)
+	}
+
+	ids := map[string]bool{}
+	for _, rr := range removed {
+		ids[rr.Metadata().ID] = true
+
+		err := c.RemoveResource(rr)
+		if err != nil {
+			return nil, fmt.Errorf(`unable to remove resource "%s" from state: %s`, rr.Metadata().ID, err)
+		}
+	}
+
+	for _, rr := range c.Resources {
+		rewriteReferences(rr, func(id string) (string, bool) {
+			return "", !ids[id]
+		})
+	}
+
+	return removed, nil
+}
+
+// MoveStateResource changes the id of a resource in the state, this allows a
+// resource to be renamed or moved into a module without it being destroyed
+// and created. The resource type can not be changed.
+//
+// Any modules that do not exist in the state are added and references to the
+// resource from other resources are updated.
+func MoveStateResource(c *hclconfig.Config, from, to string) (types.Resource, error) {
+	r, err := c.FindResource(from)
+	if err != nil {
+		return nil, fmt.Errorf(`resource "%s" does not exist in the state`, from)
+	}
+
+	if r.Metadata().Type == resources.TypeModule {
+		return nil, fmt.Errorf(`"%s" is a module, only resources can be moved`, from)
+	}
+
+	dest, err := resources.ParseFQRN(to)
+	if err != nil {
+		return nil, fmt.Errorf(`invalid resource id "%s": %s`, to, err)
+	}
+
+	if dest.Attribute != "" {
+		return nil, fmt.Errorf(`invalid resource id "%s", the id must not contain an attribute`, to)
+	}
+
+	if dest.Type != r.Metadata().Type {
+		return nil, fmt.Errorf(`unable to move "%s" to "%s", the resource type can not be changed`, from, to)
+	}
+
+	if _, err := c.FindResource(to); err == nil {
+		return nil, fmt.Errorf(`resource "%s" already exists in the state`, to)
+	}
+
+	oldID := r.Metadata().ID
+	oldModule := r.Metadata().Module
+
+	err = c.RemoveResource(r)
+	if err != nil {
+		return nil, fmt.Errorf(`unable to remove resource "%s" from state: %s`, oldID, err)
+	}
+
+	err = addStateModules(c, dest.Module)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Metadata().Name = dest.Resource
+	r.Metadata().Module = dest.Module
+
+	// the links for the resource are relative to the module, make them
+	// relative to the new module
+	for i, l := range r.Metadata().Links {
+		r.Metadata().Links[i] = relativeReference(l, oldModule, dest.Module)
+	}
+
+	deps := r.GetDependencies()
+	for i, d := range deps {
+		deps[i] = relativeReference(d, oldModule, dest.Module)
+	}
+
+	// AppendResource sets the new id
+	err = c.AppendResource(r)
+	if err != nil {
+		return nil, fmt.Errorf(`unable to add resource "%s" to state: %s`, to, err)
+	}
+
+	for _, rr := range c.Resources {
+		if rr == r {
+			continue
+		}
+
+		rewriteReferences(rr, func(id string) (string, bool) {
+			if id == oldID {
+				return r.Metadata().ID, true
+			}
+
+			return "", true
+		})
+	}
+
+	return r, nil
+}
+
+// addStateModules adds the module resources for the given module path to the
+// state when they do not exist, resources in a module depend on the module
+// resource
+func addStateModules(c *hclconfig.Config, module string) error {
+	if module == "" {
+		return nil
+	}
+
+	parts := strings.Split(module, ".")
+	for i := range parts {
+		m := &resources.Module{}
+		m.Meta.Name = parts[i]
+		m.Meta.Type = resources.TypeModule
+		m.Meta.Module = strings.Join(parts[:i], ".")
+		m.Meta.Properties = map[string]any{constants.PropertyStatus: constants.StatusCreated}
+
+		if _, err := c.FindResource(resources.FQRNFromResource(m).String()); err == nil {
+			continue
+		}
+
+		err := c.AppendResource(m)
+		if err != nil {
+			return fmt.Errorf(`unable to add module "%s" to state: %s`, resources.FQRNFromResource(m).String(), err)
+		}
+	}
+
+	return nil
+}
+
+// rewriteReferences calls f with the absolute id of every resource referenced
+// by the links and dependencies of r. When f returns false the reference is
+// removed, when f returns a new id the reference is changed to the new id
+func rewriteReferences(r types.Resource, f func(id string) (string, bool)) {
+	rewrite := func(refs []string) []string {
+		if refs == nil {
+			return nil
+		}
+
+		out := []string{}
+		for _, ref := range refs {
+			fqrn, err := resources.ParseFQRN(ref)
+			if err != nil {
+				out = append(out, ref)
+				continue
+			}
+
+			abs := fqrn.AppendParentModule(r.Metadata().Module)
+
+			newID, keep := f(abs.StringWithoutAttribute())
+			if !keep {
+				continue
+			}
+
+			if newID == "" || newID == abs.StringWithoutAttribute() {
+				out = append(out, ref)
+				continue
+			}
+
+			nr, ok := moduleRelative(newID, abs.Attribute, r.Metadata().Module)
+			if ok {
+				out = append(out, nr)
+			}
+		}
+
+		return out
+	}
+
+	r.Metadata().Links = rewrite(r.Metadata().Links)
+	r.SetDependencies(rewrite(r.GetDependencies()))
+}
+
+// relativeReference converts a reference written in the module from to a
+// reference from the module to, references that can not be written relative
+// to the new module are returned unchanged
+func relativeReference(ref, from, to string) string {
+	fqrn, err := resources.ParseFQRN(ref)
+	if err != nil {
+		return ref
+	}
+
+	abs := fqrn.AppendParentModule(from)
+
+	nr, ok := moduleRelative(abs.StringWithoutAttribute(), abs.Attribute, to)
+	if !ok {
+		return ref
+	}
+
+	return nr
+}
+
+// moduleRelative returns the reference to the absolute id and attribute as it
+// would be written in the given module, false is returned when the id is not
+// in the module or one of its sub modules
+func moduleRelative(id, attribute, module string) (string, bool) {
+	fqrn, err := resources.ParseFQRN(id)
+	if err != nil {
+		return "", false
+	}
+
+	fqrn.Attribute = attribute
+
+	if module != "" {
+		switch {
+		case fqrn.Module == module:
+			fqrn.Module = ""
+		case strings.HasPrefix(fqrn.Module, module+"."):
+			fqrn.Module = strings.TrimPrefix(fqrn.Module, module+".")
+		default:
+			return "", false
+		}
+	}
+
+	return fqrn.String(), true
+}
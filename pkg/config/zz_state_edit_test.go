@@ -0,0 +1,145 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testStateWithLinks returns a state containing a network, a container that
+// links to the network, and a module containing a template that links to the
+// container
+func testStateWithLinks() *hclconfig.Config {
+	c := hclconfig.NewConfig()
+
+	c.AppendResource(&types.ResourceBase{Meta: types.Meta{Name: "onprem", Type: "network"}})
+
+	c.AppendResource(&types.ResourceBase{
+		Meta: types.Meta{
+			Name:  "db",
+			Type:  "container",
+			Links: []string{"resource.network.onprem.meta.id"},
+		},
+		DependsOn: []string{"resource.network.onprem"},
+	})
+
+	c.AppendResource(&resources.Module{ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "app", Type: resources.TypeModule}}})
+
+	c.AppendResource(&types.ResourceBase{
+		Meta: types.Meta{
+			Name:   "config",
+			Type:   "template",
+			Module: "app",
+			Links:  []string{"resource.template.base.destination"},
+		},
+	})
+
+	c.AppendResource(&types.ResourceBase{
+		Meta: types.Meta{
+			Name:   "base",
+			Type:   "template",
+			Module: "app",
+		},
+	})
+
+	return c
+}
+
+func TestRemoveStateResourceRemovesResourceAndReferences(t *testing.T) {
+	c := testStateWithLinks()
+
+	removed, err := RemoveStateResource(c, "resource.network.onprem")
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+
+	_, err = c.FindResource("resource.network.onprem")
+	require.Error(t, err)
+
+	r, err := c.FindResource("resource.container.db")
+	require.NoError(t, err)
+	require.Empty(t, r.Metadata().Links)
+	require.Empty(t, r.GetDependencies())
+}
+
+func TestRemoveStateResourceRemovesModuleResources(t *testing.T) {
+	c := testStateWithLinks()
+
+	removed, err := RemoveStateResource(c, "module.app")
+	require.NoError(t, err)
+	require.Len(t, removed, 3)
+
+	_, err = c.FindResource("module.app.resource.template.config")
+	require.Error(t, err)
+
+	require.Len(t, c.Resources, 2)
+}
+
+func TestRemoveStateResourceReturnsErrorWhenNotFound(t *testing.T) {
+	c := testStateWithLinks()
+
+	_, err := RemoveStateResource(c, "resource.network.missing")
+	require.Error(t, err)
+}
+
+func TestMoveStateResourceRenamesResourceAndUpdatesReferences(t *testing.T) {
+	c := testStateWithLinks()
+
+	r, err := MoveStateResource(c, "resource.network.onprem", "resource.network.lan")
+	require.NoError(t, err)
+	require.Equal(t, "resource.network.lan", r.Metadata().ID)
+	require.Equal(t, "lan", r.Metadata().Name)
+
+	_, err = c.FindResource("resource.network.onprem")
+	require.Error(t, err)
+
+	db, err := c.FindResource("resource.container.db")
+	require.NoError(t, err)
+	require.Equal(t, []string{"resource.network.lan.meta.id"}, db.Metadata().Links)
+	require.Equal(t, []string{"resource.network.lan"}, db.GetDependencies())
+}
+
+func TestMoveStateResourceIntoModuleAddsModule(t *testing.T) {
+	c := testStateWithLinks()
+
+	r, err := MoveStateResource(c, "resource.container.db", "module.data.resource.container.db")
+	require.NoError(t, err)
+	require.Equal(t, "module.data.resource.container.db", r.Metadata().ID)
+	require.Equal(t, "data", r.Metadata().Module)
+
+	m, err := c.FindResource("module.data")
+	require.NoError(t, err)
+	require.Equal(t, resources.TypeModule, m.Metadata().Type)
+
+	// the network is not in the module, references to it can not be written
+	// relative to the module so they are left unchanged
+	require.Equal(t, []string{"resource.network.onprem.meta.id"}, r.Metadata().Links)
+}
+
+func TestMoveStateResourceOutOfModuleUpdatesRelativeReferences(t *testing.T) {
+	c := testStateWithLinks()
+
+	r, err := MoveStateResource(c, "module.app.resource.template.config", "resource.template.config")
+	require.NoError(t, err)
+	require.Equal(t, []string{"module.app.resource.template.base.destination"}, r.Metadata().Links)
+}
+
+func TestMoveStateResourceReturnsErrorWhenTypeChanges(t *testing.T) {
+	c := testStateWithLinks()
+
+	_, err := MoveStateResource(c, "resource.network.onprem", "resource.container.onprem")
+	require.Error(t, err)
+
+	_, err = c.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+}
+
+func TestMoveStateResourceReturnsErrorWhenDestinationExists(t *testing.T) {
+	c := testStateWithLinks()
+
+	_, err := MoveStateResource(c, "module.app.resource.template.config", "module.app.resource.template.base")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already exists")
+}
@@ -0,0 +1,456 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// encryptedPrefix identifies the values in the state that are encrypted
+const encryptedPrefix = "jumppad:enc:v1:"
+
+// stateKeySize is the size of the AES-256 key used to encrypt the state
+const stateKeySize = 32
+
+// EncryptState enables encryption of the sensitive values in the state, the
+// key is created when it does not exist and the state for the active
+// workspace is re-written with the values encrypted
+func EncryptState() error {
+	unlock, err := LockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = createStateKey()
+	if err != nil {
+		return err
+	}
+
+	c, err := LoadState()
+	if err != nil {
+		// nothing to re-write
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	return SaveState(c)
+}
+
+// StateEncryptionEnabled returns true when the key used to encrypt the
+// sensitive values in the state exists
+func StateEncryptionEnabled() bool {
+	_, err := os.Stat(utils.StateKeyPath())
+	return err == nil
+}
+
+// createStateKey writes a new random key to the state key file, existing
+// keys are never replaced as they are needed to read the state
+func createStateKey() error {
+	if StateEncryptionEnabled() {
+		return nil
+	}
+
+	key := make([]byte, stateKeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return fmt.Errorf("unable to generate state key: %s", err)
+	}
+
+	err = os.MkdirAll(utils.JumppadHome(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for state key: %s", err)
+	}
+
+	err = os.WriteFile(utils.StateKeyPath(), []byte(base64.StdEncoding.EncodeToString(key)), 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write state key '%s': %s", utils.StateKeyPath(), err)
+	}
+
+	return nil
+}
+
+// stateCipher returns the cipher used to encrypt the state, nil is returned
+// when the state key does not exist
+func stateCipher() (cipher.AEAD, error) {
+	d, err := os.ReadFile(utils.StateKeyPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to read state key '%s': %s", utils.StateKeyPath(), err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(d)))
+	if err != nil || len(key) != stateKeySize {
+		return nil, fmt.Errorf("state key '%s' is not valid", utils.StateKeyPath())
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// encryptState encrypts the sensitive values in the serialized state, the
+// state is returned unchanged when encryption is not enabled
+func encryptState(d []byte) ([]byte, error) {
+	aead, err := stateCipher()
+	if err != nil || aead == nil {
+		return d, err
+	}
+
+	doc := map[string]any{}
+	err = unmarshalState(d, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	res, _ := doc["resources"].([]any)
+	for _, r := range res {
+		rm, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		for _, p := range sensitiveStatePaths(rm) {
+			err := replaceSensitivePath(rm, strings.Split(p, "."), func(v any) (any, error) {
+				return encryptValue(aead, v)
+			})
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// use the same format as the unencrypted state
+	return encodeState(doc)
+}
+
+// decryptState decrypts any encrypted values in the serialized state
+func decryptState(d []byte) ([]byte, error) {
+	if !bytes.Contains(d, []byte(encryptedPrefix)) {
+		return d, nil
+	}
+
+	aead, err := stateCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	if aead == nil {
+		return nil, fmt.Errorf("the state contains encrypted values but the state key '%s' does not exist", utils.StateKeyPath())
+	}
+
+	var doc any
+	err = unmarshalState(d, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err = decryptValues(aead, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
+
+// sensitiveStatePaths returns the sensitive paths for a resource in the
+// serialized state
+func sensitiveStatePaths(r map[string]any) []string {
+	meta, _ := r["meta"].(map[string]any)
+	typ, _ := meta["type"].(string)
+
+	paths := []string{}
+	if rt, ok := registeredTypes[typ]; ok {
+		paths = append(paths, taggedTypePaths(reflect.TypeOf(rt), sensitiveTag)...)
+	}
+
+	props, _ := meta["properties"].(map[string]any)
+
+	return append(paths, sensitiveValuePaths(typ, props)...)
+}
+
+// encryptValue encrypts the JSON representation of v so that values which
+// are not strings keep their type when decrypted
+func encryptValue(aead cipher.AEAD, v any) (any, error) {
+	if s, ok := v.(string); ok && strings.HasPrefix(s, encryptedPrefix) {
+		return v, nil
+	}
+
+	d, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize sensitive value: %s", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate nonce: %s", err)
+	}
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, d, nil)), nil
+}
+
+func decryptValues(aead cipher.AEAD, v any) (any, error) {
+	switch vv := v.(type) {
+	case string:
+		if !strings.HasPrefix(vv, encryptedPrefix) {
+			return vv, nil
+		}
+
+		d, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(vv, encryptedPrefix))
+		if err != nil || len(d) < aead.NonceSize() {
+			return nil, fmt.Errorf("invalid encrypted value in state")
+		}
+
+		pt, err := aead.Open(nil, d[:aead.NonceSize()], d[aead.NonceSize():], nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt value in state, the state key may have changed: %s", err)
+		}
+
+		var out any
+		err = unmarshalState(pt, &out)
+		return out, err
+
+	case []any:
+		for i := range vv {
+			nv, err := decryptValues(aead, vv[i])
+			if err != nil {
+				return nil, err
+			}
+
+			vv[i] = nv
+		}
+
+	case map[string]any:
+		for k := range vv {
+			nv, err := decryptValues(aead, vv[k])
+			if err != nil {
+				return nil, err
+			}
+
+			vv[k] = nv
+		}
+	}
+
+	return v, nil
+}
+
+// unmarshalState decodes JSON preserving the precision of numbers
+func unmarshalState(d []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(d))
+	dec.UseNumber()
+
+	err := dec.Decode(v)
+	if err != nil {
+		return fmt.Errorf("unable to decode state: %s", err)
+	}
+
+	return nil
+}
+
+// encryptedStreamHeader identifies data encrypted with EncryptWriter
+const encryptedStreamHeader = "jumppad:enc:stream:v1\n"
+
+// encryptedChunkSize is the size of the plain text in each encrypted chunk
+const encryptedChunkSize = 64 * 1024
+
+// EncryptWriter returns a writer that encrypts the data written to w with
+// the state key, the data is written unchanged when state encryption is not
+// enabled. The data is encrypted in chunks so that large files such as
+// snapshots do not need to be held in memory, Close must be called to write
+// the final chunk.
+func EncryptWriter(w io.Writer) (io.WriteCloser, error) {
+	aead, err := stateCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	if aead == nil {
+		return nopWriteCloser{w}, nil
+	}
+
+	_, err = io.WriteString(w, encryptedStreamHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{w: w, aead: aead}, nil
+}
+
+// DecryptReader returns a reader that decrypts data written with
+// EncryptWriter, data that is not encrypted is returned unchanged
+func DecryptReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	h, err := br.Peek(len(encryptedStreamHeader))
+	if err != nil || string(h) != encryptedStreamHeader {
+		return br, nil
+	}
+
+	aead, err := stateCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	if aead == nil {
+		return nil, fmt.Errorf("the data is encrypted but the state key '%s' does not exist", utils.StateKeyPath())
+	}
+
+	br.Discard(len(encryptedStreamHeader))
+
+	return &decryptReader{r: br, aead: aead}, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// encryptWriter writes each chunk as a flag byte which is set for the last
+// chunk, the length of the sealed chunk and the nonce followed by the sealed
+// chunk. The sequence number and flag are authenticated so that chunks can
+// not be reordered or removed.
+type encryptWriter struct {
+	w    io.Writer
+	aead cipher.AEAD
+	buf  []byte
+	seq  uint64
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	e.buf = append(e.buf, p...)
+
+	for len(e.buf) >= encryptedChunkSize {
+		err := e.writeChunk(e.buf[:encryptedChunkSize], false)
+		if err != nil {
+			return 0, err
+		}
+
+		e.buf = e.buf[encryptedChunkSize:]
+	}
+
+	return len(p), nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.writeChunk(e.buf, true)
+}
+
+func (e *encryptWriter) writeChunk(d []byte, last bool) error {
+	nonce := make([]byte, e.aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return fmt.Errorf("unable to generate nonce: %s", err)
+	}
+
+	flag := chunkFlag(last)
+	sealed := e.aead.Seal(nonce, nonce, d, chunkData(e.seq, flag))
+	e.seq++
+
+	hdr := make([]byte, 5)
+	hdr[0] = flag
+	binary.BigEndian.PutUint32(hdr[1:], uint32(len(sealed)))
+
+	_, err = e.w.Write(append(hdr, sealed...))
+	return err
+}
+
+type decryptReader struct {
+	r    io.Reader
+	aead cipher.AEAD
+	buf  []byte
+	seq  uint64
+	done bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+
+		err := d.readChunk()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+
+	return n, nil
+}
+
+func (d *decryptReader) readChunk() error {
+	hdr := make([]byte, 5)
+	_, err := io.ReadFull(d.r, hdr)
+	if err != nil {
+		return fmt.Errorf("unable to read encrypted data, the data is truncated: %s", err)
+	}
+
+	size := binary.BigEndian.Uint32(hdr[1:])
+	if size < uint32(d.aead.NonceSize()+d.aead.Overhead()) || size > encryptedChunkSize+uint32(d.aead.NonceSize()+d.aead.Overhead()) {
+		return fmt.Errorf("unable to read encrypted data, invalid chunk size %d", size)
+	}
+
+	sealed := make([]byte, size)
+	_, err = io.ReadFull(d.r, sealed)
+	if err != nil {
+		return fmt.Errorf("unable to read encrypted data, the data is truncated: %s", err)
+	}
+
+	ns := d.aead.NonceSize()
+	pt, err := d.aead.Open(nil, sealed[:ns], sealed[ns:], chunkData(d.seq, hdr[0]))
+	if err != nil {
+		return fmt.Errorf("unable to decrypt data, the state key may have changed: %s", err)
+	}
+
+	d.seq++
+	d.buf = pt
+	d.done = hdr[0] == chunkFlag(true)
+
+	return nil
+}
+
+func chunkFlag(last bool) byte {
+	if last {
+		return 1
+	}
+
+	return 0
+}
+
+// chunkData returns the additional data authenticated with each chunk
+func chunkData(seq uint64, flag byte) []byte {
+	ad := make([]byte, 9)
+	binary.BigEndian.PutUint64(ad, seq)
+	ad[8] = flag
+
+	return ad
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStateEncryptionTests(t *testing.T) *hclconfig.Config {
+	setupStateHistoryTests(t)
+
+	c := hclconfig.NewConfig()
+	c.AppendResource(setupSensitiveTests(t))
+
+	return c
+}
+
+func TestSaveStateDoesNotEncryptWithoutKey(t *testing.T) {
+	c := setupStateEncryptionTests(t)
+
+	err := SaveState(c)
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(utils.StatePath())
+	require.NoError(t, err)
+	require.Contains(t, string(d), "secret123")
+	require.NotContains(t, string(d), encryptedPrefix)
+}
+
+func TestEncryptStateCreatesKeyAndEncryptsSensitiveValues(t *testing.T) {
+	c := setupStateEncryptionTests(t)
+
+	err := SaveState(c)
+	require.NoError(t, err)
+
+	err = EncryptState()
+	require.NoError(t, err)
+
+	fi, err := os.Stat(utils.StateKeyPath())
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	d, err := os.ReadFile(utils.StatePath())
+	require.NoError(t, err)
+	require.NotContains(t, string(d), "secret123")
+	require.NotContains(t, string(d), "token123")
+	require.NotContains(t, string(d), "private-key")
+	require.Contains(t, string(d), "/certs/key.pem")
+}
+
+func TestEncryptStateEncryptsSensitiveVariables(t *testing.T) {
+	c := setupStateEncryptionTests(t)
+
+	v := &resources.Variable{
+		ResourceBase: types.ResourceBase{
+			Meta: types.Meta{
+				ID:         "variable.db_password",
+				Name:       "db_password",
+				Type:       resources.TypeVariable,
+				Properties: map[string]any{constants.PropertySensitive: true},
+			},
+		},
+		Default: "variable-secret",
+	}
+	c.AppendResource(v)
+
+	err := EncryptState()
+	require.NoError(t, err)
+
+	err = SaveState(c)
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(utils.StatePath())
+	require.NoError(t, err)
+	require.NotContains(t, string(d), "variable-secret")
+
+	lc, err := LoadState()
+	require.NoError(t, err)
+
+	r, err := lc.FindResource("variable.db_password")
+	require.NoError(t, err)
+	require.Equal(t, "variable-secret", r.(*resources.Variable).Default)
+}
+
+func TestLoadStateDecryptsSensitiveValues(t *testing.T) {
+	c := setupStateEncryptionTests(t)
+
+	err := EncryptState()
+	require.NoError(t, err)
+
+	err = SaveState(c)
+	require.NoError(t, err)
+
+	lc, err := LoadState()
+	require.NoError(t, err)
+
+	r, err := lc.FindResource("resource.test_secret.db")
+	require.NoError(t, err)
+
+	s := r.(*testSecret)
+	require.Equal(t, "secret123", s.Password)
+	require.Equal(t, "token123", *s.Auth[0].Token)
+	require.Equal(t, "private-key", s.Key.Contents)
+}
+
+func TestLoadStateReturnsErrorWhenKeyIsMissing(t *testing.T) {
+	c := setupStateEncryptionTests(t)
+
+	err := EncryptState()
+	require.NoError(t, err)
+
+	err = SaveState(c)
+	require.NoError(t, err)
+
+	err = os.Remove(utils.StateKeyPath())
+	require.NoError(t, err)
+
+	_, err = LoadState()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not exist")
+}
+
+func TestStateSnapshotConfigDecryptsSensitiveValues(t *testing.T) {
+	c := setupStateEncryptionTests(t)
+
+	err := EncryptState()
+	require.NoError(t, err)
+
+	err = SaveState(c)
+	require.NoError(t, err)
+
+	s, err := ListStateHistory()
+	require.NoError(t, err)
+	require.NotContains(t, string(s[0].State), "secret123")
+
+	sc, err := s[0].Config()
+	require.NoError(t, err)
+
+	r, err := sc.FindResource("resource.test_secret.db")
+	require.NoError(t, err)
+	require.Equal(t, "secret123", r.(*testSecret).Password)
+}
+
+func encryptTestData(t *testing.T, d []byte) []byte {
+	buf := bytes.NewBuffer(nil)
+
+	w, err := EncryptWriter(buf)
+	require.NoError(t, err)
+
+	_, err = w.Write(d)
+	require.NoError(t, err)
+
+	err = w.Close()
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+func TestEncryptWriterEncryptsWithStateKey(t *testing.T) {
+	setupStateEncryptionTests(t)
+
+	err := createStateKey()
+	require.NoError(t, err)
+
+	// larger than a single chunk
+	d := bytes.Repeat([]byte("secret123"), encryptedChunkSize/4)
+
+	enc := encryptTestData(t, d)
+	require.NotContains(t, string(enc), "secret123")
+
+	r, err := DecryptReader(bytes.NewReader(enc))
+	require.NoError(t, err)
+
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, d, out)
+}
+
+func TestEncryptWriterDoesNotEncryptWithoutKey(t *testing.T) {
+	setupStateEncryptionTests(t)
+
+	enc := encryptTestData(t, []byte("secret123"))
+	require.Equal(t, "secret123", string(enc))
+
+	r, err := DecryptReader(bytes.NewReader(enc))
+	require.NoError(t, err)
+
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "secret123", string(out))
+}
+
+func TestDecryptReaderReturnsErrorWhenTruncated(t *testing.T) {
+	setupStateEncryptionTests(t)
+
+	err := createStateKey()
+	require.NoError(t, err)
+
+	enc := encryptTestData(t, bytes.Repeat([]byte("a"), encryptedChunkSize*2))
+
+	// remove the last chunk
+	r, err := DecryptReader(bytes.NewReader(enc[:len(encryptedStreamHeader)+2*(5+encryptedChunkSize+28)]))
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(r)
+	require.ErrorContains(t, err, "truncated")
+}
+
+func TestDecryptReaderReturnsErrorWhenKeyIsMissing(t *testing.T) {
+	setupStateEncryptionTests(t)
+
+	err := createStateKey()
+	require.NoError(t, err)
+
+	enc := encryptTestData(t, []byte("secret123"))
+	os.Remove(utils.StateKeyPath())
+
+	_, err = DecryptReader(bytes.NewReader(enc))
+	require.ErrorContains(t, err, "state key")
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// StateHistoryLimit is the number of state snapshots that are retained,
+// when the limit is reached the oldest snapshot is removed
+var StateHistoryLimit = 20
+
+// ErrStateSnapshotNotFound is returned when a snapshot with the given id
+// does not exist in the history
+var ErrStateSnapshotNotFound = fmt.Errorf("state snapshot not found")
+
+// StateSnapshot is a copy of the state taken when the state was saved
+type StateSnapshot struct {
+	// ID is the sequential id of the snapshot, the most recent snapshot
+	// has the highest id
+	ID int `json:"id"`
+	// Created is the time the snapshot was taken
+	Created time.Time `json:"created"`
+	// Command is the command that produced the state
+	Command string `json:"command"`
+	// PID of the process that produced the state
+	PID int `json:"pid"`
+	// State is the serialized state
+	State json.RawMessage `json:"state"`
+}
+
+// Config deserializes the state contained in the snapshot
+func (s *StateSnapshot) Config() (*hclconfig.Config, error) {
+	d, err := decryptState(s.State)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state snapshot %d: %s", s.ID, err)
+	}
+
+	d, err = migrateState(d)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state snapshot %d: %s", s.ID, err)
+	}
+
+	p := NewParser(nil, nil, nil)
+	c, err := p.UnmarshalJSON(d)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal state snapshot %d: %s", s.ID, err)
+	}
+
+	return c, nil
+}
+
+// ListStateHistory returns the snapshots for the active workspace ordered
+// from oldest to newest
+func ListStateHistory() ([]*StateSnapshot, error) {
+	entries, err := os.ReadDir(utils.StateHistoryDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*StateSnapshot{}, nil
+		}
+
+		return nil, fmt.Errorf("unable to read state history: %s", err)
+	}
+
+	snapshots := []*StateSnapshot{}
+	for _, e := range entries {
+		id, ok := snapshotID(e.Name())
+		if !ok {
+			continue
+		}
+
+		s, err := LoadStateSnapshot(id)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, s)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].ID < snapshots[j].ID
+	})
+
+	return snapshots, nil
+}
+
+// LoadStateSnapshot returns the snapshot with the given id
+func LoadStateSnapshot(id int) (*StateSnapshot, error) {
+	d, err := os.ReadFile(snapshotPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrStateSnapshotNotFound
+		}
+
+		return nil, fmt.Errorf("unable to read state snapshot %d: %s", id, err)
+	}
+
+	s := &StateSnapshot{}
+	err = json.Unmarshal(d, s)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state snapshot %d: %s", id, err)
+	}
+
+	return s, nil
+}
+
+// saveStateSnapshot adds the state to the history, a command can save the
+// state many times, only the last state saved by a process is retained.
+// The caller must hold the state lock
+func saveStateSnapshot(d []byte) error {
+	snapshots, err := ListStateHistory()
+	if err != nil {
+		return err
+	}
+
+	s := &StateSnapshot{
+		ID:      1,
+		Created: time.Now(),
+		Command: currentCommand(),
+		PID:     os.Getpid(),
+		State:   json.RawMessage(d),
+	}
+
+	if len(snapshots) > 0 {
+		last := snapshots[len(snapshots)-1]
+		s.ID = last.ID + 1
+
+		// replace the previous snapshot when saved by this process
+		if last.PID == s.PID && last.Command == s.Command {
+			s.ID = last.ID
+			snapshots = snapshots[:len(snapshots)-1]
+		}
+	}
+
+	err = os.MkdirAll(utils.StateHistoryDir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for state history '%s', error: %s", utils.StateHistoryDir(), err)
+	}
+
+	sd, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to serialize state snapshot: %s", err)
+	}
+
+	err = writeFileAtomic(snapshotPath(s.ID), sd)
+	if err != nil {
+		return fmt.Errorf("unable to write state snapshot '%s', error: %s", snapshotPath(s.ID), err)
+	}
+
+	// remove the oldest snapshots over the limit, the new snapshot
+	// counts towards the limit
+	for i := 0; i < len(snapshots)+1-StateHistoryLimit; i++ {
+		os.Remove(snapshotPath(snapshots[i].ID))
+	}
+
+	return nil
+}
+
+func snapshotPath(id int) string {
+	return filepath.Join(utils.StateHistoryDir(), fmt.Sprintf("%06d.json", id))
+}
+
+func snapshotID(name string) (int, bool) {
+	if !strings.HasSuffix(name, ".json") {
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(strings.TrimSuffix(name, ".json"))
+	if err != nil {
+		return 0, false
+	}
+
+	return id, true
+}
+
+// currentCommand returns the command line of the current process
+func currentCommand() string {
+	args := append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...)
+	return strings.Join(args, " ")
+}
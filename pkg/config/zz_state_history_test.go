@@ -0,0 +1,139 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStateHistoryTests(t *testing.T) {
+	setupStateLockTests(t)
+
+	limit := StateHistoryLimit
+	t.Cleanup(func() {
+		StateHistoryLimit = limit
+	})
+}
+
+// writeTestSnapshot adds a snapshot to the history as if it was created
+// by a different process
+func writeTestSnapshot(t *testing.T, id int, c *hclconfig.Config) {
+	d, err := c.ToJSON()
+	require.NoError(t, err)
+
+	s := StateSnapshot{ID: id, Command: "jumppad up", PID: 99999999, State: d}
+	sd, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	err = os.MkdirAll(utils.StateHistoryDir(), os.ModePerm)
+	require.NoError(t, err)
+
+	err = os.WriteFile(snapshotPath(id), sd, 0644)
+	require.NoError(t, err)
+}
+
+func testConfigWithResources(names ...string) *hclconfig.Config {
+	c := hclconfig.NewConfig()
+	for _, n := range names {
+		c.AppendResource(&types.ResourceBase{Meta: types.Meta{ID: "resource.container." + n, Name: n, Type: "container"}})
+	}
+
+	return c
+}
+
+func TestSaveStateAddsSnapshotToHistory(t *testing.T) {
+	setupStateHistoryTests(t)
+
+	err := SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+
+	s, err := ListStateHistory()
+	require.NoError(t, err)
+	require.Len(t, s, 1)
+
+	require.Equal(t, 1, s[0].ID)
+	require.Equal(t, os.Getpid(), s[0].PID)
+	require.Equal(t, currentCommand(), s[0].Command)
+	require.False(t, s[0].Created.IsZero())
+
+	_, err = s[0].Config()
+	require.NoError(t, err)
+}
+
+func TestSaveStateReplacesSnapshotFromSameProcess(t *testing.T) {
+	setupStateHistoryTests(t)
+
+	err := SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+
+	err = SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+
+	s, err := ListStateHistory()
+	require.NoError(t, err)
+	require.Len(t, s, 1)
+}
+
+func TestSaveStateAppendsSnapshotFromDifferentProcess(t *testing.T) {
+	setupStateHistoryTests(t)
+
+	writeTestSnapshot(t, 1, hclconfig.NewConfig())
+
+	err := SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+
+	s, err := ListStateHistory()
+	require.NoError(t, err)
+	require.Len(t, s, 2)
+	require.Equal(t, 2, s[1].ID)
+}
+
+func TestSaveStateRotatesHistory(t *testing.T) {
+	setupStateHistoryTests(t)
+	StateHistoryLimit = 3
+
+	for i := 1; i <= 3; i++ {
+		writeTestSnapshot(t, i, hclconfig.NewConfig())
+	}
+
+	err := SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+
+	s, err := ListStateHistory()
+	require.NoError(t, err)
+	require.Len(t, s, 3)
+	require.Equal(t, 2, s[0].ID)
+	require.Equal(t, 4, s[2].ID)
+}
+
+func TestRemoveStateAddsEmptySnapshot(t *testing.T) {
+	setupStateHistoryTests(t)
+
+	writeTestSnapshot(t, 1, testConfigWithResources("one"))
+
+	err := SaveState(testConfigWithResources("one"))
+	require.NoError(t, err)
+
+	err = RemoveState()
+	require.NoError(t, err)
+
+	s, err := ListStateHistory()
+	require.NoError(t, err)
+	require.Len(t, s, 2)
+
+	c, err := s[1].Config()
+	require.NoError(t, err)
+	require.Len(t, c.Resources, 0)
+}
+
+func TestLoadStateSnapshotReturnsErrorWhenNotFound(t *testing.T) {
+	setupStateHistoryTests(t)
+
+	_, err := LoadStateSnapshot(1)
+	require.ErrorIs(t, err, ErrStateSnapshotNotFound)
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	ps "github.com/mitchellh/go-ps"
+)
+
+// StateLockTimeout is the maximum time to wait for a lock held by another
+// process to be released before returning a StateLockedError
+var StateLockTimeout = 10 * time.Second
+
+// stateLockRetryInterval is the interval between attempts to acquire the lock
+var stateLockRetryInterval = 250 * time.Millisecond
+
+// StateLock contains the details of the process holding the state lock
+type StateLock struct {
+	PID      int       `json:"pid"`
+	Hostname string    `json:"hostname"`
+	Command  string    `json:"command"`
+	Created  time.Time `json:"created"`
+}
+
+// StateLockedError is returned when the state is locked by another process
+type StateLockedError struct {
+	Lock *StateLock
+}
+
+func (e StateLockedError) Error() string {
+	return fmt.Sprintf(
+		"state is locked by process %d on %s running '%s' since %s, if this process is no longer running remove the lock with 'jumppad state unlock --force'",
+		e.Lock.PID,
+		e.Lock.Hostname,
+		e.Lock.Command,
+		e.Lock.Created.Format(time.RFC3339),
+	)
+}
+
+// IsStale returns true when the process that created the lock is no longer
+// running. Locks created on a different host can not be checked and are
+// never considered stale.
+func (l *StateLock) IsStale() bool {
+	if l.Hostname != utils.GetHostname() {
+		return false
+	}
+
+	p, err := ps.FindProcess(l.PID)
+	if err != nil {
+		return false
+	}
+
+	return p == nil
+}
+
+// the lock is re-entrant within a process, the engine holds the lock for
+// the duration of an apply and SaveState acquires it again
+var lockMutex sync.Mutex
+var lockCount int
+var lockLocation string
+var lockBackend StateBackend
+
+// LockState acquires the advisory lock for the state of the active workspace.
+// If the lock is held by another process LockState waits for StateLockTimeout
+// before returning a StateLockedError. Stale locks left by processes which are
+// no longer running are removed automatically.
+//
+// The returned function releases the lock and must always be called.
+func LockState() (func(), error) {
+	b, err := stateBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	lockMutex.Lock()
+	defer lockMutex.Unlock()
+
+	// we already hold the lock
+	if lockCount > 0 && lockLocation == b.Location() {
+		lockCount++
+		return newStateUnlock(), nil
+	}
+
+	deadline := time.Now().Add(StateLockTimeout)
+	for {
+		err := b.Lock(StateLock{
+			PID:      os.Getpid(),
+			Hostname: utils.GetHostname(),
+			Command:  currentCommand(),
+			Created:  time.Now(),
+		})
+
+		if err == nil {
+			lockCount = 1
+			lockLocation = b.Location()
+			lockBackend = b
+
+			return newStateUnlock(), nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("unable to create state lock for '%s', error: %s", b.Location(), err)
+		}
+
+		l, err := b.ReadLock()
+		if err != nil {
+			// the lock could have been released between create and read
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if l.IsStale() {
+			b.Unlock()
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, StateLockedError{Lock: l}
+		}
+
+		time.Sleep(stateLockRetryInterval)
+	}
+}
+
+// ReadStateLock returns the details of the current state lock, if the
+// state is not locked an error satisfying os.IsNotExist is returned
+func ReadStateLock() (*StateLock, error) {
+	b, err := stateBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.ReadLock()
+}
+
+// ForceUnlockState removes the state lock regardless of the process
+// that holds it
+func ForceUnlockState() error {
+	b, err := stateBackend()
+	if err != nil {
+		return err
+	}
+
+	err = b.Unlock()
+	if err != nil {
+		return fmt.Errorf("unable to remove state lock for '%s', error: %s", b.Location(), err)
+	}
+
+	return nil
+}
+
+func marshalStateLock(l StateLock) ([]byte, error) {
+	d, err := json.Marshal(l)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize state lock, error: %s", err)
+	}
+
+	return d, nil
+}
+
+func unmarshalStateLock(d []byte, location string) (*StateLock, error) {
+	l := &StateLock{}
+	err := json.Unmarshal(d, l)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state lock '%s', error: %s", location, err)
+	}
+
+	return l, nil
+}
+
+// newStateUnlock returns a function that releases the lock once, calling
+// the function multiple times has no further effect
+func newStateUnlock() func() {
+	once := sync.Once{}
+
+	return func() {
+		once.Do(releaseStateLock)
+	}
+}
+
+func releaseStateLock() {
+	lockMutex.Lock()
+	defer lockMutex.Unlock()
+
+	if lockCount == 0 {
+		return
+	}
+
+	lockCount--
+	if lockCount == 0 {
+		lockBackend.Unlock()
+		lockBackend = nil
+		lockLocation = ""
+	}
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStateLockTests(t *testing.T) {
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+	t.Setenv(utils.WorkspaceEnvVar, "")
+	t.Setenv(BackendEnvVar, "")
+
+	timeout := StateLockTimeout
+	StateLockTimeout = 100 * time.Millisecond
+
+	t.Cleanup(func() {
+		StateLockTimeout = timeout
+	})
+}
+
+func writeTestLock(t *testing.T, l StateLock) {
+	err := os.MkdirAll(utils.StateDir(), os.ModePerm)
+	require.NoError(t, err)
+
+	d, err := json.Marshal(l)
+	require.NoError(t, err)
+
+	err = os.WriteFile(utils.StateLockPath(), d, 0644)
+	require.NoError(t, err)
+}
+
+func TestLockStateCreatesLockFile(t *testing.T) {
+	setupStateLockTests(t)
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), l.PID)
+	require.Equal(t, utils.GetHostname(), l.Hostname)
+
+	unlock()
+
+	require.NoFileExists(t, utils.StateLockPath())
+}
+
+func TestLockStateIsReentrant(t *testing.T) {
+	setupStateLockTests(t)
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+
+	unlock2, err := LockState()
+	require.NoError(t, err)
+
+	// releasing the inner lock keeps the outer lock
+	unlock2()
+	unlock2()
+	require.FileExists(t, utils.StateLockPath())
+
+	unlock()
+	require.NoFileExists(t, utils.StateLockPath())
+}
+
+func TestLockStateReturnsErrorWhenLockedByRunningProcess(t *testing.T) {
+	setupStateLockTests(t)
+
+	// the parent process is running, so the lock is not stale
+	writeTestLock(t, StateLock{PID: os.Getppid(), Hostname: utils.GetHostname(), Command: "jumppad up"})
+
+	_, err := LockState()
+	require.ErrorAs(t, err, &StateLockedError{})
+}
+
+func TestLockStateReturnsErrorWhenLockedByOtherHost(t *testing.T) {
+	setupStateLockTests(t)
+
+	writeTestLock(t, StateLock{PID: 1, Hostname: "some-other-host", Command: "jumppad up"})
+
+	_, err := LockState()
+	require.ErrorAs(t, err, &StateLockedError{})
+}
+
+func TestLockStateRemovesStaleLock(t *testing.T) {
+	setupStateLockTests(t)
+
+	// pids are limited to 2^22 on linux so this process will never exist
+	writeTestLock(t, StateLock{PID: 99999999, Hostname: utils.GetHostname(), Command: "jumppad up"})
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+	defer unlock()
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), l.PID)
+}
+
+func TestForceUnlockStateRemovesLock(t *testing.T) {
+	setupStateLockTests(t)
+
+	writeTestLock(t, StateLock{PID: 1, Hostname: "some-other-host", Command: "jumppad up"})
+
+	err := ForceUnlockState()
+	require.NoError(t, err)
+	require.NoFileExists(t, utils.StateLockPath())
+}
+
+func TestSaveStateWritesStateAndReleasesLock(t *testing.T) {
+	setupStateLockTests(t)
+
+	err := SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+
+	require.FileExists(t, utils.StatePath())
+	require.NoFileExists(t, utils.StateLockPath())
+
+	// no temporary files should remain
+	files, err := filepath.Glob(filepath.Join(utils.StateDir(), "*.tmp"))
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestSaveStateReturnsErrorWhenLocked(t *testing.T) {
+	setupStateLockTests(t)
+
+	writeTestLock(t, StateLock{PID: 1, Hostname: "some-other-host", Command: "jumppad up"})
+
+	err := SaveState(hclconfig.NewConfig())
+	require.ErrorAs(t, err, &StateLockedError{})
+	require.NoFileExists(t, utils.StatePath())
+}
+
+func TestLoadStateDoesNotWaitForLock(t *testing.T) {
+	setupStateLockTests(t)
+
+	err := SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+
+	writeTestLock(t, StateLock{PID: 1, Hostname: "some-other-host", Command: "jumppad up"})
+
+	_, err = LoadState()
+	require.NoError(t, err)
+
+	// the lock held by the other process is unchanged
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, "some-other-host", l.Hostname)
+}
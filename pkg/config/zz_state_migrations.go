@@ -0,0 +1,190 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/jumppad-labs/hclconfig"
+)
+
+// StateVersion is the version of the state document written by this version
+// of jumppad. Any change to a resource that changes how it is serialized must
+// increment StateVersion and add a migration that upgrades the previous
+// version to stateMigrations.
+const StateVersion = 2
+
+// legacyStateVersion is the version of state documents written before the
+// version header was added
+const legacyStateVersion = 1
+
+// stateMigration upgrades a state document from version From to From+1, the
+// document is modified in place
+type stateMigration struct {
+	From        int
+	Description string
+	Migrate     func(doc map[string]any) error
+}
+
+// stateMigrations are applied in order to upgrade older state documents,
+// there must be a migration for every version from legacyStateVersion to
+// StateVersion-1
+var stateMigrations = []stateMigration{
+	{
+		From:        1,
+		Description: "use the json names for the output and apply_output attributes of terraform resources",
+		Migrate:     migrateTerraformOutputNames,
+	},
+}
+
+// stateDocument is used to add the version header to the serialized config
+// without changing the order of the fields in the resources
+type stateDocument struct {
+	Version   int             `json:"version"`
+	Resources json.RawMessage `json:"resources"`
+}
+
+// serializeState returns the config as a state document with the version
+// header set to StateVersion
+func serializeState(c *hclconfig.Config) ([]byte, error) {
+	d, err := c.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize config to JSON: %s", err)
+	}
+
+	doc := stateDocument{}
+	err = json.Unmarshal(d, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize config to JSON: %s", err)
+	}
+
+	doc.Version = StateVersion
+
+	return encodeState(doc)
+}
+
+// migrateState upgrades the serialized state to StateVersion, the state is
+// returned unchanged when it is already the current version. An error is
+// returned when the state was written by a newer version of jumppad.
+func migrateState(d []byte) ([]byte, error) {
+	doc := map[string]any{}
+	err := unmarshalState(d, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := stateDocumentVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == StateVersion {
+		return d, nil
+	}
+
+	if v > StateVersion {
+		return nil, fmt.Errorf("state version %d is newer than the version %d supported by this version of jumppad, please upgrade jumppad", v, StateVersion)
+	}
+
+	for _, m := range stateMigrations {
+		if m.From < v {
+			continue
+		}
+
+		if m.From != v {
+			return nil, fmt.Errorf("unable to migrate state from version %d, no migration exists", v)
+		}
+
+		err := m.Migrate(doc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to migrate state from version %d to %d, %s: %s", m.From, m.From+1, m.Description, err)
+		}
+
+		v = m.From + 1
+		doc["version"] = v
+	}
+
+	if v != StateVersion {
+		return nil, fmt.Errorf("unable to migrate state from version %d, no migration exists", v)
+	}
+
+	return encodeState(doc)
+}
+
+// stateDocumentVersion returns the version header from the state document,
+// documents without a header are legacyStateVersion
+func stateDocumentVersion(doc map[string]any) (int, error) {
+	raw, ok := doc["version"]
+	if !ok {
+		return legacyStateVersion, nil
+	}
+
+	n, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("invalid state version '%v'", raw)
+	}
+
+	v, err := strconv.Atoi(n.String())
+	if err != nil || v < legacyStateVersion {
+		return 0, fmt.Errorf("invalid state version '%v'", raw)
+	}
+
+	return v, nil
+}
+
+// encodeState uses the same format as hclconfig.Config.ToJSON
+func encodeState(v any) ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{})
+	enc := json.NewEncoder(buf)
+	enc.SetIndent("", " ")
+
+	err := enc.Encode(v)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode state: %s", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// stateResources returns the resources in the state document that have the
+// given type
+func stateResources(doc map[string]any, typ string) []map[string]any {
+	out := []map[string]any{}
+
+	res, _ := doc["resources"].([]any)
+	for _, r := range res {
+		rm, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		meta, _ := rm["meta"].(map[string]any)
+		if t, _ := meta["type"].(string); t == typ {
+			out = append(out, rm)
+		}
+	}
+
+	return out
+}
+
+// migrateTerraformOutputNames renames the Output and ApplyOutput fields of
+// terraform resources which were serialized with the go field names
+func migrateTerraformOutputNames(doc map[string]any) error {
+	for _, r := range stateResources(doc, "terraform") {
+		renameStateField(r, "Output", "output")
+		renameStateField(r, "ApplyOutput", "apply_output")
+	}
+
+	return nil
+}
+
+func renameStateField(r map[string]any, from, to string) {
+	v, ok := r[from]
+	if !ok {
+		return
+	}
+
+	delete(r, from)
+	r[to] = v
+}
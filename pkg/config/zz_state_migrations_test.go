@@ -0,0 +1,141 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files for the state migration tests")
+
+// TestMigrateStateGolden migrates the fixture for each state version in
+// testdata/state/v<version>.json and compares the result with the golden
+// file v<version>.golden.json, run with -update to regenerate the golden files
+func TestMigrateStateGolden(t *testing.T) {
+	for v := legacyStateVersion; v <= StateVersion; v++ {
+		t.Run(fmt.Sprintf("v%d", v), func(t *testing.T) {
+			d, err := os.ReadFile(filepath.Join("testdata", "state", fmt.Sprintf("v%d.json", v)))
+			require.NoError(t, err)
+
+			out, err := migrateState(d)
+			require.NoError(t, err)
+
+			golden := filepath.Join("testdata", "state", fmt.Sprintf("v%d.golden.json", v))
+			if *updateGolden {
+				err := os.WriteFile(golden, out, 0644)
+				require.NoError(t, err)
+			}
+
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(out))
+
+			doc := map[string]any{}
+			err = unmarshalState(out, &doc)
+			require.NoError(t, err)
+
+			version, err := stateDocumentVersion(doc)
+			require.NoError(t, err)
+			require.Equal(t, StateVersion, version)
+		})
+	}
+}
+
+func TestStateMigrationsExistForEveryVersion(t *testing.T) {
+	for v := legacyStateVersion; v < StateVersion; v++ {
+		found := false
+		for _, m := range stateMigrations {
+			if m.From == v {
+				found = true
+			}
+		}
+
+		require.True(t, found, "no migration from state version %d", v)
+	}
+}
+
+func TestMigrateStateRenamesTerraformOutputs(t *testing.T) {
+	d, err := os.ReadFile(filepath.Join("testdata", "state", "v1.json"))
+	require.NoError(t, err)
+
+	out, err := migrateState(d)
+	require.NoError(t, err)
+
+	doc := map[string]any{}
+	err = unmarshalState(out, &doc)
+	require.NoError(t, err)
+
+	tf := stateResources(doc, "terraform")
+	require.Len(t, tf, 1)
+	require.Equal(t, "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.", tf[0]["apply_output"])
+	require.NotContains(t, tf[0], "ApplyOutput")
+	require.NotContains(t, tf[0], "Output")
+}
+
+func TestMigrateStateReturnsErrorForNewerVersion(t *testing.T) {
+	_, err := migrateState([]byte(fmt.Sprintf(`{"version": %d, "resources": []}`, StateVersion+1)))
+	require.ErrorContains(t, err, "please upgrade jumppad")
+}
+
+func TestMigrateStateReturnsErrorForInvalidVersion(t *testing.T) {
+	_, err := migrateState([]byte(`{"version": "two", "resources": []}`))
+	require.Error(t, err)
+}
+
+func TestMigrateStateReturnsErrorWhenMigrationMissing(t *testing.T) {
+	migrations := stateMigrations
+	stateMigrations = []stateMigration{}
+
+	t.Cleanup(func() {
+		stateMigrations = migrations
+	})
+
+	_, err := migrateState([]byte(`{"resources": []}`))
+	require.ErrorContains(t, err, "no migration exists")
+}
+
+func TestSaveStateWritesVersion(t *testing.T) {
+	setupStateLockTests(t)
+
+	c := hclconfig.NewConfig()
+	c.AppendResource(&resources.Output{ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "main", Type: resources.TypeOutput}}})
+
+	err := SaveState(c)
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(utils.StatePath())
+	require.NoError(t, err)
+
+	doc := map[string]any{}
+	err = unmarshalState(d, &doc)
+	require.NoError(t, err)
+
+	version, err := stateDocumentVersion(doc)
+	require.NoError(t, err)
+	require.Equal(t, StateVersion, version)
+}
+
+func TestLoadStateMigratesLegacyState(t *testing.T) {
+	setupStateLockTests(t)
+
+	err := os.MkdirAll(utils.StateDir(), os.ModePerm)
+	require.NoError(t, err)
+
+	err = os.WriteFile(utils.StatePath(), []byte(`{"resources": [{"meta": {"id": "output.main", "name": "main", "type": "output"}, "value": "abc"}]}`), 0644)
+	require.NoError(t, err)
+
+	c, err := LoadState()
+	require.NoError(t, err)
+
+	r, err := c.FindResource("output.main")
+	require.NoError(t, err)
+	require.Equal(t, "abc", r.(*resources.Output).Value)
+}
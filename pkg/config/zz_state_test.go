@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStateReadsMarshaledState(t *testing.T) {
+	c := hclconfig.NewConfig()
+	c.AppendResource(&resources.Output{ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "main", Type: resources.TypeOutput}}, Value: "abc"})
+
+	d, err := MarshalState(c)
+	require.NoError(t, err)
+
+	pc, err := ParseState(d)
+	require.NoError(t, err)
+
+	r, err := pc.FindResource("output.main")
+	require.NoError(t, err)
+	require.Equal(t, "abc", r.(*resources.Output).Value)
+}
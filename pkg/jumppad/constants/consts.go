@@ -3,6 +3,22 @@ package constants
 // PropertyStatus is the key for the Metadata property that contains the status
 const PropertyStatus = "status"
 
+// PropertyLifecycle is the key for the Metadata property that contains the
+// lifecycle settings for the resource
+const PropertyLifecycle = "lifecycle"
+
+// PropertyRetry is the key for the Metadata property that contains the
+// retry settings for the resource
+const PropertyRetry = "retry"
+
+// PropertyTimeouts is the key for the Metadata property that contains the
+// timeouts for the resource
+const PropertyTimeouts = "timeouts"
+
+// PropertySensitive is the key for the Metadata property that is set when the
+// value of an output contains sensitive data
+const PropertySensitive = "sensitive"
+
 const (
 	// StatusCreated is set once the resource has been successfully created
 	StatusCreated = "created"
@@ -14,7 +30,15 @@ const (
 	// StatusFailed indicates that the resource failed to create
 	StatusFailed = "failed"
 
+	// StatusCancelled indicates that the apply was cancelled while the
+	// resource was being created, the resource may be partially created
+	StatusCancelled = "cancelled"
+
 	// StatusDisabled indicates that the resources has been disabled and no
 	// resources have been created
 	StatusDisabled = "disabled"
+
+	// StatusStopped indicates that the resource has been stopped, the objects
+	// still exist and are started rather than recreated by the next apply
+	StatusStopped = "stopped"
 )
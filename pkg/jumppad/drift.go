@@ -0,0 +1,107 @@
+package jumppad
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+)
+
+// ResourceDrift describes a resource where the running objects no longer
+// match the state
+type ResourceDrift struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Reasons contains a description of each difference
+	Reasons []string `json:"reasons"`
+	// Tainted is true when the resource has been marked to be recreated
+	Tainted bool `json:"tainted"`
+}
+
+// DriftReport contains the result of checking the resources in the state
+// against the running objects
+type DriftReport struct {
+	// Drifted contains the resources that no longer match the state
+	Drifted []ResourceDrift `json:"drifted"`
+
+	// Unchecked contains the IDs of the created resources that could not be
+	// checked as the provider does not implement config.DriftDetector
+	Unchecked []string `json:"unchecked"`
+}
+
+// DetectDrift checks the created resources in the state against the running
+// objects and returns the resources that have drifted. Only resources with a
+// provider that implements config.DriftDetector are checked, the other
+// resources are returned as unchecked.
+//
+// When fix is true the drifted resources are marked as tainted so that they
+// are recreated on the next apply
+func (e *EngineImpl) DetectDrift(fix bool) (*DriftReport, error) {
+	// hold the lock so the state does not change while it is checked
+	unlock, err := config.LockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	c, err := config.LoadState()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load state: %s", err)
+	}
+
+	report := &DriftReport{Drifted: []ResourceDrift{}, Unchecked: []string{}}
+
+	for _, r := range c.Resources {
+		if r.GetDisabled() || r.Metadata().Properties[constants.PropertyStatus] != constants.StatusCreated {
+			continue
+		}
+
+		p := e.providers.GetProvider(r)
+
+		dd, ok := p.(config.DriftDetector)
+		if !ok {
+			report.Unchecked = append(report.Unchecked, r.Metadata().ID)
+			continue
+		}
+
+		e.log.Debug("Checking resource for drift", "ref", r.Metadata().ID)
+
+		reasons, err := dd.Drift()
+		if err != nil {
+			return nil, fmt.Errorf(`unable to check resource "%s" for drift: %s`, r.Metadata().ID, err)
+		}
+
+		if len(reasons) == 0 {
+			continue
+		}
+
+		rd := ResourceDrift{
+			ID:      r.Metadata().ID,
+			Type:    r.Metadata().Type,
+			Reasons: reasons,
+		}
+
+		if fix {
+			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusTainted
+			rd.Tainted = true
+		}
+
+		report.Drifted = append(report.Drifted, rd)
+	}
+
+	sort.Slice(report.Drifted, func(i, j int) bool {
+		return report.Drifted[i].ID < report.Drifted[j].ID
+	})
+
+	sort.Strings(report.Unchecked)
+
+	if fix && len(report.Drifted) > 0 {
+		err = config.SaveState(c)
+		if err != nil {
+			return nil, fmt.Errorf("unable to save state: %s", err)
+		}
+	}
+
+	return report, nil
+}
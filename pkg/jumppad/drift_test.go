@@ -0,0 +1,99 @@
+package jumppad
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/testutils"
+	sdk "github.com/jumppad-labs/plugin-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+type driftProvider struct {
+	mocks.Provider
+	drift []string
+}
+
+func (d *driftProvider) Drift() ([]string, error) {
+	return d.drift, nil
+}
+
+// driftProviders returns providers that report drift for the named resources
+type driftProviders struct {
+	drift map[string][]string
+}
+
+func (d *driftProviders) GetProvider(r types.Resource) sdk.Provider {
+	// networks do not implement drift detection in these tests
+	if r.Metadata().Type == "network" {
+		return &mocks.Provider{}
+	}
+
+	return &driftProvider{drift: d.drift[r.Metadata().Name]}
+}
+
+func setupDriftTests(t *testing.T, drift map[string][]string) *EngineImpl {
+	testutils.SetupState(t, existingState)
+
+	return &EngineImpl{
+		log:       logger.NewTestLogger(t),
+		providers: &driftProviders{drift: drift},
+	}
+}
+
+func TestDetectDriftReturnsDriftedResources(t *testing.T) {
+	e := setupDriftTests(t, map[string][]string{"container": {"container is exited"}})
+
+	report, err := e.DetectDrift(false)
+	require.NoError(t, err)
+
+	d := report.Drifted
+	require.Len(t, d, 1)
+	require.Equal(t, "resource.container.container", d[0].ID)
+	require.Equal(t, []string{"container is exited"}, d[0].Reasons)
+	require.False(t, d[0].Tainted)
+
+	// the state must not be modified
+	r, err := testLoadState(t).FindResource("resource.container.container")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestDetectDriftWithFixTaintsResources(t *testing.T) {
+	e := setupDriftTests(t, map[string][]string{"container": {"container is exited"}})
+
+	report, err := e.DetectDrift(true)
+	require.NoError(t, err)
+
+	d := report.Drifted
+	require.Len(t, d, 1)
+	require.True(t, d[0].Tainted)
+
+	r, err := testLoadState(t).FindResource("resource.container.container")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusTainted, r.Metadata().Properties[constants.PropertyStatus])
+
+	r, err = testLoadState(t).FindResource("resource.template.consul_config")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestDetectDriftReturnsNothingWhenNoDrift(t *testing.T) {
+	e := setupDriftTests(t, nil)
+
+	report, err := e.DetectDrift(true)
+	require.NoError(t, err)
+	require.Empty(t, report.Drifted)
+}
+
+func TestDetectDriftReturnsUncheckedResources(t *testing.T) {
+	e := setupDriftTests(t, nil)
+
+	report, err := e.DetectDrift(false)
+	require.NoError(t, err)
+	require.Contains(t, report.Unchecked, "resource.network.cloud")
+	require.NotContains(t, report.Unchecked, "resource.container.container")
+}
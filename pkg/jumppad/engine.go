@@ -5,17 +5,21 @@ import (
 	// "fmt"
 
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/jumppad-labs/hclconfig"
 	hclerrors "github.com/jumppad-labs/hclconfig/errors"
 	"github.com/jumppad-labs/hclconfig/resources"
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/telemetry"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/cache"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/network"
@@ -35,11 +39,59 @@ type Engine interface {
 	// configuration. Optionally the user can provide a map of variables which the configuration
 	// uses and / or a file containing variables.
 	ApplyWithVariables(ctx context.Context, path string, variables map[string]string, variablesFile string) (*hclconfig.Config, error)
+
+	// ApplyTargets applies only the targeted resources and the resources they
+	// depend on, all other resources in the state are left untouched.
+	ApplyTargets(ctx context.Context, path string, variables map[string]string, variablesFile string, targets []string) (*hclconfig.Config, error)
+
+	// ApplyState applies a previously saved state, resources are created,
+	// recreated or destroyed so that the running resources match the state.
+	ApplyState(ctx context.Context, state *hclconfig.Config) (*hclconfig.Config, error)
 	ParseConfig(string) (*hclconfig.Config, error)
 	ParseConfigWithVariables(string, map[string]string, string) (*hclconfig.Config, error)
 	Destroy(ctx context.Context, force bool) error
+
+	// DestroyTargets destroys only the targeted resources and the resources that
+	// depend on them, all other resources in the state are left untouched.
+	DestroyTargets(ctx context.Context, targets []string, force bool) error
 	Config() *hclconfig.Config
+
+	// Events returns the bus that the engine publishes resource events to
+	Events() *events.Bus
 	Diff(path string, variables map[string]string, variablesFile string) (new []types.Resource, changed []types.Resource, removed []types.Resource, cfg *hclconfig.Config, err error)
+
+	// Plan returns the actions that will be taken for each resource when the
+	// configuration is applied, including the attributes that have changed
+	Plan(path string, variables map[string]string, variablesFile string) (*Plan, error)
+
+	// DetectDrift returns the resources in the state where the running objects
+	// no longer match and the resources that could not be checked, when fix is
+	// true the drifted resources are marked as tainted
+	DetectDrift(fix bool) (*DriftReport, error)
+
+	// Import adds an existing Docker object to the state as the resource with
+	// the given id, the object must match the resource configuration
+	Import(path string, variables map[string]string, variablesFile string, id string, ref string) (types.Resource, error)
+
+	// Stop stops the resources in the state without destroying them, the
+	// stopped resources are started again by Start or Apply
+	Stop(ctx context.Context) error
+
+	// Start starts the resources in the state that have been stopped
+	Start(ctx context.Context) error
+
+	// SetParallelism limits the number of resources that are processed at
+	// the same time
+	SetParallelism(p Parallelism) error
+
+	// SetGracePeriod sets the time providers are given to finish or roll
+	// back the operation in progress when the context is cancelled
+	SetGracePeriod(d time.Duration)
+
+	// SetKeepGoing sets whether the engine continues to create resources
+	// that do not depend on a failed resource, by default no new resources
+	// are started after a resource fails
+	SetKeepGoing(k bool)
 }
 
 // EngineImpl is responsible for creating and destroying resources
@@ -50,6 +102,26 @@ type EngineImpl struct {
 	ctx        context.Context
 	force      bool
 	cacheMutex sync.Mutex
+
+	// events receives the progress of the engine
+	events *events.Bus
+
+	// targets contains the ids of the resources the engine operates on,
+	// when nil the engine operates on all resources
+	targets map[string]bool
+
+	// limiter restricts the number of concurrent provider operations, when
+	// nil there is no limit
+	limiter *limiter
+
+	// providerCtx is passed to the providers, it is cancelled once the
+	// grace period has elapsed after ctx is cancelled
+	providerCtx context.Context
+	gracePeriod time.Duration
+
+	// keepGoing continues to create independent resources after a failure
+	keepGoing bool
+	failures  *failureRecorder
 }
 
 // New creates a new Jumppad engine
@@ -58,6 +130,8 @@ func New(p config.Providers, l logger.Logger) (Engine, error) {
 	e.log = l
 	e.providers = p
 	e.cacheMutex = sync.Mutex{}
+	e.events = events.NewBus()
+	e.gracePeriod = DefaultGracePeriod
 
 	// Set the standard writer to our logger as the DAG uses the standard library log.
 	log.SetOutput(l.StandardWriter())
@@ -70,6 +144,36 @@ func (e *EngineImpl) Config() *hclconfig.Config {
 	return e.config
 }
 
+// Events returns the bus that the engine publishes resource events to
+func (e *EngineImpl) Events() *events.Bus {
+	return e.events
+}
+
+// SetParallelism limits the number of resources that are processed at the
+// same time
+func (e *EngineImpl) SetParallelism(p Parallelism) error {
+	err := p.validate()
+	if err != nil {
+		return err
+	}
+
+	e.limiter = newLimiter(p)
+
+	return nil
+}
+
+// SetGracePeriod sets the time providers are given to finish or roll back
+// the operation in progress when the context is cancelled
+func (e *EngineImpl) SetGracePeriod(d time.Duration) {
+	e.gracePeriod = d
+}
+
+// SetKeepGoing sets whether the engine continues to create resources that do
+// not depend on a failed resource
+func (e *EngineImpl) SetKeepGoing(k bool) {
+	e.keepGoing = k
+}
+
 // ParseConfig parses the given Jumppad files and creating the resource types but does
 // not apply or destroy the resources.
 // This function can be used to check the validity of a configuration without making changes
@@ -81,8 +185,12 @@ func (e *EngineImpl) ParseConfig(path string) (*hclconfig.Config, error) {
 // not apply or destroy the resources.
 // This function can be used to check the validity of a configuration without making changes
 func (e *EngineImpl) ParseConfigWithVariables(path string, vars map[string]string, variablesFile string) (*hclconfig.Config, error) {
-	// abs paths
+	_, span := telemetry.Start(e.ctx, "parse", telemetry.AttributePath.String(path))
+
 	var err error
+	defer func() { telemetry.End(span, err) }()
+
+	// abs paths
 	path, err = filepath.Abs(path)
 	if err != nil {
 		return nil, err
@@ -111,12 +219,25 @@ func (e *EngineImpl) ParseConfigWithVariables(path string, vars map[string]strin
 func (e *EngineImpl) Diff(path string, variables map[string]string, variablesFile string) (
 	[]types.Resource, []types.Resource, []types.Resource, *hclconfig.Config, error) {
 
+	_, span := telemetry.Start(e.ctx, "diff", telemetry.AttributePath.String(path))
+
+	new, changed, removed, res, err := e.diff(path, variables, variablesFile)
+	telemetry.End(span, err)
+
+	return new, changed, removed, res, err
+}
+
+func (e *EngineImpl) diff(path string, variables map[string]string, variablesFile string) (
+	[]types.Resource, []types.Resource, []types.Resource, *hclconfig.Config, error) {
+
 	var new []types.Resource
 	var changed []types.Resource
 	var removed []types.Resource
 
-	// load the stack
-	past, _ := config.LoadState()
+	past, err := loadState()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	// Parse the config to check it is valid
 	res, parseErr := e.ParseConfigWithVariables(path, variables, variablesFile)
@@ -182,8 +303,11 @@ func (e *EngineImpl) Diff(path string, variables map[string]string, variablesFil
 	// loop through the remaining resources and call changed on the provider
 	// to see if any internal properties that have changed
 	for _, r := range unchanged {
-		// call changed on when not disabled
-		if !r.GetDisabled() {
+		// call changed on when not disabled, unless the lifecycle ignores
+		// all changes to the resource. Ignoring individual attributes only
+		// affects the checksum of the configuration, the provider does not
+		// know which attributes caused the change
+		if !r.GetDisabled() && !getLifecycle(r).IgnoresAll() {
 			p := e.providers.GetProvider(r)
 			if p == nil {
 				return nil, nil, nil, nil, fmt.Errorf("unable to create provider for resource Name: %s, Type: %s. Please check the provider is registered in providers.go", r.Metadata().Name, r.Metadata().Type)
@@ -210,10 +334,25 @@ func (e *EngineImpl) Apply(ctx context.Context, path string) (*hclconfig.Config,
 
 // ApplyWithVariables applies the current config creating the resources
 func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars map[string]string, variablesFile string) (*hclconfig.Config, error) {
-	e.ctx = ctx
+	return e.applyWithTargets(ctx, path, vars, variablesFile, nil)
+}
+
+// ApplyTargets applies the current config creating only the targeted resources
+// and their dependencies
+func (e *EngineImpl) ApplyTargets(ctx context.Context, path string, vars map[string]string, variablesFile string, targets []string) (*hclconfig.Config, error) {
+	return e.applyWithTargets(ctx, path, vars, variablesFile, targets)
+}
+
+func (e *EngineImpl) applyWithTargets(ctx context.Context, path string, vars map[string]string, variablesFile string, targets []string) (_ *hclconfig.Config, err error) {
+	ctx, span := telemetry.Start(ctx, "apply", telemetry.AttributePath.String(path))
+	defer func() { telemetry.End(span, err) }()
+
+	defer e.setContext(ctx)()
+	e.targets = nil
+	defer func() { e.targets = nil }()
+	e.failures = &failureRecorder{}
 
 	// abs paths
-	var err error
 	path, err = filepath.Abs(path)
 	if err != nil {
 		return nil, err
@@ -228,20 +367,49 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 		}
 	}
 
+	// hold the state lock for the duration of the apply to stop other
+	// processes modifying the state
+	unlock, err := config.LockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	// get a diff of resources
-	_, _, removed, _, err := e.Diff(path, vars, variablesFile)
+	new, changed, removed, parsed, err := e.Diff(path, vars, variablesFile)
 	if err != nil {
 		return nil, err
 	}
 
 	// load the state
-	c, err := config.LoadState()
+	c, err := loadState()
 	if err != nil {
-		e.log.Debug("unable to load state", "error", err)
+		return nil, err
 	}
 
 	e.config = c
 
+	if len(targets) > 0 {
+		e.targets, err = e.resolveApplyTargets(parsed, removed, targets)
+		if err != nil {
+			return nil, err
+		}
+
+		e.log.Info("Applying targeted resources", "targets", targets, "resources", len(e.targets))
+	}
+
+	// check that no resources with prevent_destroy would be destroyed
+	// before making any changes
+	plan, err := buildPlan(new, changed, removed, parsed, c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkPreventDestroy(plan, e.targets)
+	if err != nil {
+		return nil, err
+	}
+
 	// check to see we already have an image cache
 	// only create the cache if it is not disabled
 	if !utils.ImageCacheDisabled() {
@@ -268,7 +436,7 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 			}
 
 			// create the cache
-			err := p.Create(ctx)
+			err := e.runProvider(ca, opCreate, p.Create)
 			if err != nil {
 				ca.Meta.Properties[constants.PropertyStatus] = constants.StatusFailed
 			} else {
@@ -287,6 +455,16 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 				return nil, fmt.Errorf("unable to create image cache %s", err)
 			}
 		}
+
+		// the image cache is not part of the config, when the environment
+		// has been stopped it is started before the resources that use it
+		ic, err := c.FindResource("resource.image_cache.default")
+		if err == nil && ic.Metadata().Properties[constants.PropertyStatus] == constants.StatusStopped {
+			err := e.startResource(ic, e.providers.GetProvider(ic))
+			if err != nil {
+				return nil, fmt.Errorf("unable to start image cache %s", err)
+			}
+		}
 	} else {
 		e.log.Info("Image cache disabled, skipping creation")
 	}
@@ -294,10 +472,38 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 	// finally we can process and create resources
 	processErr := e.readAndProcessConfig(path, vars, variablesFile, e.createCallback)
 
+	// record the arguments of a cancelled apply so that it can be resumed,
+	// resources that are no longer in the config are left in the state until
+	// the apply completes
+	cancelled := ctx.Err() != nil
+	if cancelled {
+		processErr = ErrCancelled
+
+		err := saveResumeInfo(ResumeInfo{Path: path, Variables: vars, VariablesFile: variablesFile, Targets: targets})
+		if err != nil {
+			e.log.Error("Unable to save resume file", "error", err)
+		}
+	} else {
+		err := clearResumeInfo()
+		if err != nil {
+			e.log.Debug("Unable to remove resume file", "error", err)
+		}
+	}
+
 	// we need to remove any resources that are in the state but not in the config
 	for _, r := range removed {
+		if cancelled || !e.isTargeted(r) {
+			continue
+		}
+
 		e.log.Debug("removing resource in state but not current config", "id", r.Metadata().ID)
 
+		err := checkResourcesPreventDestroy([]types.Resource{r})
+		if err != nil {
+			processErr = err
+			continue
+		}
+
 		p := e.providers.GetProvider(r)
 		if p == nil {
 			processErr = fmt.Errorf("unable to create provider for resource Name: %s, Type: %s. Please check the provider is registered in providers.go", r.Metadata().Name, r.Metadata().Type)
@@ -305,7 +511,7 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 		}
 
 		// call destroy
-		err := p.Destroy(e.ctx, e.force)
+		err = e.destroyResource(r, p, e.force)
 		if err != nil {
 			processErr = fmt.Errorf("unable to destroy resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
 			continue
@@ -320,23 +526,179 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 		e.log.Info("Unable to save state", "error", stateErr)
 	}
 
+	if !cancelled {
+		processErr = e.failures.applyError(parsed, processErr)
+	}
+
+	return e.config, processErr
+}
+
+// ApplyState applies a previously saved state such as a snapshot from the
+// state history. Resources that are not in the given state are destroyed,
+// resources that have changed are recreated and new resources are created.
+func (e *EngineImpl) ApplyState(ctx context.Context, state *hclconfig.Config) (_ *hclconfig.Config, err error) {
+	e.log.Info("Applying resources from state")
+
+	ctx, span := telemetry.Start(ctx, "apply_state")
+	defer func() { telemetry.End(span, err) }()
+
+	defer e.setContext(ctx)()
+	e.failures = &failureRecorder{}
+
+	// hold the state lock for the duration of the apply
+	unlock, err := config.LockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// load the current state
+	c, err := loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	e.config = c
+
+	diff, err := c.Diff(state)
+	if err != nil {
+		return nil, err
+	}
+
+	// destroy the resources that are not in the new state in reverse order
+	removed := map[string]bool{}
+	for _, r := range diff.Removed {
+		removed[r.Metadata().ID] = true
+	}
+
+	if len(removed) > 0 {
+		err = c.Walk(func(r types.Resource) error {
+			if !removed[r.Metadata().ID] {
+				return nil
+			}
+
+			return e.destroyCallback(r)
+		}, true)
+
+		if err != nil {
+			config.SaveState(e.config)
+			return e.config, fmt.Errorf("error trying to call Destroy on provider: %s", err)
+		}
+	}
+
+	// mark changed resources as tainted so that they are recreated
+	for _, r := range append(diff.ParseUpdated, diff.ProcessedUpdated...) {
+		sr, err := e.config.FindResource(r.Metadata().ID)
+		if err == nil {
+			sr.Metadata().Properties[constants.PropertyStatus] = constants.StatusTainted
+		}
+	}
+
+	// the status of the resources comes from the current state, new resources
+	// must not have a status
+	for _, r := range diff.Added {
+		if r.Metadata().Properties == nil {
+			r.Metadata().Properties = map[string]interface{}{}
+		}
+
+		delete(r.Metadata().Properties, constants.PropertyStatus)
+	}
+
+	processErr := state.Walk(e.createCallback, false)
+
+	// process is not called for disabled resources, add manually
+	err = e.appendDisabledResources(state)
+	if err != nil && processErr == nil {
+		processErr = err
+	}
+
+	err = e.destroyDisabledResources(ctx, false)
+	if err != nil && processErr == nil {
+		processErr = err
+	}
+
+	// save the state regardless of error
+	stateErr := config.SaveState(e.config)
+	if stateErr != nil {
+		e.log.Info("Unable to save state", "error", stateErr)
+	}
+
 	return e.config, processErr
 }
 
 // Destroy the resources defined by the state
 func (e *EngineImpl) Destroy(ctx context.Context, force bool) error {
+	return e.destroyWithTargets(ctx, nil, force)
+}
+
+// DestroyTargets destroys the targeted resources and the resources that
+// depend on them
+func (e *EngineImpl) DestroyTargets(ctx context.Context, targets []string, force bool) error {
+	return e.destroyWithTargets(ctx, targets, force)
+}
+
+func (e *EngineImpl) destroyWithTargets(ctx context.Context, targets []string, force bool) (err error) {
 	e.log.Info("Destroying resources", "force", force)
 	e.force = force
-	e.ctx = ctx
+
+	ctx, span := telemetry.Start(ctx, "destroy")
+	defer func() { telemetry.End(span, err) }()
+
+	defer e.setContext(ctx)()
+	e.targets = nil
+	defer func() { e.targets = nil }()
+
+	// hold the state lock for the duration of the destroy
+	unlock, err := config.LockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// load the state
-	c, err := config.LoadState()
+	c, err := loadState()
 	if err != nil {
-		e.log.Debug("State file does not exist")
+		return err
 	}
 
 	e.config = c
 
+	if len(targets) > 0 {
+		e.targets, err = resolveTargets(c, targets, true)
+		if err != nil {
+			return err
+		}
+
+		// the image cache is attached to every network, unless explicitly
+		// targeted it is detached from targeted networks rather than destroyed
+		targetsCache := false
+		for _, t := range targets {
+			if t == "resource.image_cache.default" {
+				targetsCache = true
+			}
+		}
+
+		if !targetsCache {
+			delete(e.targets, "resource.image_cache.default")
+		}
+
+		e.log.Info("Destroying targeted resources", "targets", targets, "resources", len(e.targets))
+	}
+
+	// check that none of the resources have prevent_destroy set before
+	// destroying anything
+	destroy := []types.Resource{}
+	for _, r := range c.Resources {
+		if e.isTargeted(r) && !r.GetDisabled() {
+			destroy = append(destroy, r)
+		}
+	}
+
+	err = checkResourcesPreventDestroy(destroy)
+	if err != nil {
+		return err
+	}
+
 	// run through the graph and call the destroy callback
 	// disabled resources are not included in this callback
 	// image cache which is manually added by Apply process
@@ -345,12 +707,100 @@ func (e *EngineImpl) Destroy(ctx context.Context, force bool) error {
 	err = e.config.Walk(e.destroyCallback, true)
 	if err != nil {
 
+		// save the remaining resources when only destroying targets
+		if e.targets != nil {
+			config.SaveState(e.config)
+		}
+
 		// return the process error
 		return fmt.Errorf("error trying to call Destroy on provider: %s", err)
 	}
 
+	// when only the targets have been destroyed keep the rest of the state
+	if e.targets != nil {
+		return config.SaveState(e.config)
+	}
+
 	// remove the state
-	return os.Remove(utils.StatePath())
+	return config.RemoveState()
+}
+
+// resolveApplyTargets returns the targeted resources and their dependencies
+// from the parsed config. Targets that have been removed from the config but
+// exist in the state are included so that they can be destroyed.
+// Variables are always included as they are required to process the config
+func (e *EngineImpl) resolveApplyTargets(parsed *hclconfig.Config, removed []types.Resource, targets []string) (map[string]bool, error) {
+	configTargets := []string{}
+	removedTargets := map[string]bool{}
+
+	for _, t := range targets {
+		isRemoved := false
+		for _, r := range removed {
+			if r.Metadata().ID == t {
+				isRemoved = true
+				removedTargets[t] = true
+			}
+		}
+
+		if !isRemoved {
+			configTargets = append(configTargets, t)
+		}
+	}
+
+	selected, err := resolveTargets(parsed, configTargets, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for t := range removedTargets {
+		selected[t] = true
+	}
+
+	for _, r := range parsed.Resources {
+		if r.Metadata().Type == resources.TypeVariable {
+			selected[r.Metadata().ID] = true
+		}
+	}
+
+	return selected, nil
+}
+
+// isTargeted returns true when the engine should operate on the resource
+func (e *EngineImpl) isTargeted(r types.Resource) bool {
+	return e.targets == nil || e.targets[r.Metadata().ID]
+}
+
+// detachImageCache removes the network from the image cache so that a
+// targeted network can be destroyed without destroying the cache
+func (e *EngineImpl) detachImageCache(network types.Resource) {
+	e.cacheMutex.Lock()
+	defer e.cacheMutex.Unlock()
+
+	ic, err := e.config.FindResource("resource.image_cache.default")
+	if err != nil {
+		return
+	}
+
+	deps := []string{}
+	for _, d := range ic.GetDependencies() {
+		if d != network.Metadata().ID {
+			deps = append(deps, d)
+		}
+	}
+
+	ic.SetDependencies(deps)
+
+	p := e.providers.GetProvider(ic)
+	if p == nil {
+		return
+	}
+
+	e.log.Debug("Detaching image cache from network", "network", network.Metadata().ID)
+
+	err = p.Refresh(e.ctx)
+	if err != nil {
+		e.log.Error("Unable to detach Image Cache from network", "error", err)
+	}
 }
 
 // ResourceCount defines the number of resources in a plan
@@ -381,7 +831,7 @@ func (e *EngineImpl) readAndProcessConfig(path string, variables map[string]stri
 		variablesFiles = append(variablesFiles, variablesFile)
 	}
 
-	hclParser := config.NewParser(callback, variables, variablesFiles)
+	hclParser := config.NewParser(withMetaArguments(callback), variables, variablesFiles)
 
 	if utils.IsHCLFile(path) {
 		// ParseFile processes the HCL, builds a graph of resources then calls
@@ -403,6 +853,8 @@ func (e *EngineImpl) readAndProcessConfig(path string, variables map[string]stri
 		parsedConfig, parseError = hclParser.ParseDirectory(path)
 	}
 
+	markSensitiveVariables(parsedConfig, variables)
+
 	// process is not called for disabled resources, add manually
 	err := e.appendDisabledResources(parsedConfig)
 	if err != nil {
@@ -426,9 +878,18 @@ func (e *EngineImpl) destroyDisabledResources(ctx context.Context, force bool) e
 	// these respurces should be destroyed
 
 	for _, r := range e.config.Resources {
+		if !e.isTargeted(r) {
+			continue
+		}
+
 		if r.GetDisabled() &&
 			r.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
 
+			err := checkResourcesPreventDestroy([]types.Resource{r})
+			if err != nil {
+				return err
+			}
+
 			p := e.providers.GetProvider(r)
 			if p == nil {
 				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
@@ -436,7 +897,7 @@ func (e *EngineImpl) destroyDisabledResources(ctx context.Context, force bool) e
 			}
 
 			// call destroy
-			err := p.Destroy(ctx, force)
+			err = e.destroyResource(r, p, force)
 			if err != nil {
 				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 				return fmt.Errorf("unable to destroy resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
@@ -481,10 +942,25 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 		return nil
 	}
 
+	// resources that are not targeted are left untouched in the state
+	if !e.isTargeted(r) {
+		return nil
+	}
+
+	// once a resource has failed no new resources are started unless the
+	// engine keeps going
+	if !e.keepGoing && e.failures.hasFailures() {
+		e.failures.skipped(r)
+		return nil
+	}
+
 	p := e.providers.GetProvider(r)
 	if p == nil {
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
-		return fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
+		err := fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
+		e.failures.failed(r, err)
+
+		return err
 	}
 
 	// we need to check if a resource exists in the state, if so the status
@@ -501,37 +977,87 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 		}
 	}
 
+	// ensure sensitive values set in the config are never logged
+	logger.AddSensitiveValues(config.SensitiveValues(r)...)
+
 	var providerError error
 	switch r.Metadata().Properties[constants.PropertyStatus] {
+	case constants.StatusStopped:
+		// stopped resources are started rather than recreated and are then
+		// refreshed in the same way as a created resource
+		providerError = e.startResource(r, p)
+		if providerError == nil {
+			providerError = e.runProvider(r, opRefresh, p.Refresh)
+		}
+
+		switch {
+		case e.interrupted(providerError):
+			providerError = nil
+		case providerError != nil:
+			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+		}
+
 	case constants.StatusCreated:
-		providerError = p.Refresh(e.ctx)
-		if providerError != nil {
+		providerError = e.runProvider(r, opRefresh, p.Refresh)
+
+		switch {
+		case e.interrupted(providerError):
+			// the resource has been created, an interrupted refresh leaves it
+			// as it was
+			providerError = nil
+		case providerError != nil:
 			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 		}
 
 	// Normal case for PendingUpdate is do nothing
 	// PendingModification causes a resource to be
 	// destroyed before created
-	case constants.StatusTainted:
-		fallthrough
-
 	// Always attempt to destroy and re-create failed resources
-	case constants.StatusFailed:
-		providerError = p.Destroy(e.ctx, false)
-		if providerError != nil {
-			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
-		}
+	// and resources that were interrupted by a cancelled apply
+	case constants.StatusTainted, constants.StatusFailed, constants.StatusCancelled:
+		lc := getLifecycle(r)
+
+		switch {
+		case lc.PreventDestroy:
+			// leave the existing resource and the status untouched
+			providerError = checkResourcesPreventDestroy([]types.Resource{r})
+
+		default:
+			providerError = e.destroyResource(r, p, false)
+			if providerError != nil {
+				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+			}
 
-		fallthrough // failed resources should always attempt recreation
+			// failed resources should always attempt recreation
+			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+			providerError = e.createResource(r, p)
+			if providerError != nil {
+				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+			}
+		}
 
 	default:
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
-		providerError = p.Create(e.ctx)
+		providerError = e.createResource(r, p)
 		if providerError != nil {
 			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 		}
 	}
 
+	// a resource that was being created when the apply was cancelled may be
+	// partially created, it is recreated by the next apply
+	if e.interrupted(providerError) && r.Metadata().Properties[constants.PropertyStatus] == constants.StatusFailed {
+		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCancelled
+		providerError = nil
+	}
+
+	if providerError != nil {
+		e.failures.failed(r, providerError)
+	}
+
+	// values such as generated passwords are only known once created
+	logger.AddSensitiveValues(config.SensitiveValues(r)...)
+
 	// add the resource to the state
 	err = e.config.AppendResource(r)
 	if err != nil {
@@ -552,7 +1078,7 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 
 				// reload the networks
 				np := e.providers.GetProvider(ic)
-				np.Refresh(e.ctx)
+				np.Refresh(e.providerContext())
 			} else {
 				e.log.Error("Unable to find Image Cache", "error", err)
 			}
@@ -590,12 +1116,12 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 
 				e.cacheMutex.Unlock()
 
-				err := np.Destroy(e.ctx, e.force)
+				err := np.Destroy(e.providerContext(), e.force)
 				if err != nil {
 					e.log.Error("Unable to destroy Image Cache", "error", err)
 				}
 
-				err = np.Create(e.ctx)
+				err = np.Create(e.providerContext())
 				if err != nil {
 					e.log.Error("Unable to create Image Cache", "error", err)
 				}
@@ -616,6 +1142,11 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 
 	fqrn := resources.FQRNFromResource(r)
 
+	// resources that are not targeted are left untouched in the state
+	if !e.isTargeted(r) {
+		return nil
+	}
+
 	// do nothing for disabled resources
 	if r.GetDisabled() {
 		e.log.Info("Skipping disabled resource", "fqdn", fqrn.String())
@@ -624,6 +1155,11 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 		return nil
 	}
 
+	err := checkResourcesPreventDestroy([]types.Resource{r})
+	if err != nil {
+		return err
+	}
+
 	p := e.providers.GetProvider(r)
 
 	if p == nil {
@@ -631,7 +1167,13 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 		return fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
 	}
 
-	err := p.Destroy(e.ctx, e.force)
+	// when destroying a targeted network the image cache must be detached
+	// as the cache is not destroyed
+	if e.targets != nil && r.Metadata().Type == network.TypeNetwork && !e.targets["resource.image_cache.default"] {
+		e.detachImageCache(r)
+	}
+
+	err = e.destroyResource(r, p, e.force)
 	if err != nil && !e.force {
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 		return fmt.Errorf("unable to destroy resource Name: %s, Type: %s, Error: %s", r.Metadata().Name, r.Metadata().Type, err)
@@ -642,3 +1184,109 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 
 	return nil
 }
+
+// providerOperation contains the events published for a provider method
+type providerOperation struct {
+	name     string
+	started  events.Type
+	finished events.Type
+	failed   events.Type
+}
+
+var opCreate = providerOperation{"create", events.ResourceCreateStarted, events.ResourceCreateFinished, events.ResourceCreateFailed}
+var opRefresh = providerOperation{"refresh", events.ResourceRefreshStarted, events.ResourceRefreshFinished, events.ResourceRefreshFailed}
+var opDestroy = providerOperation{"destroy", events.ResourceDestroyStarted, events.ResourceDestroyFinished, events.ResourceDestroyFailed}
+var opStop = providerOperation{"stop", events.ResourceStopStarted, events.ResourceStopFinished, events.ResourceStopFailed}
+var opStart = providerOperation{"start", events.ResourceStartStarted, events.ResourceStartFinished, events.ResourceStartFailed}
+
+// runProvider calls the given provider method publishing events before and
+// after the call. The context passed to the provider allows the provider to
+// publish events for the resource such as health check progress, and
+// contains the span for the operation so providers can add child spans
+func (e *EngineImpl) runProvider(r types.Resource, op providerOperation, f func(ctx context.Context) error) error {
+	id := r.Metadata().ID
+	rt := r.Metadata().Type
+
+	// wait for a free slot before the operation is started so that the time
+	// spent waiting is not included in the duration
+	release := e.limiter.acquire(e.ctx, rt)
+	defer release()
+
+	// resources waiting for a slot when the context is cancelled are not
+	// started
+	if e.ctx.Err() != nil {
+		return e.ctx.Err()
+	}
+
+	e.events.Publish(events.Event{Type: op.started, ResourceID: id, ResourceType: rt})
+
+	ctx, span := telemetry.Start(
+		e.providerContext(),
+		op.name+" "+id,
+		telemetry.AttributeResourceID.String(id),
+		telemetry.AttributeResourceType.String(rt),
+		telemetry.AttributeOperation.String(op.name),
+	)
+
+	st := time.Now()
+	err := f(events.WithResource(ctx, e.events, id, rt))
+	telemetry.End(span, err)
+
+	ev := events.Event{Type: op.finished, ResourceID: id, ResourceType: rt, DurationMS: time.Since(st).Milliseconds()}
+	if err != nil {
+		ev.Type = op.failed
+		ev.Error = err.Error()
+	}
+
+	e.events.Publish(ev)
+
+	return err
+}
+
+// setContext sets the context for an operation, the returned function
+// releases the provider context and must be called when the operation
+// completes
+func (e *EngineImpl) setContext(ctx context.Context) func() {
+	e.ctx = ctx
+
+	pctx, cancel := gracefulContext(ctx, e.gracePeriod)
+	e.providerCtx = pctx
+
+	return func() {
+		cancel()
+		e.providerCtx = nil
+	}
+}
+
+// providerContext returns the context passed to providers, operations in
+// progress when the context is cancelled have the grace period to finish
+func (e *EngineImpl) providerContext() context.Context {
+	if e.providerCtx != nil {
+		return e.providerCtx
+	}
+
+	return e.ctx
+}
+
+// interrupted returns true when err is the result of the context being
+// cancelled
+func (e *EngineImpl) interrupted(err error) bool {
+	return err != nil && e.ctx.Err() != nil
+}
+
+// loadState returns the state for the active workspace, an empty config is
+// returned when nothing has been applied. Any other error is returned as
+// continuing with an empty config would recreate the existing resources and
+// replace the state.
+func loadState() (*hclconfig.Config, error) {
+	c, err := config.LoadState()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return hclconfig.NewConfig(), nil
+		}
+
+		return nil, fmt.Errorf("unable to load state: %w", err)
+	}
+
+	return c, nil
+}
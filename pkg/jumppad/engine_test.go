@@ -6,11 +6,14 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/jumppad-labs/hclconfig"
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/telemetry"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/cache"
@@ -21,6 +24,10 @@ import (
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func setupTests(t *testing.T, returnVals map[string]error) (*EngineImpl, *mocks.Providers) {
@@ -194,6 +201,28 @@ func TestApplyDoesNotCallsProviderCreateWhenInState(t *testing.T) {
 	require.Equal(t, 7, sf.ResourceCount())
 }
 
+func TestApplyWithUnreadableStateReturnsErrorAndKeepsState(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, "not json")
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file")
+	require.ErrorContains(t, err, "unable to load state")
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+
+	d, err := os.ReadFile(utils.StatePath())
+	require.NoError(t, err)
+	require.Equal(t, "not json", string(d))
+}
+
+func TestDestroyWithUnreadableStateReturnsError(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, "not json")
+
+	err := e.Destroy(context.Background(), false)
+	require.ErrorContains(t, err, "unable to load state")
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
 func TestApplyRemovesItemsInStateWhenNotInFiles(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, existingState)
 
@@ -285,6 +314,7 @@ func TestApplySetsCreatedStatusForEachResource(t *testing.T) {
 
 func TestApplyCallsProviderGenerateErrorStopsExecution(t *testing.T) {
 	e, mp := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
+	e.keepGoing = true
 
 	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
 	require.Error(t, err)
@@ -312,6 +342,65 @@ func TestApplyCallsProviderGenerateErrorStopsExecution(t *testing.T) {
 	require.Error(t, err)
 }
 
+func setupTracing(t *testing.T) *tracetest.SpanRecorder {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+	})
+
+	return sr
+}
+
+func findSpan(t *testing.T, sr *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	for _, s := range sr.Ended() {
+		if s.Name() == name {
+			return s
+		}
+	}
+
+	t.Fatalf("span %s not found", name)
+	return nil
+}
+
+func TestApplyCreatesSpansForEachResource(t *testing.T) {
+	sr := setupTracing(t)
+	e, _ := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	apply := findSpan(t, sr, "apply")
+	require.False(t, apply.Parent().IsValid())
+
+	for _, name := range []string{"parse", "diff", "create resource.network.onprem", "create resource.container.consul"} {
+		s := findSpan(t, sr, name)
+		require.Equal(t, apply.SpanContext().SpanID(), s.Parent().SpanID(), "span %s should be a child of apply", name)
+	}
+
+	s := findSpan(t, sr, "create resource.network.onprem")
+	require.Contains(t, s.Attributes(), telemetry.AttributeResourceType.String("network"))
+	require.Contains(t, s.Attributes(), telemetry.AttributeOperation.String("create"))
+}
+
+func TestApplyRecordsProviderErrorsOnSpans(t *testing.T) {
+	sr := setupTracing(t)
+	e, _ := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.Error(t, err)
+
+	s := findSpan(t, sr, "create resource.network.onprem")
+	require.Equal(t, codes.Error, s.Status().Code)
+	require.Equal(t, "boom", s.Status().Description)
+
+	require.Equal(t, codes.Error, findSpan(t, sr, "apply").Status().Code)
+}
+
 func TestApplyCallsProviderDestroyAndCreateForFailedResources(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, failedState)
 
@@ -369,6 +458,7 @@ func TestApplyCallsProviderRefreshForCreatedResources(t *testing.T) {
 
 func TestApplyCallsProviderRefreshWithErrorHaltsExecution(t *testing.T) {
 	e, mp := setupTestsWithState(t, map[string]error{"consul_config": fmt.Errorf("boom")}, singleFileState)
+	e.keepGoing = true
 
 	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
 	require.Error(t, err)
@@ -377,6 +467,164 @@ func TestApplyCallsProviderRefreshWithErrorHaltsExecution(t *testing.T) {
 	testAssertMethodCalled(t, mp, "Create", 2)
 }
 
+func TestApplyStateCreatesDestroysAndRecreatesResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+
+	// snapshot contains the network from the current state, a changed
+	// container and a new template, the existing template is removed
+	p := config.NewParser(nil, nil, nil)
+	snapshot, err := p.UnmarshalJSON([]byte(existingState))
+	require.NoError(t, err)
+
+	r, err := snapshot.FindResource("resource.container.container")
+	require.NoError(t, err)
+	r.Metadata().Checksum.Parsed = "changed"
+
+	r, err = snapshot.FindResource("resource.template.consul_config")
+	require.NoError(t, err)
+	snapshot.RemoveResource(r)
+
+	snapshot.AppendResource(&types.ResourceBase{
+		Meta: types.Meta{
+			ID:         "resource.template.new_config",
+			Name:       "new_config",
+			Type:       "template",
+			Properties: map[string]interface{}{constants.PropertyStatus: constants.StatusCreated},
+		},
+	})
+
+	_, err = e.ApplyState(context.Background(), snapshot)
+	require.NoError(t, err)
+
+	// the removed template and the changed container are destroyed
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+
+	// the changed container and the new template are created
+	testAssertMethodCalled(t, mp, "Create", 2)
+
+	// the unchanged network and image cache are refreshed, the image cache
+	// is refreshed again when the network is attached
+	testAssertMethodCalled(t, mp, "Refresh", 3)
+
+	sf := testLoadState(t)
+	require.Equal(t, 4, sf.ResourceCount())
+
+	_, err = sf.FindResource("resource.template.consul_config")
+	require.Error(t, err)
+
+	r, err = sf.FindResource("resource.template.new_config")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestApplyTargetsOnlyCreatesTargetAndDependencies(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.ApplyTargets(context.Background(), "../../examples/single_file/container.hcl", nil, "", []string{"resource.template.consul_config"})
+	require.NoError(t, err)
+
+	// image cache, two variables and the template
+	testAssertMethodCalled(t, mp, "Create", 4)
+
+	sf := testLoadState(t)
+
+	_, err = sf.FindResource("resource.template.consul_config")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.container.consul")
+	require.Error(t, err)
+
+	_, err = sf.FindResource("resource.network.onprem")
+	require.Error(t, err)
+}
+
+func TestApplyTargetsCreatesDependencies(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	_, err := e.ApplyTargets(context.Background(), "../../examples/single_file/container.hcl", nil, "", []string{"resource.container.consul"})
+	require.NoError(t, err)
+
+	sf := testLoadState(t)
+
+	_, err = sf.FindResource("resource.container.consul")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.template.consul_config")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("output.consul_addr")
+	require.Error(t, err)
+}
+
+func TestApplyTargetsLeavesOtherResourcesInState(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+
+	_, err := e.ApplyTargets(context.Background(), "../../examples/single_file/container.hcl", nil, "", []string{"resource.network.onprem"})
+	require.NoError(t, err)
+
+	// resources removed from the config are not destroyed as they are not targeted
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+
+	sf := testLoadState(t)
+
+	_, err = sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.network.cloud")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.container.container")
+	require.NoError(t, err)
+}
+
+func TestApplyTargetsReturnsErrorWhenTargetNotFound(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.ApplyTargets(context.Background(), "../../examples/single_file/container.hcl", nil, "", []string{"resource.container.missing"})
+	require.Error(t, err)
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+}
+
+func TestDestroyTargetsDestroysTargetAndDependents(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, complexState)
+
+	err := e.DestroyTargets(context.Background(), []string{"resource.template.mytemplate"}, false)
+	require.NoError(t, err)
+
+	// the template and the container that depends on it
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+
+	sf := testLoadState(t)
+	require.Equal(t, 2, sf.ResourceCount())
+
+	_, err = sf.FindResource("resource.network.cloud")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.image_cache.default")
+	require.NoError(t, err)
+}
+
+func TestDestroyTargetsDetachesImageCacheFromNetwork(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, complexState)
+
+	err := e.DestroyTargets(context.Background(), []string{"resource.network.cloud"}, false)
+	require.NoError(t, err)
+
+	// the network and the container, the image cache is not destroyed
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+	testAssertMethodCalled(t, mp, "Refresh", 1)
+
+	sf := testLoadState(t)
+
+	ic, err := sf.FindResource("resource.image_cache.default")
+	require.NoError(t, err)
+	require.Empty(t, ic.GetDependencies())
+}
+
 func TestDestroyCallsProviderDestroyForEachProvider(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, existingState)
 
@@ -785,3 +1033,144 @@ var disabledAndCreatedState = `
   ]
 }
 `
+
+func TestApplyPublishesResourceEvents(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	e.events = events.NewBus()
+
+	mutex := sync.Mutex{}
+	received := []events.Event{}
+	e.events.Subscribe(func(ev events.Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		received = append(received, ev)
+	})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	started := map[string]bool{}
+	finished := map[string]bool{}
+	for _, ev := range received {
+		switch ev.Type {
+		case events.ResourceCreateStarted:
+			started[ev.ResourceID] = true
+		case events.ResourceCreateFinished:
+			finished[ev.ResourceID] = true
+		}
+	}
+
+	require.True(t, started["resource.network.onprem"])
+	require.True(t, finished["resource.network.onprem"])
+	require.True(t, finished["resource.container.consul"])
+}
+
+func TestApplyPublishesFailedEventOnError(t *testing.T) {
+	e, _ := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
+	e.events = events.NewBus()
+
+	mutex := sync.Mutex{}
+	var failed *events.Event
+	e.events.Subscribe(func(ev events.Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if ev.Type == events.ResourceCreateFailed {
+			failed = &ev
+		}
+	})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.Error(t, err)
+
+	require.NotNil(t, failed)
+	require.Equal(t, "resource.network.onprem", failed.ResourceID)
+	require.Equal(t, "boom", failed.Error)
+}
+
+func TestApplyCancelledMarksInterruptedResourcesAsCancelled(t *testing.T) {
+	e, _ := setupTests(t, map[string]error{"onprem": context.Canceled})
+	e.events = events.NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// cancel the apply once the network has started
+	e.events.Subscribe(func(ev events.Event) {
+		if ev.Type == events.ResourceCreateStarted && ev.ResourceID == "resource.network.onprem" {
+			cancel()
+		}
+	})
+
+	_, err := e.Apply(ctx, "../../examples/single_file/container.hcl")
+	require.ErrorIs(t, err, ErrCancelled)
+
+	sf := testLoadState(t)
+
+	r, err := sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCancelled, r.Metadata().Properties[constants.PropertyStatus])
+
+	// resources that were not started should not be in the state
+	_, err = sf.FindResource("resource.container.consul")
+	require.Error(t, err)
+
+	ri, err := LoadResumeInfo()
+	require.NoError(t, err)
+	require.NotNil(t, ri)
+	require.Contains(t, ri.Path, "examples/single_file/container.hcl")
+}
+
+func TestApplyCallsProviderDestroyAndCreateForCancelledResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, strings.Replace(failedState, `"status": "failed"`, `"status": "cancelled"`, 1))
+
+	err := saveResumeInfo(ResumeInfo{Path: "../../examples/single_file/container.hcl"})
+	require.NoError(t, err)
+
+	_, err = e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+	testAssertMethodCalled(t, mp, "Create", 7) // ImageCache are always created
+
+	// a completed apply removes the resume file
+	ri, err := LoadResumeInfo()
+	require.NoError(t, err)
+	require.Nil(t, ri)
+}
+
+func TestApplyWithKeepGoingReturnsFailuresAndSkippedResources(t *testing.T) {
+	e, _ := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
+	e.keepGoing = true
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.Error(t, err)
+
+	var ae *ApplyError
+	require.ErrorAs(t, err, &ae)
+
+	require.Len(t, ae.Failures, 1)
+	require.Equal(t, "resource.network.onprem", ae.Failures[0].ID)
+	require.Equal(t, "boom", ae.Failures[0].Error)
+	require.Equal(t, []string{"resource.container.consul"}, ae.Failures[0].Skipped)
+	require.Empty(t, ae.NotStarted)
+}
+
+func TestApplyWithoutKeepGoingDoesNotStartResourcesAfterFailure(t *testing.T) {
+	e, mp := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
+	e.failures = &failureRecorder{}
+	e.failures.failed(&types.ResourceBase{Meta: types.Meta{ID: "resource.network.onprem", Type: "network"}}, fmt.Errorf("boom"))
+	e.ctx = context.Background()
+	e.config = hclconfig.NewConfig()
+
+	r := &types.ResourceBase{Meta: types.Meta{ID: "resource.template.consul_config", Name: "consul_config", Type: "template", Properties: map[string]any{}}}
+
+	err := e.createCallback(r)
+	require.NoError(t, err)
+
+	require.Empty(t, mp.Providers)
+
+	ae := e.failures.applyError(nil, fmt.Errorf("boom"))
+	require.Equal(t, []string{"resource.template.consul_config"}, ae.(*ApplyError).NotStarted)
+}
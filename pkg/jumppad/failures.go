@@ -0,0 +1,157 @@
+package jumppad
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+)
+
+// ResourceFailure is a resource that could not be created along with the
+// resources that were skipped because they depend on it
+type ResourceFailure struct {
+	ID      string   `json:"id"`
+	Type    string   `json:"type"`
+	Error   string   `json:"error"`
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ApplyError is returned when one or more resources fail to be created, it
+// wraps the error returned by the parser
+type ApplyError struct {
+	Failures []ResourceFailure
+
+	// NotStarted contains the resources that were not started because the
+	// apply stopped after the first failure, it is empty when the engine
+	// keeps going after a failure
+	NotStarted []string
+
+	err error
+}
+
+func (a *ApplyError) Error() string {
+	return a.err.Error()
+}
+
+func (a *ApplyError) Unwrap() error {
+	return a.err
+}
+
+// failureRecorder records the resources that fail during an apply, it is
+// safe to use from the concurrent walk callbacks
+type failureRecorder struct {
+	mutex      sync.Mutex
+	failures   []ResourceFailure
+	notStarted []string
+}
+
+func (f *failureRecorder) failed(r types.Resource, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.failures = append(f.failures, ResourceFailure{ID: r.Metadata().ID, Type: r.Metadata().Type, Error: err.Error()})
+}
+
+func (f *failureRecorder) skipped(r types.Resource) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.notStarted = append(f.notStarted, r.Metadata().ID)
+}
+
+func (f *failureRecorder) hasFailures() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return len(f.failures) > 0
+}
+
+// applyError returns an ApplyError wrapping err that lists the failed
+// resources and their dependents in the given config, nil is returned when
+// no resources failed
+func (f *failureRecorder) applyError(c *hclconfig.Config, err error) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.failures) == 0 || err == nil {
+		return err
+	}
+
+	ae := &ApplyError{err: err}
+
+	dependents := map[string][]string{}
+	if c != nil {
+		for id, deps := range dependencyGraph(c) {
+			for _, d := range deps {
+				dependents[d] = append(dependents[d], id)
+			}
+		}
+	}
+
+	for _, rf := range f.failures {
+		for _, d := range transitiveDependents(dependents, rf.ID) {
+			if isReported(d) {
+				rf.Skipped = append(rf.Skipped, d)
+			}
+		}
+
+		ae.Failures = append(ae.Failures, rf)
+	}
+
+	sort.Slice(ae.Failures, func(i, j int) bool { return ae.Failures[i].ID < ae.Failures[j].ID })
+
+	for _, id := range f.notStarted {
+		if isReported(id) {
+			ae.NotStarted = append(ae.NotStarted, id)
+		}
+	}
+
+	sort.Strings(ae.NotStarted)
+
+	return ae
+}
+
+// transitiveDependents returns the ids of all the resources that depend on
+// the resource with the given id either directly or through another resource
+func transitiveDependents(dependents map[string][]string, id string) []string {
+	seen := map[string]bool{}
+
+	var visit func(id string)
+	visit = func(id string) {
+		for _, d := range dependents[id] {
+			if !seen[d] {
+				seen[d] = true
+				visit(d)
+			}
+		}
+	}
+
+	visit(id)
+
+	out := []string{}
+	for d := range seen {
+		out = append(out, d)
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+// isReported returns false for variables, locals, outputs and modules which
+// are not created by a provider
+func isReported(id string) bool {
+	fqrn, err := resources.ParseFQRN(id)
+	if err != nil {
+		return false
+	}
+
+	switch fqrn.Type {
+	case resources.TypeVariable, resources.TypeLocal, resources.TypeOutput, resources.TypeModule:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,273 @@
+package jumppad
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+)
+
+// GraphNode is a resource in the dependency graph
+type GraphNode struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Module string `json:"module,omitempty"`
+
+	// Status is the status of the resource in the state, empty when the
+	// resource has not been created
+	Status string `json:"status,omitempty"`
+
+	// Action is the action a plan would take for the resource, it is only
+	// set when the graph is created with a plan
+	Action PlanAction `json:"action,omitempty"`
+}
+
+// GraphEdge is a dependency between two resources, the resource is created
+// after the resource it depends on
+type GraphEdge struct {
+	Resource  string `json:"resource"`
+	DependsOn string `json:"depends_on"`
+}
+
+// Graph is the dependency graph for a configuration
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// NewGraph creates the dependency graph for the parsed configuration, the
+// status of each resource is read from the state. When plan is not nil the
+// action for each resource is added to the nodes and resources that the plan
+// would destroy are included.
+// Variables and locals are not included as they are not created by a
+// provider.
+func NewGraph(c *hclconfig.Config, state *hclconfig.Config, plan *Plan) *Graph {
+	g := &Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	nodes := map[string]bool{}
+
+	for _, r := range c.Resources {
+		if r.Metadata().Type == resources.TypeVariable || r.Metadata().Type == resources.TypeLocal {
+			continue
+		}
+
+		n := GraphNode{ID: r.Metadata().ID, Type: r.Metadata().Type, Module: r.Metadata().Module}
+
+		if state != nil {
+			if sr, err := state.FindResource(n.ID); err == nil {
+				n.Status, _ = sr.Metadata().Properties[constants.PropertyStatus].(string)
+			}
+		}
+
+		if r.GetDisabled() {
+			n.Status = constants.StatusDisabled
+		}
+
+		g.Nodes = append(g.Nodes, n)
+		nodes[n.ID] = true
+	}
+
+	if plan != nil {
+		for _, rp := range plan.Resources {
+			if nodes[rp.ID] || rp.Action != PlanActionDestroy {
+				continue
+			}
+
+			n := GraphNode{ID: rp.ID, Type: rp.Type, Status: constants.StatusCreated}
+			if fqrn, err := resources.ParseFQRN(rp.ID); err == nil {
+				n.Module = fqrn.Module
+			}
+
+			g.Nodes = append(g.Nodes, n)
+			nodes[n.ID] = true
+		}
+
+		actions := map[string]PlanAction{}
+		for _, rp := range plan.Resources {
+			actions[rp.ID] = rp.Action
+		}
+
+		for i := range g.Nodes {
+			g.Nodes[i].Action = actions[g.Nodes[i].ID]
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+
+	deps := dependencyGraph(c)
+	for _, id := range sortedKeys(deps) {
+		if !nodes[id] {
+			continue
+		}
+
+		ds := append([]string{}, deps[id]...)
+		sort.Strings(ds)
+
+		for _, d := range ds {
+			if nodes[d] {
+				g.Edges = append(g.Edges, GraphEdge{Resource: id, DependsOn: d})
+			}
+		}
+	}
+
+	return g
+}
+
+// DOT returns the graph in the Graphviz DOT format, resources in a module
+// are grouped in a cluster
+func (g *Graph) DOT() string {
+	sb := &strings.Builder{}
+
+	sb.WriteString("digraph jumppad {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+
+	for _, m := range g.modules() {
+		nodes := g.moduleNodes(m)
+
+		if m == "" {
+			for _, n := range nodes {
+				fmt.Fprintf(sb, "  %s;\n", dotNode(n))
+			}
+
+			continue
+		}
+
+		fmt.Fprintf(sb, "  subgraph %q {\n", "cluster_module."+m)
+		fmt.Fprintf(sb, "    label=%q;\n", "module."+m)
+
+		for _, n := range nodes {
+			fmt.Fprintf(sb, "    %s;\n", dotNode(n))
+		}
+
+		sb.WriteString("  }\n")
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(sb, "  %q -> %q;\n", e.DependsOn, e.Resource)
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart, resources in a module
+// are grouped in a subgraph
+func (g *Graph) Mermaid() string {
+	sb := &strings.Builder{}
+
+	// mermaid ids can not contain dots, use the index of the node
+	ids := map[string]string{}
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	sb.WriteString("flowchart LR\n")
+
+	for i, m := range g.modules() {
+		nodes := g.moduleNodes(m)
+
+		indent := "  "
+		if m != "" {
+			fmt.Fprintf(sb, "  subgraph m%d [\"module.%s\"]\n", i, m)
+			indent = "    "
+		}
+
+		for _, n := range nodes {
+			fmt.Fprintf(sb, "%s%s[\"%s<br/>%s\"]\n", indent, ids[n.ID], n.ID, nodeDescription(n))
+
+			if n.Action != "" && n.Action != PlanActionNone {
+				fmt.Fprintf(sb, "%sclass %s %s\n", indent, ids[n.ID], n.Action)
+			}
+		}
+
+		if m != "" {
+			sb.WriteString("  end\n")
+		}
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(sb, "  %s --> %s\n", ids[e.DependsOn], ids[e.Resource])
+	}
+
+	for _, a := range []PlanAction{PlanActionCreate, PlanActionRefresh, PlanActionReplace, PlanActionDestroy} {
+		fmt.Fprintf(sb, "  classDef %s %s\n", a, mermaidActionStyles[a])
+	}
+
+	return sb.String()
+}
+
+var mermaidActionStyles = map[PlanAction]string{
+	PlanActionCreate:  "stroke:#2da44e,stroke-width:2px",
+	PlanActionRefresh: "stroke:#d4a72c,stroke-width:2px",
+	PlanActionReplace: "stroke:#bc4c00,stroke-width:2px",
+	PlanActionDestroy: "stroke:#cf222e,stroke-width:2px,stroke-dasharray:4",
+}
+
+var dotActionColors = map[PlanAction]string{
+	PlanActionCreate:  "green",
+	PlanActionRefresh: "goldenrod",
+	PlanActionReplace: "orange",
+	PlanActionDestroy: "red",
+}
+
+func dotNode(n GraphNode) string {
+	attrs := fmt.Sprintf("label=%q", n.ID+"\n"+nodeDescription(n))
+
+	if c, ok := dotActionColors[n.Action]; ok {
+		attrs += fmt.Sprintf(", color=%s, penwidth=2", c)
+	}
+
+	if n.Action == PlanActionDestroy || n.Status == constants.StatusDisabled {
+		attrs += ", style=\"rounded,dashed\""
+	}
+
+	return fmt.Sprintf("%q [%s]", n.ID, attrs)
+}
+
+// nodeDescription returns the type, status and planned action for a node
+func nodeDescription(n GraphNode) string {
+	status := n.Status
+	if status == "" {
+		status = "not created"
+	}
+
+	parts := []string{n.Type, status}
+
+	if n.Action != "" && n.Action != PlanActionNone {
+		parts = append(parts, fmt.Sprintf("will %s", n.Action))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// modules returns the modules in the graph, the root module is returned first
+func (g *Graph) modules() []string {
+	ms := map[string]bool{"": true}
+	for _, n := range g.Nodes {
+		ms[n.Module] = true
+	}
+
+	out := []string{}
+	for m := range ms {
+		out = append(out, m)
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+func (g *Graph) moduleNodes(module string) []GraphNode {
+	nodes := []GraphNode{}
+	for _, n := range g.Nodes {
+		if n.Module == module {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes
+}
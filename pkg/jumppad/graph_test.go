@@ -0,0 +1,101 @@
+package jumppad
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraphState() *hclconfig.Config {
+	s := hclconfig.NewConfig()
+
+	s.AppendResource(&types.ResourceBase{Meta: types.Meta{Name: "main", Type: "network", Properties: map[string]any{constants.PropertyStatus: constants.StatusCreated}}})
+	s.AppendResource(&types.ResourceBase{Meta: types.Meta{Name: "dev", Type: "k8s_cluster", Properties: map[string]any{constants.PropertyStatus: constants.StatusFailed}}})
+	s.AppendResource(&types.ResourceBase{Meta: types.Meta{Name: "old", Type: "container", Properties: map[string]any{constants.PropertyStatus: constants.StatusCreated}}})
+
+	return s
+}
+
+func findGraphNode(t *testing.T, g *Graph, id string) GraphNode {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+
+	t.Fatalf("node %s not found in graph", id)
+	return GraphNode{}
+}
+
+func TestNewGraphAddsNodesWithStatus(t *testing.T) {
+	c := testTimingsConfig()
+	c.AppendResource(&resources.Variable{ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "version", Type: resources.TypeVariable}}})
+
+	g := NewGraph(c, testGraphState(), nil)
+
+	require.Len(t, g.Nodes, 5)
+	require.Equal(t, constants.StatusCreated, findGraphNode(t, g, "resource.network.main").Status)
+	require.Equal(t, constants.StatusFailed, findGraphNode(t, g, "resource.k8s_cluster.dev").Status)
+	require.Empty(t, findGraphNode(t, g, "resource.image_cache.cache").Status)
+
+	n := findGraphNode(t, g, "module.apps.resource.helm.vault")
+	require.Equal(t, "helm", n.Type)
+	require.Equal(t, "apps", n.Module)
+}
+
+func TestNewGraphAddsEdges(t *testing.T) {
+	g := NewGraph(testTimingsConfig(), nil, nil)
+
+	require.Contains(t, g.Edges, GraphEdge{Resource: "resource.k8s_cluster.dev", DependsOn: "resource.network.main"})
+	require.Contains(t, g.Edges, GraphEdge{Resource: "resource.image_cache.cache", DependsOn: "resource.network.main"})
+	require.Contains(t, g.Edges, GraphEdge{Resource: "module.apps", DependsOn: "resource.k8s_cluster.dev"})
+	require.Contains(t, g.Edges, GraphEdge{Resource: "module.apps.resource.helm.vault", DependsOn: "module.apps"})
+}
+
+func TestNewGraphWithPlanAddsActions(t *testing.T) {
+	p := &Plan{Resources: []ResourcePlan{
+		{ID: "resource.network.main", Type: "network", Action: PlanActionNone},
+		{ID: "resource.k8s_cluster.dev", Type: "k8s_cluster", Action: PlanActionReplace},
+		{ID: "resource.container.old", Type: "container", Action: PlanActionDestroy},
+	}}
+
+	g := NewGraph(testTimingsConfig(), testGraphState(), p)
+
+	require.Equal(t, PlanActionNone, findGraphNode(t, g, "resource.network.main").Action)
+	require.Equal(t, PlanActionReplace, findGraphNode(t, g, "resource.k8s_cluster.dev").Action)
+
+	// resources that are removed from the config are added to the graph
+	n := findGraphNode(t, g, "resource.container.old")
+	require.Equal(t, PlanActionDestroy, n.Action)
+	require.Equal(t, "container", n.Type)
+}
+
+func TestGraphDOTGroupsModules(t *testing.T) {
+	g := NewGraph(testTimingsConfig(), testGraphState(), nil)
+
+	dot := g.DOT()
+
+	require.Contains(t, dot, `subgraph "cluster_module.apps" {`)
+	require.Contains(t, dot, `"resource.network.main" -> "resource.k8s_cluster.dev";`)
+	require.Contains(t, dot, `label="resource.network.main\nnetwork, created"`)
+}
+
+func TestGraphMermaidMarksPlannedChanges(t *testing.T) {
+	p := &Plan{Resources: []ResourcePlan{
+		{ID: "resource.network.main", Type: "network", Action: PlanActionCreate},
+	}}
+
+	g := NewGraph(testTimingsConfig(), nil, p)
+
+	m := g.Mermaid()
+
+	// nodes are sorted by id so the network is the last node
+	require.Contains(t, m, `n4["resource.network.main<br/>network, not created, will create"]`)
+	require.Contains(t, m, "class n4 create")
+	require.Contains(t, m, "n4 --> n3")
+	require.Contains(t, m, `subgraph m1 ["module.apps"]`)
+}
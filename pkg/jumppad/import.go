@@ -0,0 +1,95 @@
+package jumppad
+
+import (
+	"fmt"
+
+	hclerrors "github.com/jumppad-labs/hclconfig/errors"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+)
+
+// Import adds an existing object that was not created by Jumppad to the state
+// as the resource with the given id. The resource is read from the
+// configuration at path and the provider checks that the object referenced by
+// ref, a Docker id or name, matches the configuration. Only resources with a
+// provider that implements config.Importer can be imported.
+//
+// Once imported the resource has the status created and is managed by
+// subsequent applies and destroys
+func (e *EngineImpl) Import(path string, variables map[string]string, variablesFile string, id string, ref string) (types.Resource, error) {
+	// hold the lock so the state does not change while the resource is added
+	unlock, err := config.LockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	c, err := loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := e.ParseConfigWithVariables(path, variables, variablesFile)
+	if err != nil {
+		// process errors can be ignored as the resources have not been
+		// created, references to outputs of other resources are not set
+		ce, ok := err.(*hclerrors.ConfigError)
+		if !ok || ce.ContainsErrors() {
+			return nil, err
+		}
+	}
+
+	r, err := parsed.FindResource(id)
+	if err != nil {
+		return nil, fmt.Errorf(`resource "%s" does not exist in the configuration`, id)
+	}
+
+	if r.GetDisabled() {
+		return nil, fmt.Errorf(`resource "%s" is disabled and can not be imported`, id)
+	}
+
+	sr, err := c.FindResource(id)
+	if err == nil {
+		if sr.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
+			return nil, fmt.Errorf(`resource "%s" already exists in the state`, id)
+		}
+
+		// failed and tainted resources are replaced by the imported resource
+		err = c.RemoveResource(sr)
+		if err != nil {
+			return nil, fmt.Errorf(`unable to remove resource "%s" from state, %s`, id, err)
+		}
+	}
+
+	p := e.providers.GetProvider(r)
+	if p == nil {
+		return nil, fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
+	}
+
+	im, ok := p.(config.Importer)
+	if !ok {
+		return nil, fmt.Errorf(`resources of type "%s" can not be imported`, r.Metadata().Type)
+	}
+
+	e.log.Info("Importing resource", "ref", id, "object", ref)
+
+	err = im.Import(ref)
+	if err != nil {
+		return nil, fmt.Errorf(`unable to import "%s" as resource "%s": %s`, ref, id, err)
+	}
+
+	r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+
+	err = c.AppendResource(r)
+	if err != nil {
+		return nil, fmt.Errorf(`unable to add resource "%s" to state, %s`, id, err)
+	}
+
+	err = config.SaveState(c)
+	if err != nil {
+		return nil, fmt.Errorf("unable to save state: %s", err)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,147 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/testutils"
+	sdk "github.com/jumppad-labs/plugin-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+var importConfig = `
+resource "network" "onprem" {
+  subnet = "10.6.0.0/16"
+}
+
+resource "container" "db" {
+  image {
+    name = "postgres:15"
+  }
+
+  network {
+    id = resource.network.onprem.meta.id
+  }
+}
+
+resource "template" "config" {
+  source      = "data"
+  destination = "/tmp/config"
+}
+`
+
+type importProvider struct {
+	mocks.Provider
+	err  error
+	refs []string
+}
+
+func (i *importProvider) Import(ref string) error {
+	i.refs = append(i.refs, ref)
+	return i.err
+}
+
+// importProviders returns providers that implement import for containers and
+// networks
+type importProviders struct {
+	provider *importProvider
+}
+
+func (i *importProviders) GetProvider(r types.Resource) sdk.Provider {
+	if r.Metadata().Type == "template" {
+		return &mocks.Provider{}
+	}
+
+	return i.provider
+}
+
+func setupImportTests(t *testing.T, state string, err error) (*EngineImpl, *importProvider, string) {
+	testutils.SetupState(t, state)
+
+	p := &importProvider{err: err}
+
+	e := &EngineImpl{
+		log:       logger.NewTestLogger(t),
+		ctx:       context.Background(),
+		providers: &importProviders{provider: p},
+	}
+
+	path := writeLifecycleConfig(t, t.TempDir(), importConfig)
+
+	return e, p, path
+}
+
+func TestImportAddsResourceToStateAsCreated(t *testing.T) {
+	e, p, path := setupImportTests(t, "", nil)
+
+	r, err := e.Import(path, nil, "", "resource.container.db", "abc123")
+	require.NoError(t, err)
+	require.Equal(t, "resource.container.db", r.Metadata().ID)
+
+	require.Equal(t, []string{"abc123"}, p.refs)
+
+	sr, err := testLoadState(t).FindResource("resource.container.db")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, sr.Metadata().Properties[constants.PropertyStatus])
+	require.NotEmpty(t, sr.Metadata().Checksum.Parsed)
+}
+
+func TestImportDoesNotAddResourcesThatWereNotImported(t *testing.T) {
+	e, _, path := setupImportTests(t, "", nil)
+
+	_, err := e.Import(path, nil, "", "resource.container.db", "abc123")
+	require.NoError(t, err)
+
+	_, err = testLoadState(t).FindResource("resource.network.onprem")
+	require.Error(t, err)
+}
+
+func TestImportReturnsErrorWhenResourceNotInConfig(t *testing.T) {
+	e, p, path := setupImportTests(t, "", nil)
+
+	_, err := e.Import(path, nil, "", "resource.container.missing", "abc123")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not exist in the configuration")
+
+	require.Empty(t, p.refs)
+}
+
+func TestImportReturnsErrorWhenProviderDoesNotSupportImport(t *testing.T) {
+	e, _, path := setupImportTests(t, "", nil)
+
+	_, err := e.Import(path, nil, "", "resource.template.config", "abc123")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can not be imported")
+}
+
+func TestImportReturnsErrorAndDoesNotModifyStateWhenObjectDoesNotMatch(t *testing.T) {
+	e, _, path := setupImportTests(t, "", fmt.Errorf("image does not match"))
+
+	_, err := e.Import(path, nil, "", "resource.container.db", "abc123")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "image does not match")
+
+	// the state is not written when the import fails
+	c, _ := config.LoadState()
+	_, err = c.FindResource("resource.container.db")
+	require.Error(t, err)
+}
+
+func TestImportReturnsErrorWhenResourceAlreadyCreated(t *testing.T) {
+	e, p, path := setupImportTests(t, "", nil)
+
+	_, err := e.Import(path, nil, "", "resource.network.onprem", "net1")
+	require.NoError(t, err)
+
+	_, err = e.Import(path, nil, "", "resource.network.onprem", "net1")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already exists in the state")
+
+	require.Len(t, p.refs, 1)
+}
@@ -0,0 +1,146 @@
+package jumppad
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// ignoreAllChanges can be used in ignore_changes to ignore every attribute
+const ignoreAllChanges = "all"
+
+// Lifecycle contains the settings from the optional lifecycle block
+//
+//	resource "container" "db" {
+//	  lifecycle {
+//	    prevent_destroy = true
+//	    ignore_changes  = ["environment"]
+//	  }
+//	}
+//
+// Replaced resources are always destroyed before the new version is created,
+// create_before_destroy is not supported as most resources create objects
+// with names derived from the resource, such as the container name or the
+// network name. The new version would conflict with the existing objects and
+// destroying the existing version would remove the objects of the new version
+type Lifecycle struct {
+	// PreventDestroy stops the engine from destroying or replacing the resource
+	PreventDestroy bool `hcl:"prevent_destroy,optional" json:"prevent_destroy,omitempty"`
+
+	// IgnoreChanges is a list of attributes that are not used to detect changes
+	// to the resource, nested attributes use dot notation e.g. image.name.
+	// Ignored attributes are removed from the checksum of the configuration,
+	// changes detected by the provider such as a modified source file are
+	// still applied unless the value "all" is set, which ignores every change
+	IgnoreChanges []string `hcl:"ignore_changes,optional" json:"ignore_changes,omitempty"`
+}
+
+// IgnoresAll returns true when changes to all attributes are ignored
+func (l Lifecycle) IgnoresAll() bool {
+	for _, i := range l.IgnoreChanges {
+		if i == ignoreAllChanges {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsIgnored returns true when the attribute at the given path is ignored,
+// the path uses the same dot notation as the plan e.g. ports[0].host
+func (l Lifecycle) IsIgnored(path string) bool {
+	for _, i := range l.IgnoreChanges {
+		if i == ignoreAllChanges || i == path || strings.HasPrefix(path, i+".") || strings.HasPrefix(path, i+"[") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getLifecycle returns the lifecycle settings stored in the properties of
+// the resource, when the resource has no lifecycle block the default
+// settings are returned
+func getLifecycle(r types.Resource) Lifecycle {
+	lc := Lifecycle{}
+	getMetaArgument(r, constants.PropertyLifecycle, &lc)
+
+	return lc
+}
+
+// lifecycleChecksum generates a checksum from the attributes of the resource
+// that are not ignored
+func lifecycleChecksum(r types.Resource, lc Lifecycle) (string, error) {
+	attrs, err := resourceAttributes(r)
+	if err != nil {
+		return "", err
+	}
+
+	if lc.IgnoresAll() {
+		attrs = map[string]interface{}{}
+	}
+
+	for _, i := range lc.IgnoreChanges {
+		deleteAttribute(attrs, strings.Split(i, "."))
+	}
+
+	return utils.ChecksumFromInterface(attrs)
+}
+
+func deleteAttribute(attrs map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(attrs, path[0])
+		return
+	}
+
+	if m, ok := attrs[path[0]].(map[string]interface{}); ok {
+		deleteAttribute(m, path[1:])
+	}
+}
+
+// checkPreventDestroy returns an error when the plan destroys or replaces a
+// resource that has prevent_destroy set, when targets is not nil only the
+// targeted resources are checked
+func checkPreventDestroy(p *Plan, targets map[string]bool) error {
+	ids := []string{}
+
+	for _, r := range p.Resources {
+		if !r.PreventDestroy || (targets != nil && !targets[r.ID]) {
+			continue
+		}
+
+		if r.Action == PlanActionDestroy || r.Action == PlanActionReplace {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	return preventDestroyError(ids)
+}
+
+// checkResourcesPreventDestroy returns an error when any of the given resources
+// have prevent_destroy set
+func checkResourcesPreventDestroy(res []types.Resource) error {
+	ids := []string{}
+
+	for _, r := range res {
+		if getLifecycle(r).PreventDestroy {
+			ids = append(ids, r.Metadata().ID)
+		}
+	}
+
+	return preventDestroyError(ids)
+}
+
+func preventDestroyError(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"unable to destroy %s, lifecycle.prevent_destroy is set, remove the setting from the configuration to allow the resource to be destroyed",
+		strings.Join(ids, ", "),
+	)
+}
@@ -0,0 +1,217 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/stretchr/testify/require"
+)
+
+var lifecycleConfig = `
+resource "network" "onprem" {
+  subnet = "10.6.0.0/16"
+}
+
+resource "container" "db" {
+  image {
+    name = "postgres:15"
+  }
+
+  environment = {
+    POSTGRES_PASSWORD = "%s"
+  }
+
+  network {
+    id = resource.network.onprem.meta.id
+  }
+
+  lifecycle {
+    %s
+  }
+}
+`
+
+var lifecycleConfigWithoutDB = `
+resource "network" "onprem" {
+  subnet = "10.6.0.0/16"
+}
+`
+
+func writeLifecycleConfig(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, "main.hcl")
+
+	err := os.WriteFile(path, []byte(content), os.ModePerm)
+	require.NoError(t, err)
+
+	return path
+}
+
+func setupLifecycleTests(t *testing.T, password, lifecycle string) (*EngineImpl, *mocks.Providers, string) {
+	e, mp := setupTests(t, nil)
+
+	path := writeLifecycleConfig(t, t.TempDir(), fmt.Sprintf(lifecycleConfig, password, lifecycle))
+
+	_, err := e.Apply(context.Background(), path)
+	require.NoError(t, err)
+
+	return e, mp, path
+}
+
+func testTaintResource(t *testing.T, id string) {
+	c := testLoadState(t)
+
+	r, err := c.FindResource(id)
+	require.NoError(t, err)
+
+	r.Metadata().Properties[constants.PropertyStatus] = constants.StatusTainted
+
+	err = config.SaveState(c)
+	require.NoError(t, err)
+}
+
+func TestApplyStoresLifecycleInState(t *testing.T) {
+	setupLifecycleTests(t, "secret", `prevent_destroy = true`)
+
+	r, err := testLoadState(t).FindResource("resource.container.db")
+	require.NoError(t, err)
+
+	lc := getLifecycle(r)
+	require.True(t, lc.PreventDestroy)
+}
+
+func TestApplyReturnsErrorForInvalidLifecycle(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	path := writeLifecycleConfig(t, t.TempDir(), fmt.Sprintf(lifecycleConfig, "secret", `prevent_destroy = "nope"`))
+
+	_, err := e.Apply(context.Background(), path)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid lifecycle block")
+}
+
+func TestDestroyWithPreventDestroyReturnsErrorAndDestroysNothing(t *testing.T) {
+	e, mp, _ := setupLifecycleTests(t, "secret", `prevent_destroy = true`)
+
+	err := e.Destroy(context.Background(), false)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "resource.container.db")
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+
+	// the state must not be removed
+	_, err = testLoadState(t).FindResource("resource.container.db")
+	require.NoError(t, err)
+}
+
+func TestApplyRemovingResourceWithPreventDestroyReturnsError(t *testing.T) {
+	e, mp, path := setupLifecycleTests(t, "secret", `prevent_destroy = true`)
+
+	writeLifecycleConfig(t, filepath.Dir(path), lifecycleConfigWithoutDB)
+
+	_, err := e.Apply(context.Background(), path)
+	require.Error(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+
+	_, err = testLoadState(t).FindResource("resource.container.db")
+	require.NoError(t, err)
+}
+
+func TestPlanReturnsErrorWhenReplacingResourceWithPreventDestroy(t *testing.T) {
+	e, _, path := setupLifecycleTests(t, "secret", `prevent_destroy = true`)
+
+	testTaintResource(t, "resource.container.db")
+
+	p, err := e.Plan(path, nil, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "prevent_destroy")
+
+	// the plan is returned with the error so the resources can be shown
+	require.NotNil(t, p)
+	require.True(t, findPlan(t, p, "resource.container.db").PreventDestroy)
+}
+
+func TestDiffIgnoresChangesToIgnoredAttributes(t *testing.T) {
+	e, _, path := setupLifecycleTests(t, "secret", `ignore_changes = ["environment"]`)
+
+	writeLifecycleConfig(t, filepath.Dir(path), fmt.Sprintf(lifecycleConfig, "changed", `ignore_changes = ["environment"]`))
+
+	_, changed, _, _, err := e.Diff(path, nil, "")
+	require.NoError(t, err)
+
+	for _, r := range changed {
+		require.NotEqual(t, "resource.container.db", r.Metadata().ID)
+	}
+}
+
+func TestDiffDetectsChangesToAttributesNotIgnored(t *testing.T) {
+	e, _, path := setupLifecycleTests(t, "secret", `ignore_changes = ["image"]`)
+
+	writeLifecycleConfig(t, filepath.Dir(path), fmt.Sprintf(lifecycleConfig, "changed", `ignore_changes = ["image"]`))
+
+	_, changed, _, _, err := e.Diff(path, nil, "")
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, r := range changed {
+		ids = append(ids, r.Metadata().ID)
+	}
+
+	require.Contains(t, ids, "resource.container.db")
+}
+
+func TestApplyReturnsErrorForCreateBeforeDestroy(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	path := writeLifecycleConfig(t, t.TempDir(), fmt.Sprintf(lifecycleConfig, "secret", `create_before_destroy = true`))
+
+	_, err := e.Apply(context.Background(), path)
+	require.ErrorContains(t, err, "create_before_destroy")
+}
+
+func TestApplyDestroysBeforeCreatingReplacedResourcesByDefault(t *testing.T) {
+	e, _, path := setupLifecycleTests(t, "secret", ``)
+
+	testTaintResource(t, "resource.container.db")
+
+	e.events = events.NewBus()
+
+	mutex := sync.Mutex{}
+	received := []events.Type{}
+	e.events.Subscribe(func(ev events.Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if ev.ResourceID == "resource.container.db" {
+			received = append(received, ev.Type)
+		}
+	})
+
+	_, err := e.Apply(context.Background(), path)
+	require.NoError(t, err)
+
+	require.Equal(t, []events.Type{
+		events.ResourceDestroyStarted,
+		events.ResourceDestroyFinished,
+		events.ResourceCreateStarted,
+		events.ResourceCreateFinished,
+	}, received)
+}
+
+func TestLifecycleIsIgnoredMatchesNestedPaths(t *testing.T) {
+	lc := Lifecycle{IgnoreChanges: []string{"environment", "image.name"}}
+
+	require.True(t, lc.IsIgnored("environment"))
+	require.True(t, lc.IsIgnored("environment.FOO"))
+	require.True(t, lc.IsIgnored("image.name"))
+	require.False(t, lc.IsIgnored("image.id"))
+	require.False(t, lc.IsIgnored("environments"))
+}
@@ -0,0 +1,354 @@
+package jumppad
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Meta arguments are blocks that can be added to any resource to control how
+// the engine manages the resource, they are not part of the resource type
+// and are not passed to the provider.
+const (
+	lifecycleBlock = "lifecycle"
+	retryBlock     = "retry"
+	timeoutsBlock  = "timeouts"
+)
+
+// withMetaArguments wraps a parser callback, the meta argument blocks are read
+// from the source of each resource and stored in the resource properties
+// before the callback is called. hclconfig ignores blocks that are not
+// defined by the resource type so the blocks are read directly from the
+// source file
+func withMetaArguments(callback hclconfig.WalkCallback) hclconfig.WalkCallback {
+	sr := &sourceReader{files: map[string]*hcl.File{}}
+
+	return func(r types.Resource) error {
+		b, err := sr.resourceBlock(r)
+		if err != nil {
+			return err
+		}
+
+		setSensitive(r, b)
+
+		if b != nil {
+			err = setMetaArguments(r, b)
+			if err != nil {
+				return err
+			}
+		}
+
+		return callback(r)
+	}
+}
+
+func setMetaArguments(r types.Resource, b *hclsyntax.Block) error {
+	lc := &Lifecycle{}
+	ok, err := decodeMetaArgument(r, b, lifecycleBlock, lc)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		sort.Strings(lc.IgnoreChanges)
+		setMetaArgument(r, constants.PropertyLifecycle, *lc)
+
+		// the checksum is used to detect changes, regenerate it without the
+		// ignored attributes
+		if len(lc.IgnoreChanges) > 0 {
+			cs, err := lifecycleChecksum(r, *lc)
+			if err != nil {
+				return fmt.Errorf(`unable to generate checksum for resource "%s": %s`, r.Metadata().ID, err)
+			}
+
+			r.Metadata().Checksum.Parsed = cs
+		}
+	}
+
+	rt := &Retry{}
+	ok, err = decodeMetaArgument(r, b, retryBlock, rt)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		err = rt.validate()
+		if err != nil {
+			return fmt.Errorf(`invalid retry block for resource "%s": %s`, r.Metadata().ID, err)
+		}
+
+		setMetaArgument(r, constants.PropertyRetry, *rt)
+	}
+
+	to := &Timeouts{}
+	ok, err = decodeMetaArgument(r, b, timeoutsBlock, to)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		err = to.validate()
+		if err != nil {
+			return fmt.Errorf(`invalid timeouts block for resource "%s": %s`, r.Metadata().ID, err)
+		}
+
+		setMetaArgument(r, constants.PropertyTimeouts, *to)
+	}
+
+	return nil
+}
+
+// decodeMetaArgument decodes the block with the given name into v, false is
+// returned when the resource does not contain the block. The values must be
+// literals as the block is decoded without an evaluation context
+func decodeMetaArgument(r types.Resource, b *hclsyntax.Block, name string, v interface{}) (bool, error) {
+	for _, mb := range b.Body.Blocks {
+		if mb.Type != name {
+			continue
+		}
+
+		diags := gohcl.DecodeBody(mb.Body, nil, v)
+		if diags.HasErrors() {
+			return false, fmt.Errorf(`invalid %s block for resource "%s": %s`, name, r.Metadata().ID, diags.Error())
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func setMetaArgument(r types.Resource, key string, v interface{}) {
+	if r.Metadata().Properties == nil {
+		r.Metadata().Properties = map[string]interface{}{}
+	}
+
+	r.Metadata().Properties[key] = v
+}
+
+// setSensitive marks outputs as sensitive so that the value is redacted.
+// An output is sensitive when it sets sensitive = true, the value calls the
+// sensitive function or references a sensitive attribute, such as the value
+// of a random_password. Outputs that reference sensitive variables are
+// marked by markSensitiveVariables
+func setSensitive(r types.Resource, b *hclsyntax.Block) {
+	if r.Metadata().Type != resources.TypeOutput {
+		return
+	}
+
+	if r.(*resources.Output).Sensitive {
+		setMetaArgument(r, constants.PropertySensitive, true)
+		return
+	}
+
+	if b != nil && b.Body.Attributes["value"] != nil && callsSensitive(b.Body.Attributes["value"].Expr) {
+		setMetaArgument(r, constants.PropertySensitive, true)
+		return
+	}
+
+	for _, l := range r.Metadata().Links {
+		if config.IsSensitiveReference(l) {
+			setMetaArgument(r, constants.PropertySensitive, true)
+			return
+		}
+	}
+}
+
+// markSensitiveVariables marks the variables that set sensitive = true or
+// have a default that calls the sensitive function, and the outputs that
+// reference them, as sensitive. Variables are not walked by the parser so
+// they are marked once the configuration has been parsed. The values of the
+// marked variables and outputs are added to the values redacted from the
+// logs as values set with --var do not call the sensitive function
+func markSensitiveVariables(c *hclconfig.Config, values map[string]string) {
+	if c == nil {
+		return
+	}
+
+	variables := map[string]bool{}
+
+	for _, r := range c.Resources {
+		if r.Metadata().Type != resources.TypeVariable {
+			continue
+		}
+
+		v := r.(*resources.Variable)
+
+		a, ok := v.Default.(*hcl.Attribute)
+		if !v.Sensitive && (!ok || !callsSensitive(a.Expr)) {
+			continue
+		}
+
+		setMetaArgument(r, constants.PropertySensitive, true)
+		variables[resources.FQRNFromResource(r).String()] = true
+
+		// values set with --var are only used for variables in the root
+		// module
+		if val, set := values[r.Metadata().Name]; set && r.Metadata().Module == "" {
+			logger.AddSensitiveValues(val)
+		}
+
+		if ok {
+			if val, diags := a.Expr.Value(nil); !diags.HasErrors() && val.Type() == cty.String && val.IsKnown() && !val.IsNull() {
+				logger.AddSensitiveValues(val.AsString())
+			}
+		}
+	}
+
+	if len(variables) == 0 {
+		return
+	}
+
+	// variables are not added to the links of the output, the references
+	// are read from the value in the source
+	sr := &sourceReader{files: map[string]*hcl.File{}}
+
+	for _, r := range c.Resources {
+		if r.Metadata().Type != resources.TypeOutput {
+			continue
+		}
+
+		b, err := sr.resourceBlock(r)
+		if err != nil || b == nil || b.Body.Attributes["value"] == nil {
+			continue
+		}
+
+		for _, t := range b.Body.Attributes["value"].Expr.Variables() {
+			if t.RootName() != resources.TypeVariable || len(t) < 2 {
+				continue
+			}
+
+			a, ok := t[1].(hcl.TraverseAttr)
+			if !ok {
+				continue
+			}
+
+			fqrn := resources.FQRN{Module: r.Metadata().Module, Type: resources.TypeVariable, Resource: a.Name}
+			if variables[fqrn.String()] {
+				setMetaArgument(r, constants.PropertySensitive, true)
+				logger.AddSensitiveValues(config.SensitiveValues(r)...)
+				break
+			}
+		}
+	}
+}
+
+// callsSensitive returns true when the expression calls the sensitive function
+func callsSensitive(expr hcl.Expression) bool {
+	se, ok := expr.(hclsyntax.Expression)
+	if !ok {
+		return false
+	}
+
+	found := false
+	hclsyntax.VisitAll(se, func(n hclsyntax.Node) hcl.Diagnostics {
+		if f, ok := n.(*hclsyntax.FunctionCallExpr); ok && f.Name == config.SensitiveFunction {
+			found = true
+		}
+
+		return nil
+	})
+
+	return found
+}
+
+// getMetaArgument reads the meta argument stored in the resource properties
+// into v, v is not modified when the property does not exist. Resources
+// loaded from the state contain a map rather than the type so the value is
+// converted using JSON
+func getMetaArgument(r types.Resource, key string, v interface{}) {
+	p, ok := r.Metadata().Properties[key]
+	if !ok || p == nil {
+		return
+	}
+
+	d, err := json.Marshal(p)
+	if err != nil {
+		return
+	}
+
+	json.Unmarshal(d, v)
+}
+
+// sourceReader reads the resource blocks from the source files, files are
+// cached as the parser callback is called for every resource
+type sourceReader struct {
+	mutex sync.Mutex
+	files map[string]*hcl.File
+}
+
+// resourceBlock returns the block that defines the resource or nil when the
+// resource was not defined in a file
+func (sr *sourceReader) resourceBlock(r types.Resource) (*hclsyntax.Block, error) {
+	if r.Metadata().File == "" {
+		return nil, nil
+	}
+
+	f, err := sr.file(r.Metadata().File)
+	if err != nil {
+		return nil, err
+	}
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, nil
+	}
+
+	for _, b := range body.Blocks {
+		if b.TypeRange.Start.Line != r.Metadata().Line {
+			continue
+		}
+
+		if b.Type == types.TypeResource &&
+			len(b.Labels) == 2 &&
+			b.Labels[0] == r.Metadata().Type &&
+			b.Labels[1] == r.Metadata().Name {
+			return b, nil
+		}
+
+		if b.Type == resources.TypeOutput &&
+			r.Metadata().Type == resources.TypeOutput &&
+			len(b.Labels) == 1 &&
+			b.Labels[0] == r.Metadata().Name {
+			return b, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (sr *sourceReader) file(path string) (*hcl.File, error) {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
+	if f, ok := sr.files[path]; ok {
+		return f, nil
+	}
+
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %s: %s", path, err)
+	}
+
+	f, diags := hclsyntax.ParseConfig(d, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("unable to parse file %s: %s", path, diags.Error())
+	}
+
+	sr.files[path] = f
+
+	return f, nil
+}
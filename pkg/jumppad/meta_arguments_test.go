@@ -0,0 +1,132 @@
+package jumppad
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/stretchr/testify/require"
+)
+
+var sensitiveConfig = `
+resource "random_password" "db" {
+  length = 16
+}
+
+output "password" {
+  value = resource.random_password.db.value
+}
+
+output "length" {
+  value = resource.random_password.db.length
+}
+`
+
+func TestApplyMarksOutputsReferencingSensitiveValues(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	path := writeLifecycleConfig(t, t.TempDir(), sensitiveConfig)
+
+	_, err := e.Apply(context.Background(), path)
+	require.NoError(t, err)
+
+	c := testLoadState(t)
+
+	r, err := c.FindResource("output.password")
+	require.NoError(t, err)
+	require.Equal(t, true, r.Metadata().Properties[constants.PropertySensitive])
+
+	r, err = c.FindResource("output.length")
+	require.NoError(t, err)
+	require.Nil(t, r.Metadata().Properties[constants.PropertySensitive])
+}
+
+var sensitiveFunctionConfig = `
+variable "db_password" {
+  default = sensitive("supersecret")
+}
+
+variable "db_user" {
+  default = "admin"
+}
+
+output "password" {
+  value = variable.db_password
+}
+
+output "token" {
+  value = sensitive("token123")
+}
+
+output "user" {
+  value = variable.db_user
+}
+`
+
+func TestApplyMarksVariablesAndOutputsUsingSensitiveFunction(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	path := writeLifecycleConfig(t, t.TempDir(), sensitiveFunctionConfig)
+
+	_, err := e.Apply(context.Background(), path)
+	require.NoError(t, err)
+
+	c := testLoadState(t)
+
+	r, err := c.FindResource("variable.db_password")
+	require.NoError(t, err)
+	require.Equal(t, true, r.Metadata().Properties[constants.PropertySensitive])
+
+	r, err = c.FindResource("output.password")
+	require.NoError(t, err)
+	require.Equal(t, true, r.Metadata().Properties[constants.PropertySensitive])
+	require.Equal(t, "supersecret", r.(*resources.Output).Value)
+
+	r, err = c.FindResource("output.token")
+	require.NoError(t, err)
+	require.Equal(t, true, r.Metadata().Properties[constants.PropertySensitive])
+
+	r, err = c.FindResource("output.user")
+	require.NoError(t, err)
+	require.Nil(t, r.Metadata().Properties[constants.PropertySensitive])
+}
+
+var sensitiveAttributeConfig = `
+variable "db_password" {
+  default   = "supersecret"
+  sensitive = true
+}
+
+output "password" {
+  value = variable.db_password
+}
+
+output "token" {
+  value     = "token123"
+  sensitive = true
+}
+`
+
+func TestApplyMarksVariablesAndOutputsWithSensitiveAttribute(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	path := writeLifecycleConfig(t, t.TempDir(), sensitiveAttributeConfig)
+
+	_, err := e.Apply(context.Background(), path)
+	require.NoError(t, err)
+
+	c := testLoadState(t)
+
+	r, err := c.FindResource("variable.db_password")
+	require.NoError(t, err)
+	require.Equal(t, true, r.Metadata().Properties[constants.PropertySensitive])
+
+	r, err = c.FindResource("output.password")
+	require.NoError(t, err)
+	require.Equal(t, true, r.Metadata().Properties[constants.PropertySensitive])
+
+	r, err = c.FindResource("output.token")
+	require.NoError(t, err)
+	require.Equal(t, true, r.Metadata().Properties[constants.PropertySensitive])
+}
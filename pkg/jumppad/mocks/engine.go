@@ -7,8 +7,14 @@ import (
 
 	hclconfig "github.com/jumppad-labs/hclconfig"
 
+	events "github.com/jumppad-labs/jumppad/pkg/clients/events"
+
+	jumppad "github.com/jumppad-labs/jumppad/pkg/jumppad"
+
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	types "github.com/jumppad-labs/hclconfig/types"
 )
 
@@ -69,6 +75,58 @@ func (_m *Engine) ApplyWithVariables(ctx context.Context, path string, variables
 	return r0, r1
 }
 
+// ApplyState provides a mock function with given fields: ctx, state
+func (_m *Engine) ApplyState(ctx context.Context, state *hclconfig.Config) (*hclconfig.Config, error) {
+	ret := _m.Called(ctx, state)
+
+	var r0 *hclconfig.Config
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hclconfig.Config) (*hclconfig.Config, error)); ok {
+		return rf(ctx, state)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *hclconfig.Config) *hclconfig.Config); ok {
+		r0 = rf(ctx, state)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hclconfig.Config)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *hclconfig.Config) error); ok {
+		r1 = rf(ctx, state)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ApplyTargets provides a mock function with given fields: ctx, path, variables, variablesFile, targets
+func (_m *Engine) ApplyTargets(ctx context.Context, path string, variables map[string]string, variablesFile string, targets []string) (*hclconfig.Config, error) {
+	ret := _m.Called(ctx, path, variables, variablesFile, targets)
+
+	var r0 *hclconfig.Config
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, string, []string) (*hclconfig.Config, error)); ok {
+		return rf(ctx, path, variables, variablesFile, targets)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, string, []string) *hclconfig.Config); ok {
+		r0 = rf(ctx, path, variables, variablesFile, targets)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hclconfig.Config)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, map[string]string, string, []string) error); ok {
+		r1 = rf(ctx, path, variables, variablesFile, targets)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Config provides a mock function with given fields:
 func (_m *Engine) Config() *hclconfig.Config {
 	ret := _m.Called()
@@ -99,6 +157,46 @@ func (_m *Engine) Destroy(ctx context.Context, force bool) error {
 	return r0
 }
 
+// DestroyTargets provides a mock function with given fields: ctx, targets, force
+func (_m *Engine) DestroyTargets(ctx context.Context, targets []string, force bool) error {
+	ret := _m.Called(ctx, targets, force)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, bool) error); ok {
+		r0 = rf(ctx, targets, force)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetectDrift provides a mock function with given fields: fix
+func (_m *Engine) DetectDrift(fix bool) (*jumppad.DriftReport, error) {
+	ret := _m.Called(fix)
+
+	var r0 *jumppad.DriftReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(bool) (*jumppad.DriftReport, error)); ok {
+		return rf(fix)
+	}
+	if rf, ok := ret.Get(0).(func(bool) *jumppad.DriftReport); ok {
+		r0 = rf(fix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*jumppad.DriftReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(fix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Diff provides a mock function with given fields: path, variables, variablesFile
 func (_m *Engine) Diff(path string, variables map[string]string, variablesFile string) ([]types.Resource, []types.Resource, []types.Resource, *hclconfig.Config, error) {
 	ret := _m.Called(path, variables, variablesFile)
@@ -152,6 +250,74 @@ func (_m *Engine) Diff(path string, variables map[string]string, variablesFile s
 	return r0, r1, r2, r3, r4
 }
 
+// Events provides a mock function with given fields:
+func (_m *Engine) Events() *events.Bus {
+	ret := _m.Called()
+
+	var r0 *events.Bus
+	if rf, ok := ret.Get(0).(func() *events.Bus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*events.Bus)
+		}
+	}
+
+	return r0
+}
+
+// Import provides a mock function with given fields: path, variables, variablesFile, id, ref
+func (_m *Engine) Import(path string, variables map[string]string, variablesFile string, id string, ref string) (types.Resource, error) {
+	ret := _m.Called(path, variables, variablesFile, id, ref)
+
+	var r0 types.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string, string, string) (types.Resource, error)); ok {
+		return rf(path, variables, variablesFile, id, ref)
+	}
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string, string, string) types.Resource); ok {
+		r0 = rf(path, variables, variablesFile, id, ref)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, map[string]string, string, string, string) error); ok {
+		r1 = rf(path, variables, variablesFile, id, ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Plan provides a mock function with given fields: path, variables, variablesFile
+func (_m *Engine) Plan(path string, variables map[string]string, variablesFile string) (*jumppad.Plan, error) {
+	ret := _m.Called(path, variables, variablesFile)
+
+	var r0 *jumppad.Plan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string) (*jumppad.Plan, error)); ok {
+		return rf(path, variables, variablesFile)
+	}
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string) *jumppad.Plan); ok {
+		r0 = rf(path, variables, variablesFile)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*jumppad.Plan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, map[string]string, string) error); ok {
+		r1 = rf(path, variables, variablesFile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ParseConfig provides a mock function with given fields: _a0
 func (_m *Engine) ParseConfig(_a0 string) (*hclconfig.Config, error) {
 	ret := _m.Called(_a0)
@@ -204,6 +370,58 @@ func (_m *Engine) ParseConfigWithVariables(_a0 string, _a1 map[string]string, _a
 	return r0, r1
 }
 
+// SetParallelism provides a mock function with given fields: p
+func (_m *Engine) SetParallelism(p jumppad.Parallelism) error {
+	ret := _m.Called(p)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(jumppad.Parallelism) error); ok {
+		r0 = rf(p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetGracePeriod provides a mock function with given fields: d
+func (_m *Engine) SetGracePeriod(d time.Duration) {
+	_m.Called(d)
+}
+
+// SetKeepGoing provides a mock function with given fields: k
+func (_m *Engine) SetKeepGoing(k bool) {
+	_m.Called(k)
+}
+
+// Start provides a mock function with given fields: ctx
+func (_m *Engine) Start(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Stop provides a mock function with given fields: ctx
+func (_m *Engine) Stop(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewEngine interface {
 	mock.TestingT
 	Cleanup(func())
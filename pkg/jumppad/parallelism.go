@@ -0,0 +1,115 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parallelism limits the number of resources that the engine processes at
+// the same time. Independent resources are processed concurrently, on
+// machines with limited resources creating several clusters at once can
+// starve Docker.
+type Parallelism struct {
+	// Limit is the maximum number of resources processed at the same time,
+	// 0 means there is no limit
+	Limit int
+
+	// TypeLimits is the maximum number of resources of the given type that
+	// are processed at the same time, e.g. {"k8s_cluster": 1}
+	TypeLimits map[string]int
+}
+
+// ParseTypeLimits parses a list of type limits in the form type=limit,
+// e.g. k8s_cluster=1
+func ParseTypeLimits(limits []string) (map[string]int, error) {
+	tl := map[string]int{}
+
+	for _, l := range limits {
+		parts := strings.Split(l, "=")
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf(`invalid type limit "%s", limits must be specified as type=limit e.g. k8s_cluster=1`, l)
+		}
+
+		n, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf(`invalid type limit "%s", the limit must be a number`, l)
+		}
+
+		tl[parts[0]] = n
+	}
+
+	return tl, nil
+}
+
+func (p Parallelism) validate() error {
+	if p.Limit < 0 {
+		return fmt.Errorf("parallelism must be 0 or greater, got %d", p.Limit)
+	}
+
+	for t, l := range p.TypeLimits {
+		if l < 1 {
+			return fmt.Errorf(`parallelism for type "%s" must be 1 or greater, got %d`, t, l)
+		}
+	}
+
+	return nil
+}
+
+// limiter restricts the number of concurrent provider operations, a slot is
+// held for the duration of a provider call
+type limiter struct {
+	global chan struct{}
+	types  map[string]chan struct{}
+}
+
+func newLimiter(p Parallelism) *limiter {
+	l := &limiter{types: map[string]chan struct{}{}}
+
+	if p.Limit > 0 {
+		l.global = make(chan struct{}, p.Limit)
+	}
+
+	for t, n := range p.TypeLimits {
+		l.types[t] = make(chan struct{}, n)
+	}
+
+	return l
+}
+
+// acquire blocks until a slot is available for the resource type, the
+// returned function releases the slot. If the context is cancelled while
+// waiting the operation is allowed to continue, providers skip their work
+// when the context is cancelled.
+func (l *limiter) acquire(ctx context.Context, resourceType string) func() {
+	if l == nil {
+		return func() {}
+	}
+
+	// the type slot is always acquired first so that a resource waiting for
+	// a type slot does not hold a global slot
+	sems := []chan struct{}{}
+	if s, ok := l.types[resourceType]; ok {
+		sems = append(sems, s)
+	}
+
+	if l.global != nil {
+		sems = append(sems, l.global)
+	}
+
+	acquired := []chan struct{}{}
+	for _, s := range sems {
+		select {
+		case s <- struct{}{}:
+			acquired = append(acquired, s)
+		case <-ctx.Done():
+		}
+	}
+
+	return func() {
+		for _, s := range acquired {
+			<-s
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package jumppad
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runLimited runs n operations of the given type at the same time and
+// returns the maximum number that were running concurrently
+func runLimited(l *limiter, resourceType string, n int) int32 {
+	running := int32(0)
+	max := int32(0)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			release := l.acquire(context.Background(), resourceType)
+			defer release()
+
+			r := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if r <= m || atomic.CompareAndSwapInt32(&max, m, r) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+
+	wg.Wait()
+
+	return max
+}
+
+func TestParseTypeLimitsReturnsLimits(t *testing.T) {
+	tl, err := ParseTypeLimits([]string{"k8s_cluster=1", "container=3"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"k8s_cluster": 1, "container": 3}, tl)
+}
+
+func TestParseTypeLimitsReturnsErrorWhenInvalid(t *testing.T) {
+	_, err := ParseTypeLimits([]string{"k8s_cluster"})
+	require.Error(t, err)
+
+	_, err = ParseTypeLimits([]string{"k8s_cluster=one"})
+	require.Error(t, err)
+}
+
+func TestSetParallelismReturnsErrorWhenInvalid(t *testing.T) {
+	e := &EngineImpl{}
+
+	err := e.SetParallelism(Parallelism{Limit: -1})
+	require.Error(t, err)
+
+	err = e.SetParallelism(Parallelism{TypeLimits: map[string]int{"k8s_cluster": 0}})
+	require.Error(t, err)
+
+	require.Nil(t, e.limiter)
+}
+
+func TestLimiterRestrictsGlobalConcurrency(t *testing.T) {
+	l := newLimiter(Parallelism{Limit: 2})
+
+	require.Equal(t, int32(2), runLimited(l, "container", 6))
+}
+
+func TestLimiterRestrictsTypeConcurrency(t *testing.T) {
+	l := newLimiter(Parallelism{TypeLimits: map[string]int{"k8s_cluster": 1}})
+
+	require.Equal(t, int32(1), runLimited(l, "k8s_cluster", 4))
+
+	// other types are not limited
+	require.Equal(t, int32(4), runLimited(l, "container", 4))
+}
+
+func TestNilLimiterDoesNotRestrictConcurrency(t *testing.T) {
+	var l *limiter
+
+	require.Equal(t, int32(4), runLimited(l, "container", 4))
+}
+
+func TestLimiterDoesNotBlockWhenContextCancelled(t *testing.T) {
+	l := newLimiter(Parallelism{Limit: 1})
+
+	release := l.acquire(context.Background(), "container")
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		l.acquire(ctx, "container")()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("acquire blocked with a cancelled context")
+	}
+}
@@ -0,0 +1,398 @@
+package jumppad
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+)
+
+// PlanAction is the action the engine takes for a resource when the
+// configuration is applied
+type PlanAction string
+
+const (
+	// PlanActionCreate the resource does not exist and will be created
+	PlanActionCreate PlanAction = "create"
+	// PlanActionRefresh the resource exists and the provider will update it
+	PlanActionRefresh PlanAction = "refresh"
+	// PlanActionReplace the resource will be destroyed and then created
+	PlanActionReplace PlanAction = "replace"
+	// PlanActionDestroy the resource is no longer in the configuration and
+	// will be destroyed
+	PlanActionDestroy PlanAction = "destroy"
+	// PlanActionNone the resource has not changed
+	PlanActionNone PlanAction = "no-op"
+)
+
+// AttributeChange describes a change to a single attribute of a resource,
+// Path uses dot notation for nested attributes e.g. image.name or ports[0].host.
+// When an attribute is added Old is nil, when removed New is nil
+type AttributeChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+
+	// Sensitive is true when the attribute contains sensitive data such as a
+	// password, the values are replaced by Redact
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// ResourcePlan is the planned action for a single resource
+type ResourcePlan struct {
+	ID      string            `json:"id"`
+	Type    string            `json:"type"`
+	Action  PlanAction        `json:"action"`
+	Reason  string            `json:"reason,omitempty"`
+	Changes []AttributeChange `json:"changes,omitempty"`
+
+	// PreventDestroy is true when the lifecycle of the resource prevents the
+	// engine from destroying or replacing it
+	PreventDestroy bool `json:"prevent_destroy,omitempty"`
+}
+
+// PlanSummary contains the number of resources for each action
+type PlanSummary struct {
+	Create  int `json:"create"`
+	Refresh int `json:"refresh"`
+	Replace int `json:"replace"`
+	Destroy int `json:"destroy"`
+	NoOp    int `json:"no_op"`
+}
+
+// Plan is the set of actions the engine takes to apply a configuration
+type Plan struct {
+	Resources []ResourcePlan `json:"resources"`
+	Summary   PlanSummary    `json:"summary"`
+}
+
+// HasChanges returns true when applying the plan would modify resources
+func (p *Plan) HasChanges() bool {
+	return p.Summary.Create+p.Summary.Refresh+p.Summary.Replace+p.Summary.Destroy > 0
+}
+
+// Redact replaces the values of the sensitive attribute changes with
+// config.SensitiveValue
+func (p *Plan) Redact() {
+	for i := range p.Resources {
+		for j, c := range p.Resources[i].Changes {
+			if !c.Sensitive {
+				continue
+			}
+
+			if c.Old != nil {
+				p.Resources[i].Changes[j].Old = config.SensitiveValue
+			}
+
+			if c.New != nil {
+				p.Resources[i].Changes[j].New = config.SensitiveValue
+			}
+		}
+	}
+}
+
+func (p *Plan) add(rp ResourcePlan) {
+	p.Resources = append(p.Resources, rp)
+
+	switch rp.Action {
+	case PlanActionCreate:
+		p.Summary.Create++
+	case PlanActionRefresh:
+		p.Summary.Refresh++
+	case PlanActionReplace:
+		p.Summary.Replace++
+	case PlanActionDestroy:
+		p.Summary.Destroy++
+	default:
+		p.Summary.NoOp++
+	}
+}
+
+// Plan compares the configuration at the given path with the state and
+// returns the action that will be taken for every resource along with the
+// attributes that have changed. When the plan would destroy a resource that
+// has lifecycle.prevent_destroy set the plan is returned along with an error
+// so that the resources can be shown
+func (e *EngineImpl) Plan(path string, variables map[string]string, variablesFile string) (*Plan, error) {
+	new, changed, removed, cfg, err := e.Diff(path, variables, variablesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// there is no state when nothing has been applied
+	past, err := config.LoadState()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unable to load state: %w", err)
+	}
+
+	plan, err := buildPlan(new, changed, removed, cfg, past)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, checkPreventDestroy(plan, nil)
+}
+
+// buildPlan creates a plan from the result of Diff and the current state
+func buildPlan(new, changed, removed []types.Resource, cfg, past *hclconfig.Config) (*Plan, error) {
+	var err error
+
+	isNew := map[string]bool{}
+	for _, r := range new {
+		isNew[r.Metadata().ID] = true
+	}
+
+	isChanged := map[string]bool{}
+	for _, r := range changed {
+		isChanged[r.Metadata().ID] = true
+	}
+
+	plan := &Plan{Resources: []ResourcePlan{}}
+
+	for _, r := range cfg.Resources {
+		rp := ResourcePlan{
+			ID:     r.Metadata().ID,
+			Type:   r.Metadata().Type,
+			Action: PlanActionNone,
+		}
+
+		sr, _ := past.FindResource(r.Metadata().ID)
+
+		switch {
+		case r.GetDisabled():
+			// disabled resources are only destroyed when they have been created
+			if sr == nil || sr.Metadata().Properties[constants.PropertyStatus] != constants.StatusCreated {
+				continue
+			}
+
+			rp.Action = PlanActionDestroy
+			rp.Reason = "resource has been disabled"
+
+			// disabled resources are not processed, the lifecycle comes from the state
+			rp.PreventDestroy = getLifecycle(sr).PreventDestroy
+
+		case isNew[r.Metadata().ID] || sr == nil:
+			rp.Action = PlanActionCreate
+
+		case sr.Metadata().Properties[constants.PropertyStatus] == constants.StatusTainted:
+			rp.Action = PlanActionReplace
+			rp.Reason = "resource is tainted"
+
+		case sr.Metadata().Properties[constants.PropertyStatus] == constants.StatusFailed:
+			rp.Action = PlanActionReplace
+			rp.Reason = "resource failed to create"
+
+		case sr.Metadata().Properties[constants.PropertyStatus] == constants.StatusCancelled:
+			rp.Action = PlanActionReplace
+			rp.Reason = "resource creation was cancelled"
+
+		case sr.Metadata().Properties[constants.PropertyStatus] == constants.StatusStopped:
+			rp.Action = PlanActionRefresh
+			rp.Reason = "resource is stopped and will be started"
+
+		case isChanged[r.Metadata().ID]:
+			rp.Action = PlanActionRefresh
+			rp.Reason = "provider detected changes"
+
+			if sr.Metadata().Checksum.Parsed != r.Metadata().Checksum.Parsed {
+				rp.Reason = "configuration changed"
+			}
+		}
+
+		lc := getLifecycle(r)
+		if rp.Action != PlanActionDestroy {
+			rp.PreventDestroy = lc.PreventDestroy
+		}
+
+		// only compare the attributes when the configuration has changed,
+		// the values of computed attributes are set by the provider and are
+		// not known until the resource has been applied
+		if sr != nil && rp.Action != PlanActionDestroy && sr.Metadata().Checksum.Parsed != r.Metadata().Checksum.Parsed {
+			rp.Changes, err = diffAttributes(sr, r)
+			if err != nil {
+				return nil, fmt.Errorf("unable to compare attributes for resource %s: %s", r.Metadata().ID, err)
+			}
+
+			rp.Changes = filterComputedChanges(rp.Changes, config.ComputedPaths(r))
+			rp.Changes = filterIgnoredChanges(rp.Changes, lc)
+			markSensitiveChanges(rp.Changes, config.SensitivePaths(r))
+		}
+
+		plan.add(rp)
+	}
+
+	for _, r := range removed {
+		plan.add(ResourcePlan{
+			ID:             r.Metadata().ID,
+			Type:           r.Metadata().Type,
+			Action:         PlanActionDestroy,
+			Reason:         "resource is no longer in the configuration",
+			PreventDestroy: getLifecycle(r).PreventDestroy,
+		})
+	}
+
+	sort.SliceStable(plan.Resources, func(i, j int) bool {
+		return plan.Resources[i].ID < plan.Resources[j].ID
+	})
+
+	return plan, nil
+}
+
+// filterIgnoredChanges removes the changes to attributes that are ignored by
+// the lifecycle of the resource
+func filterIgnoredChanges(changes []AttributeChange, lc Lifecycle) []AttributeChange {
+	filtered := []AttributeChange{}
+	for _, c := range changes {
+		if !lc.IsIgnored(c.Path) {
+			filtered = append(filtered, c)
+		}
+	}
+
+	return filtered
+}
+
+// indexRegex matches the list indexes in an attribute path e.g. [0]
+var indexRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// filterComputedChanges removes the changes to attributes that are set by the
+// provider, the paths of the computed attributes do not contain indexes
+func filterComputedChanges(changes []AttributeChange, computed []string) []AttributeChange {
+	filtered := []AttributeChange{}
+	for _, c := range changes {
+		if !isComputedPath(c.Path, computed) {
+			filtered = append(filtered, c)
+		}
+	}
+
+	return filtered
+}
+
+func isComputedPath(path string, computed []string) bool {
+	path = indexRegex.ReplaceAllString(path, "")
+
+	for _, p := range computed {
+		if path == p || strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// markSensitiveChanges sets Sensitive for the changes to sensitive attributes
+// and to the attributes that contain them
+func markSensitiveChanges(changes []AttributeChange, sensitive []string) {
+	for i, c := range changes {
+		path := indexRegex.ReplaceAllString(c.Path, "")
+
+		for _, p := range sensitive {
+			if path == p || strings.HasPrefix(path, p+".") || strings.HasPrefix(p, path+".") {
+				changes[i].Sensitive = true
+				break
+			}
+		}
+	}
+}
+
+// diffAttributes returns the attributes that differ between two versions
+// of a resource, the meta data is not compared
+func diffAttributes(old, new types.Resource) ([]AttributeChange, error) {
+	o, err := resourceAttributes(old)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := resourceAttributes(new)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []AttributeChange{}
+	diffValues("", o, n, &changes)
+
+	return changes, nil
+}
+
+func resourceAttributes(r types.Resource) (map[string]interface{}, error) {
+	d, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := map[string]interface{}{}
+	err = json.Unmarshal(d, &attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(attrs, "meta")
+
+	return attrs, nil
+}
+
+func diffValues(path string, old, new interface{}, changes *[]AttributeChange) {
+	om, oldIsMap := old.(map[string]interface{})
+	nm, newIsMap := new.(map[string]interface{})
+
+	if oldIsMap && newIsMap {
+		keys := map[string]bool{}
+		for k := range om {
+			keys[k] = true
+		}
+
+		for k := range nm {
+			keys[k] = true
+		}
+
+		sorted := []string{}
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			diffValues(p, om[k], nm[k], changes)
+		}
+
+		return
+	}
+
+	ol, oldIsSlice := old.([]interface{})
+	nl, newIsSlice := new.([]interface{})
+
+	if oldIsSlice && newIsSlice {
+		for i := 0; i < len(ol) || i < len(nl); i++ {
+			var ov, nv interface{}
+			if i < len(ol) {
+				ov = ol[i]
+			}
+
+			if i < len(nl) {
+				nv = nl[i]
+			}
+
+			diffValues(fmt.Sprintf("%s[%d]", path, i), ov, nv, changes)
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, AttributeChange{Path: path, Old: old, New: new})
+	}
+}
@@ -0,0 +1,162 @@
+package jumppad
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/stretchr/testify/require"
+)
+
+func findPlan(t *testing.T, p *Plan, id string) ResourcePlan {
+	for _, r := range p.Resources {
+		if r.ID == id {
+			return r
+		}
+	}
+
+	require.Failf(t, "resource not found in plan", id)
+	return ResourcePlan{}
+}
+
+func TestPlanReturnsActionsForResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	require.Equal(t, PlanActionCreate, findPlan(t, p, "resource.network.onprem").Action)
+	require.Equal(t, PlanActionCreate, findPlan(t, p, "resource.container.consul").Action)
+	require.Equal(t, PlanActionRefresh, findPlan(t, p, "resource.template.consul_config").Action)
+	require.Equal(t, PlanActionDestroy, findPlan(t, p, "resource.network.cloud").Action)
+	require.Equal(t, PlanActionDestroy, findPlan(t, p, "resource.container.container").Action)
+
+	require.Equal(t, 2, p.Summary.Destroy)
+	require.True(t, p.HasChanges())
+
+	// plan must not modify any resources
+	testAssertMethodCalled(t, mp, "Create", 0)
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
+func TestPlanReturnsReplaceForTaintedResources(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, taintedState)
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	r := findPlan(t, p, "resource.network.onprem")
+	require.Equal(t, PlanActionReplace, r.Action)
+	require.Equal(t, "resource is tainted", r.Reason)
+}
+
+func TestPlanReturnsRefreshForStoppedResources(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, strings.Replace(taintedState, "tainted", "stopped", 1))
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	r := findPlan(t, p, "resource.network.onprem")
+	require.Equal(t, PlanActionRefresh, r.Action)
+	require.Equal(t, "resource is stopped and will be started", r.Reason)
+}
+
+func TestPlanReturnsAttributeChanges(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, existingState)
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	r := findPlan(t, p, "resource.template.consul_config")
+	require.NotEmpty(t, r.Changes)
+
+	paths := []string{}
+	for _, c := range r.Changes {
+		paths = append(paths, c.Path)
+	}
+
+	require.Contains(t, paths, "destination")
+}
+
+func TestPlanDoesNotReturnChangesForComputedAttributes(t *testing.T) {
+	changes := []AttributeChange{
+		{Path: "image.name", Old: "consul:1.8.1", New: "consul:1.9.0"},
+		{Path: "networks[0].assigned_address", Old: "10.6.0.2"},
+		{Path: "container_name", Old: "consul.container.local.jmpd.in"},
+	}
+
+	c := filterComputedChanges(changes, config.ComputedPaths(&container.Container{}))
+
+	require.Equal(t, []AttributeChange{changes[0]}, c)
+}
+
+func TestPlanCreatesResourcesMissingFromState(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, "")
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	require.Equal(t, PlanActionCreate, findPlan(t, p, "resource.container.consul").Action)
+}
+
+func TestDiffAttributesReturnsNestedChanges(t *testing.T) {
+	old := &container.Container{
+		Image: container.Image{Name: "consul:1.8.1"},
+		Ports: []container.Port{{Local: "8500"}},
+	}
+
+	new := &container.Container{
+		Image: container.Image{Name: "consul:1.9.0"},
+		Ports: []container.Port{{Local: "8500"}, {Local: "8501"}},
+	}
+
+	c, err := diffAttributes(old, new)
+	require.NoError(t, err)
+
+	require.Contains(t, c, AttributeChange{Path: "image.name", Old: "consul:1.8.1", New: "consul:1.9.0"})
+
+	found := false
+	for _, ch := range c {
+		if ch.Path == "ports[1]" {
+			require.Nil(t, ch.Old)
+			require.NotNil(t, ch.New)
+			found = true
+		}
+	}
+
+	require.True(t, found)
+}
+
+func TestMarkSensitiveChangesMarksSensitiveAttributes(t *testing.T) {
+	changes := []AttributeChange{
+		{Path: "image.name", Old: "consul:1.8.1", New: "consul:1.9.0"},
+		{Path: "image.password", Old: "secret", New: "changed"},
+		{Path: "image", Old: nil, New: map[string]interface{}{"password": "secret"}},
+	}
+
+	markSensitiveChanges(changes, []string{"image.password"})
+
+	require.False(t, changes[0].Sensitive)
+	require.True(t, changes[1].Sensitive)
+	require.True(t, changes[2].Sensitive)
+}
+
+func TestPlanRedactReplacesSensitiveValues(t *testing.T) {
+	p := &Plan{}
+	p.add(ResourcePlan{
+		ID:     "resource.container.db",
+		Action: PlanActionRefresh,
+		Changes: []AttributeChange{
+			{Path: "image.name", Old: "consul:1.8.1", New: "consul:1.9.0"},
+			{Path: "image.password", Old: nil, New: "secret", Sensitive: true},
+		},
+	})
+
+	p.Redact()
+
+	c := p.Resources[0].Changes
+	require.Equal(t, "consul:1.9.0", c[0].New)
+	require.Nil(t, c[1].Old)
+	require.Equal(t, config.SensitiveValue, c[1].New)
+}
@@ -0,0 +1,100 @@
+package jumppad
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// DefaultGracePeriod is the time providers are given to finish or roll back
+// the operation in progress when an apply is cancelled
+const DefaultGracePeriod = 30 * time.Second
+
+// ErrCancelled is returned when an apply is cancelled before all the
+// resources have been processed
+var ErrCancelled = errors.New("apply was cancelled before all resources were created")
+
+// ResumeInfo records the arguments of an apply that was cancelled so that it
+// can be resumed
+type ResumeInfo struct {
+	Path          string            `json:"path"`
+	Variables     map[string]string `json:"variables,omitempty"`
+	VariablesFile string            `json:"variables_file,omitempty"`
+	Targets       []string          `json:"targets,omitempty"`
+}
+
+// LoadResumeInfo returns the arguments of the last cancelled apply, nil is
+// returned when there is no cancelled apply to resume
+func LoadResumeInfo() (*ResumeInfo, error) {
+	d, err := os.ReadFile(utils.ResumePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to read resume file: %s", err)
+	}
+
+	ri := &ResumeInfo{}
+	err = json.Unmarshal(d, ri)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse resume file: %s", err)
+	}
+
+	return ri, nil
+}
+
+// saveResumeInfo writes the resume file, variables can contain secrets so
+// the file is only readable by the current user
+func saveResumeInfo(ri ResumeInfo) error {
+	d, err := json.MarshalIndent(ri, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(utils.ResumePath(), d, 0600)
+}
+
+func clearResumeInfo() error {
+	err := os.Remove(utils.ResumePath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// gracefulContext returns a context that is cancelled once the grace period
+// has elapsed after the parent is cancelled, the returned function releases
+// the context
+func gracefulContext(parent context.Context, grace time.Duration) (context.Context, context.CancelFunc) {
+	if grace <= 0 {
+		return context.WithCancel(parent)
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+
+	go func() {
+		select {
+		case <-parent.Done():
+		case <-ctx.Done():
+			return
+		}
+
+		t := time.NewTimer(grace)
+		defer t.Stop()
+
+		select {
+		case <-t.C:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
@@ -0,0 +1,50 @@
+package jumppad
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGracefulContextIsNotCancelledWithParent(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+
+	ctx, release := gracefulContext(parent, time.Minute)
+	defer release()
+
+	cancel()
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("context cancelled before the grace period elapsed")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestGracefulContextIsCancelledAfterGracePeriod(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+
+	ctx, release := gracefulContext(parent, 10*time.Millisecond)
+	defer release()
+
+	cancel()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context not cancelled after the grace period")
+	}
+}
+
+func TestGracefulContextWithoutGracePeriodIsCancelledWithParent(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+
+	ctx, release := gracefulContext(parent, 0)
+	defer release()
+
+	cancel()
+
+	require.Error(t, ctx.Err())
+}
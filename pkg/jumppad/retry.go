@@ -0,0 +1,257 @@
+package jumppad
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	sdk "github.com/jumppad-labs/plugin-sdk"
+)
+
+const (
+	defaultBackoff    = 1 * time.Second
+	defaultMaxBackoff = 1 * time.Minute
+)
+
+// Retry contains the settings from the optional retry block, when a resource
+// fails to create the engine destroys any partially created resources and
+// calls Create again after waiting for the backoff
+//
+//	resource "helm" "consul" {
+//	  retry {
+//	    attempts    = 3
+//	    backoff     = "5s"
+//	    max_backoff = "1m"
+//	  }
+//	}
+type Retry struct {
+	// Attempts is the maximum number of times Create is called, default 1
+	Attempts int `hcl:"attempts,optional" json:"attempts,omitempty"`
+
+	// Backoff is the time to wait before the first retry, the time doubles
+	// for every subsequent retry, default 1s
+	Backoff string `hcl:"backoff,optional" json:"backoff,omitempty"`
+
+	// MaxBackoff is the maximum time to wait between attempts, default 1m
+	MaxBackoff string `hcl:"max_backoff,optional" json:"max_backoff,omitempty"`
+}
+
+func (r Retry) validate() error {
+	// attempts is optional, 0 uses the default of a single attempt
+	if r.Attempts < 0 {
+		return fmt.Errorf("attempts must not be negative")
+	}
+
+	for _, d := range []string{r.Backoff, r.MaxBackoff} {
+		if d == "" {
+			continue
+		}
+
+		if _, err := time.ParseDuration(d); err != nil {
+			return fmt.Errorf("invalid duration %s: %s", d, err)
+		}
+	}
+
+	return nil
+}
+
+func (r Retry) attempts() int {
+	if r.Attempts < 1 {
+		return 1
+	}
+
+	return r.Attempts
+}
+
+func (r Retry) backoff() time.Duration {
+	return parseDurationOrDefault(r.Backoff, defaultBackoff)
+}
+
+// nextBackoff returns the time to wait after the given backoff
+func (r Retry) nextBackoff(current time.Duration) time.Duration {
+	max := parseDurationOrDefault(r.MaxBackoff, defaultMaxBackoff)
+
+	next := current * 2
+	if next > max {
+		return max
+	}
+
+	return next
+}
+
+// Timeouts contains the settings from the optional timeouts block, the
+// context passed to the provider has a deadline set to the timeout and the
+// operation fails when the timeout is exceeded
+//
+//	resource "container" "consul" {
+//	  timeouts {
+//	    create  = "5m"
+//	    destroy = "1m"
+//	  }
+//	}
+type Timeouts struct {
+	// Create is the maximum time for each attempt to create the resource
+	Create string `hcl:"create,optional" json:"create,omitempty"`
+
+	// Destroy is the maximum time to destroy the resource
+	Destroy string `hcl:"destroy,optional" json:"destroy,omitempty"`
+}
+
+func (t Timeouts) validate() error {
+	for _, d := range []string{t.Create, t.Destroy} {
+		if d == "" {
+			continue
+		}
+
+		if _, err := time.ParseDuration(d); err != nil {
+			return fmt.Errorf("invalid duration %s: %s", d, err)
+		}
+	}
+
+	return nil
+}
+
+func getRetry(r types.Resource) Retry {
+	rt := Retry{}
+	getMetaArgument(r, constants.PropertyRetry, &rt)
+
+	return rt
+}
+
+func getTimeouts(r types.Resource) Timeouts {
+	to := Timeouts{}
+	getMetaArgument(r, constants.PropertyTimeouts, &to)
+
+	return to
+}
+
+func parseDurationOrDefault(d string, def time.Duration) time.Duration {
+	pd, err := time.ParseDuration(d)
+	if err != nil || pd <= 0 {
+		return def
+	}
+
+	return pd
+}
+
+// errNotReturned is returned when a provider does not return within the grace
+// period after its timeout has expired
+var errNotReturned = errors.New("provider did not return after the timeout")
+
+// createResource calls Create on the provider. When the resource has a retry
+// block failed creates are retried with an exponential backoff, before each
+// retry the provider is asked to destroy anything left by the failed attempt
+func (e *EngineImpl) createResource(r types.Resource, p sdk.Provider) error {
+	rt := getRetry(r)
+	to := getTimeouts(r)
+	timeout := parseDurationOrDefault(to.Create, 0)
+
+	backoff := rt.backoff()
+
+	for attempt := 1; ; attempt++ {
+		err := e.runProvider(r, opCreate, withTimeout(timeout, e.gracePeriod, p.Create))
+
+		// a provider that is still running can not be destroyed or called
+		// again, the resource is left as failed
+		if err == nil || attempt >= rt.attempts() || e.ctx.Err() != nil || errors.Is(err, errNotReturned) {
+			return err
+		}
+
+		e.log.Info("Unable to create resource, retrying", "ref", r.Metadata().ID, "attempt", attempt, "backoff", backoff, "error", err)
+
+		e.events.Publish(events.Event{
+			Type:         events.ResourceCreateRetrying,
+			ResourceID:   r.Metadata().ID,
+			ResourceType: r.Metadata().Type,
+			Message:      fmt.Sprintf("attempt %d of %d failed, retrying in %s", attempt, rt.attempts(), backoff),
+			Error:        err.Error(),
+		})
+
+		destroy := withTimeout(parseDurationOrDefault(to.Destroy, 0), e.gracePeriod, func(ctx context.Context) error {
+			return p.Destroy(ctx, false)
+		})
+
+		derr := destroy(e.providerContext())
+		if errors.Is(derr, errNotReturned) {
+			return fmt.Errorf("%s, unable to clean up failed resource: %w", err, derr)
+		}
+
+		if derr != nil {
+			e.log.Debug("Unable to clean up failed resource", "ref", r.Metadata().ID, "error", derr)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-e.ctx.Done():
+			return err
+		}
+
+		backoff = rt.nextBackoff(backoff)
+	}
+}
+
+// destroyResource calls Destroy on the provider with the destroy timeout for
+// the resource
+func (e *EngineImpl) destroyResource(r types.Resource, p sdk.Provider, force bool) error {
+	timeout := parseDurationOrDefault(getTimeouts(r).Destroy, 0)
+
+	return e.runProvider(r, opDestroy, withTimeout(timeout, e.gracePeriod, func(ctx context.Context) error {
+		return p.Destroy(ctx, force)
+	}))
+}
+
+// withTimeout returns a function that calls f with a context that has the
+// given timeout, when timeout is 0 f is returned. When the deadline is
+// exceeded f is given the grace period to return, a provider that is still
+// running after the grace period is left in the background and errNotReturned
+// is returned
+func withTimeout(timeout, grace time.Duration, f func(ctx context.Context) error) func(ctx context.Context) error {
+	if timeout == 0 {
+		return f
+	}
+
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- f(ctx)
+		}()
+
+		var err error
+
+		select {
+		case err = <-done:
+		case <-ctx.Done():
+			// wait for the provider to return so that it can clean up, a
+			// provider that ignores the context is not waited for forever
+			t := time.NewTimer(grace)
+			defer t.Stop()
+
+			select {
+			case err = <-done:
+			case <-t.C:
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return fmt.Errorf("timed out after %s: %w", timeout, errNotReturned)
+				}
+
+				return fmt.Errorf("%s: %w", ctx.Err(), errNotReturned)
+			}
+		}
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			if err != nil {
+				return fmt.Errorf("timed out after %s: %s", timeout, err)
+			}
+
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+
+		return err
+	}
+}
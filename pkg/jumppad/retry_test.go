@@ -0,0 +1,229 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var retryConfig = `
+resource "container" "db" {
+  image {
+    name = "postgres:15"
+  }
+
+  retry {
+    attempts    = 3
+    backoff     = "%s"
+    max_backoff = "1m"
+  }
+
+  timeouts {
+    create = "5m"
+  }
+}
+`
+
+func setupRetryTests(t *testing.T, rt *Retry, to *Timeouts) (*EngineImpl, *container.Container) {
+	e := &EngineImpl{
+		log:         logger.NewTestLogger(t),
+		ctx:         context.Background(),
+		gracePeriod: 100 * time.Millisecond,
+	}
+
+	r := &container.Container{
+		ResourceBase: types.ResourceBase{
+			Meta: types.Meta{
+				ID:         "resource.container.db",
+				Name:       "db",
+				Type:       container.TypeContainer,
+				Properties: map[string]interface{}{},
+			},
+		},
+	}
+
+	if rt != nil {
+		setMetaArgument(r, constants.PropertyRetry, *rt)
+	}
+
+	if to != nil {
+		setMetaArgument(r, constants.PropertyTimeouts, *to)
+	}
+
+	return e, r
+}
+
+func TestCreateResourceRetriesUntilSuccess(t *testing.T) {
+	e, r := setupRetryTests(t, &Retry{Attempts: 3, Backoff: "1ms"}, nil)
+
+	p := &mocks.Provider{}
+	p.On("Create", mock.Anything).Return(fmt.Errorf("boom")).Twice()
+	p.On("Create", mock.Anything).Return(nil)
+	p.On("Destroy", mock.Anything, false).Return(nil)
+
+	err := e.createResource(r, p)
+	require.NoError(t, err)
+
+	p.AssertNumberOfCalls(t, "Create", 3)
+	p.AssertNumberOfCalls(t, "Destroy", 2)
+}
+
+func TestCreateResourceReturnsErrorWhenAttemptsExceeded(t *testing.T) {
+	e, r := setupRetryTests(t, &Retry{Attempts: 2, Backoff: "1ms"}, nil)
+
+	p := &mocks.Provider{}
+	p.On("Create", mock.Anything).Return(fmt.Errorf("boom"))
+	p.On("Destroy", mock.Anything, false).Return(nil)
+
+	err := e.createResource(r, p)
+	require.Error(t, err)
+
+	p.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestCreateResourceWithoutRetryCallsCreateOnce(t *testing.T) {
+	e, r := setupRetryTests(t, nil, nil)
+
+	p := &mocks.Provider{}
+	p.On("Create", mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := e.createResource(r, p)
+	require.Error(t, err)
+
+	p.AssertNumberOfCalls(t, "Create", 1)
+	p.AssertNotCalled(t, "Destroy", mock.Anything, mock.Anything)
+}
+
+func TestCreateResourceSetsDeadlineFromTimeout(t *testing.T) {
+	e, r := setupRetryTests(t, nil, &Timeouts{Create: "10ms"})
+
+	p := &mocks.Provider{}
+	p.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+
+		_, ok := ctx.Deadline()
+		require.True(t, ok)
+
+		<-ctx.Done()
+	}).Return(nil)
+
+	err := e.createResource(r, p)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timed out after 10ms")
+}
+
+func TestCreateResourceReturnsWhenTimeoutExpires(t *testing.T) {
+	e, r := setupRetryTests(t, nil, &Timeouts{Create: "10ms"})
+
+	// the provider does not check the context
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	p := &mocks.Provider{}
+	p.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		<-release
+	}).Return(nil)
+
+	start := time.Now()
+
+	err := e.createResource(r, p)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timed out after 10ms")
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestCreateResourceDoesNotRetryWhenProviderDoesNotReturn(t *testing.T) {
+	e, r := setupRetryTests(t, &Retry{Attempts: 3, Backoff: "1ms"}, &Timeouts{Create: "10ms"})
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	p := &mocks.Provider{}
+	p.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		<-release
+	}).Return(nil)
+	p.On("Destroy", mock.Anything, false).Return(nil)
+
+	err := e.createResource(r, p)
+	require.ErrorIs(t, err, errNotReturned)
+
+	p.AssertNumberOfCalls(t, "Create", 1)
+	p.AssertNotCalled(t, "Destroy", mock.Anything, mock.Anything)
+}
+
+func TestCreateResourceWaitsForProviderBeforeRetrying(t *testing.T) {
+	e, r := setupRetryTests(t, &Retry{Attempts: 2, Backoff: "1ms"}, &Timeouts{Create: "10ms", Destroy: "20ms"})
+
+	running := false
+
+	p := &mocks.Provider{}
+	p.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		require.False(t, running)
+		running = true
+
+		<-args.Get(0).(context.Context).Done()
+		time.Sleep(10 * time.Millisecond)
+
+		running = false
+	}).Return(fmt.Errorf("boom")).Once()
+	p.On("Create", mock.Anything).Return(nil)
+	p.On("Destroy", mock.Anything, false).Run(func(args mock.Arguments) {
+		require.False(t, running)
+
+		_, ok := args.Get(0).(context.Context).Deadline()
+		require.True(t, ok)
+	}).Return(nil)
+
+	err := e.createResource(r, p)
+	require.NoError(t, err)
+
+	p.AssertNumberOfCalls(t, "Create", 2)
+	p.AssertNumberOfCalls(t, "Destroy", 1)
+}
+
+func TestRetryValidateReturnsErrorForNegativeAttempts(t *testing.T) {
+	require.NoError(t, Retry{Backoff: "1s"}.validate())
+	require.Error(t, Retry{Attempts: -1}.validate())
+}
+
+func TestRetryNextBackoffDoublesUntilMax(t *testing.T) {
+	rt := Retry{Backoff: "1s", MaxBackoff: "3s"}
+
+	require.Equal(t, 1*time.Second, rt.backoff())
+	require.Equal(t, 2*time.Second, rt.nextBackoff(rt.backoff()))
+	require.Equal(t, 3*time.Second, rt.nextBackoff(2*time.Second))
+}
+
+func TestApplyStoresRetryAndTimeoutsInState(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	path := writeLifecycleConfig(t, t.TempDir(), fmt.Sprintf(retryConfig, "5s"))
+
+	_, err := e.Apply(context.Background(), path)
+	require.NoError(t, err)
+
+	r, err := testLoadState(t).FindResource("resource.container.db")
+	require.NoError(t, err)
+
+	require.Equal(t, Retry{Attempts: 3, Backoff: "5s", MaxBackoff: "1m"}, getRetry(r))
+	require.Equal(t, Timeouts{Create: "5m"}, getTimeouts(r))
+}
+
+func TestApplyReturnsErrorForInvalidRetry(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	path := writeLifecycleConfig(t, t.TempDir(), fmt.Sprintf(retryConfig, "soon"))
+
+	_, err := e.Apply(context.Background(), path)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid retry block")
+}
@@ -0,0 +1,364 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	ctypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/cache"
+	rcontainer "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	cp "github.com/otiai10/copy"
+)
+
+// Version is the version of the snapshot format, snapshots with a newer
+// version can not be restored
+const Version = 1
+
+const (
+	manifestFile = "manifest.json"
+	stateFile    = "state.json"
+	volumesDir   = "volumes"
+	dataDir      = "data"
+)
+
+// Manifest describes the contents of a snapshot
+type Manifest struct {
+	// Version of the snapshot format
+	Version int `json:"version"`
+	// Created is the time the snapshot was taken
+	Created time.Time `json:"created"`
+	// Workspace the snapshot was taken from
+	Workspace string `json:"workspace"`
+	// Volumes are the names of the Docker volumes in the snapshot
+	Volumes []string `json:"volumes"`
+	// Images are the names of the images used by the resources in the state
+	Images []string `json:"images"`
+}
+
+// Snapshot saves the state, volumes, data folder and image list of the
+// active workspace to a single gzipped tarball and restores them. The data
+// written by Kubernetes and Nomad clusters is stored in the cluster
+// containers and is not included, only the volumes mounted into the
+// clusters are saved. When state encryption is enabled the tarball is
+// encrypted with the state key.
+type Snapshot struct {
+	client container.ContainerTasks
+	tar    *tar.TarGz
+	log    logger.Logger
+}
+
+// New creates a new Snapshot
+func New(ct container.ContainerTasks, tg *tar.TarGz, l logger.Logger) *Snapshot {
+	return &Snapshot{ct, tg, l}
+}
+
+// Save writes a snapshot of the active workspace to w
+func (s *Snapshot) Save(w io.Writer) (*Manifest, error) {
+	c, err := config.LoadState()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load state: %s", err)
+	}
+
+	tmp, err := os.MkdirTemp(utils.JumppadTemp(), "snapshot")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	m := &Manifest{
+		Version:   Version,
+		Created:   time.Now(),
+		Workspace: utils.CurrentWorkspace(),
+		Volumes:   stateVolumes(c),
+		Images:    stateImages(c),
+	}
+
+	for _, r := range c.Resources {
+		switch r.(type) {
+		case *k8s.Cluster, *nomad.NomadCluster:
+			s.log.Warn("The data in the cluster is not included in the snapshot, only volumes are saved", "ref", r.Metadata().ID)
+		}
+	}
+
+	d, err := config.MarshalState(c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(filepath.Join(tmp, stateFile), d, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to write state: %s", err)
+	}
+
+	for _, v := range m.Volumes {
+		s.log.Info("Saving volume", "name", v)
+
+		err = s.client.CopyFromVolume(v, filepath.Join(tmp, volumesDir, v))
+		if err != nil {
+			return nil, fmt.Errorf("unable to save volume '%s': %s", v, err)
+		}
+	}
+
+	data := filepath.Join(utils.WorkspaceDir(utils.CurrentWorkspace()), dataDir)
+	if _, err := os.Stat(data); err == nil {
+		s.log.Info("Saving data folder", "path", data)
+
+		err = cp.Copy(data, filepath.Join(tmp, dataDir))
+		if err != nil {
+			return nil, fmt.Errorf("unable to save data folder: %s", err)
+		}
+	}
+
+	md, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize manifest: %s", err)
+	}
+
+	err = os.WriteFile(filepath.Join(tmp, manifestFile), md, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to write manifest: %s", err)
+	}
+
+	ew, err := config.EncryptWriter(w)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encrypt snapshot: %s", err)
+	}
+
+	err = s.tar.Create(ew, &tar.TarGzOptions{OmitRoot: true, ZipContents: true}, []string{tmp})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create snapshot: %s", err)
+	}
+
+	err = ew.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to encrypt snapshot: %s", err)
+	}
+
+	return m, nil
+}
+
+// Restore restores the volumes, data folder and images from the snapshot
+// read from r and returns the state contained in the snapshot. The resources
+// are not created, the returned state should be applied with the engine.
+// The state lock is held while the files are restored, callers should hold
+// the lock until the state has been applied. An error is returned without
+// changing any files when resources in the active workspace are running.
+func (s *Snapshot) Restore(r io.Reader) (*Manifest, *hclconfig.Config, error) {
+	unlock, err := config.LockState()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	err = checkNotRunning()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmp, err := os.MkdirTemp(utils.JumppadTemp(), "snapshot")
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	dr, err := config.DecryptReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to decrypt snapshot: %s", err)
+	}
+
+	err = s.tar.Extract(dr, true, tmp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to extract snapshot: %s", err)
+	}
+
+	md, err := os.ReadFile(filepath.Join(tmp, manifestFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read manifest, the file is not a jumppad snapshot: %s", err)
+	}
+
+	m := &Manifest{}
+	err = json.Unmarshal(md, m)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read manifest: %s", err)
+	}
+
+	if m.Version > Version {
+		return nil, nil, fmt.Errorf("snapshot version %d is newer than the supported version %d, please update jumppad", m.Version, Version)
+	}
+
+	d, err := os.ReadFile(filepath.Join(tmp, stateFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read state: %s", err)
+	}
+
+	c, err := config.ParseState(d)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := filepath.Join(tmp, dataDir)
+	if _, err := os.Stat(data); err == nil {
+		dst := filepath.Join(utils.WorkspaceDir(utils.CurrentWorkspace()), dataDir)
+		s.log.Info("Restoring data folder", "path", dst)
+
+		err = cp.Copy(data, dst)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to restore data folder: %s", err)
+		}
+	}
+
+	for _, v := range m.Volumes {
+		s.log.Info("Restoring volume", "name", v)
+
+		err = s.client.CopyDirectoryToVolume(v, filepath.Join(tmp, volumesDir, v))
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to restore volume '%s': %s", v, err)
+		}
+	}
+
+	for _, i := range m.Images {
+		s.log.Info("Pulling image", "name", i)
+
+		// private images are pulled with their credentials when the resources
+		// are created, do not fail the restore
+		err = s.client.PullImage(ctypes.Image{Name: i}, false)
+		if err != nil {
+			s.log.Warn("Unable to pull image", "name", i, "error", err)
+		}
+	}
+
+	return m, c, nil
+}
+
+// checkNotRunning returns an error when resources in the active workspace
+// that use volumes are running, the volumes can not be replaced while the
+// resources are using them
+func checkNotRunning() error {
+	c, err := config.LoadState()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("unable to load state: %s", err)
+	}
+
+	running := []string{}
+	for _, r := range c.Resources {
+		if r.GetDisabled() || r.Metadata().Properties[constants.PropertyStatus] != constants.StatusCreated {
+			continue
+		}
+
+		switch r.(type) {
+		case *rcontainer.Container, *rcontainer.Sidecar, *k8s.Cluster, *nomad.NomadCluster, *cache.ImageCache:
+			running = append(running, r.Metadata().ID)
+		}
+	}
+
+	if len(running) > 0 {
+		sort.Strings(running)
+		return fmt.Errorf("resources %s are running, stop the resources with 'jumppad stop' or remove them with 'jumppad down' before restoring a snapshot", strings.Join(running, ", "))
+	}
+
+	return nil
+}
+
+// stateVolumes returns the names of the Docker volumes used by the resources
+// in the state, the images volume is shared by all workspaces and is not
+// included, the images are pulled when the snapshot is restored
+func stateVolumes(c *hclconfig.Config) []string {
+	volumes := map[string]bool{}
+
+	addVolumes := func(vols []rcontainer.Volume) {
+		for _, v := range vols {
+			if v.Type == "volume" {
+				volumes[v.Source] = true
+			}
+		}
+	}
+
+	for _, r := range c.Resources {
+		if r.GetDisabled() {
+			continue
+		}
+
+		switch v := r.(type) {
+		case *rcontainer.Container:
+			addVolumes(v.Volumes)
+		case *rcontainer.Sidecar:
+			addVolumes(v.Volumes)
+		case *k8s.Cluster:
+			addVolumes(v.Volumes)
+		case *nomad.NomadCluster:
+			addVolumes(v.Volumes)
+		}
+	}
+
+	return sortedKeys(volumes)
+}
+
+// stateImages returns the names of the images used by the resources in the
+// state
+func stateImages(c *hclconfig.Config) []string {
+	images := map[string]bool{}
+
+	addImages := func(imgs ...rcontainer.Image) {
+		for _, i := range imgs {
+			if i.Name != "" {
+				images[i.Name] = true
+			}
+		}
+	}
+
+	for _, r := range c.Resources {
+		if r.GetDisabled() {
+			continue
+		}
+
+		switch v := r.(type) {
+		case *rcontainer.Container:
+			addImages(v.Image)
+		case *rcontainer.Sidecar:
+			addImages(v.Image)
+		case *k8s.Cluster:
+			if v.Image != nil {
+				addImages(*v.Image)
+			}
+			addImages(v.CopyImages...)
+		case *nomad.NomadCluster:
+			if v.Image != nil {
+				addImages(*v.Image)
+			}
+			addImages(v.CopyImages...)
+		}
+	}
+
+	return sortedKeys(images)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,199 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmocks "github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/cache"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	config.RegisterResource(container.TypeContainer, &container.Container{}, &container.Provider{})
+	config.RegisterResource(cache.TypeImageCache, &cache.ImageCache{}, &cache.Provider{})
+}
+
+func setupSnapshotTests(t *testing.T, state string) (*Snapshot, *cmocks.ContainerTasks) {
+	testutils.SetupState(t, state)
+
+	ct := &cmocks.ContainerTasks{}
+	ct.On("CopyFromVolume", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		dst := args.String(1)
+		os.MkdirAll(dst, 0755)
+		os.WriteFile(filepath.Join(dst, "volume.txt"), []byte(args.String(0)), 0644)
+	}).Return(nil)
+	ct.On("CopyDirectoryToVolume", mock.Anything, mock.Anything).Return(nil)
+	ct.On("PullImage", mock.Anything, false).Return(nil)
+
+	return New(ct, &tar.TarGz{}, logger.NewTestLogger(t)), ct
+}
+
+func TestSaveAddsStateVolumesAndImagesToManifest(t *testing.T) {
+	s, ct := setupSnapshotTests(t, snapshotState)
+
+	buf := bytes.NewBuffer(nil)
+	m, err := s.Save(buf)
+	require.NoError(t, err)
+
+	require.Equal(t, Version, m.Version)
+	require.Equal(t, []string{"data"}, m.Volumes)
+	require.Equal(t, []string{"consul:1.16", "postgres:15"}, m.Images)
+
+	ct.AssertCalled(t, "CopyFromVolume", "data", mock.Anything)
+
+	// the images volume is shared by all workspaces
+	ct.AssertNotCalled(t, "CopyFromVolume", utils.FQDNVolumeName(utils.ImageVolumeName), mock.Anything)
+}
+
+func TestSaveEncryptsSnapshotWhenStateEncryptionIsEnabled(t *testing.T) {
+	s, _ := setupSnapshotTests(t, snapshotState)
+
+	err := config.EncryptState()
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	_, err = s.Save(buf)
+	require.NoError(t, err)
+
+	// the tarball can not be read without decrypting
+	err = (&tar.TarGz{}).Extract(bytes.NewReader(buf.Bytes()), true, t.TempDir())
+	require.Error(t, err)
+
+	os.Remove(utils.StatePath())
+
+	_, c, err := s.Restore(buf)
+	require.NoError(t, err)
+
+	_, err = c.FindResource("resource.container.db")
+	require.NoError(t, err)
+}
+
+func TestRestoreRestoresSavedSnapshot(t *testing.T) {
+	s, ct := setupSnapshotTests(t, snapshotState)
+
+	data := filepath.Join(utils.WorkspaceDir(utils.CurrentWorkspace()), "data", "db")
+	os.MkdirAll(data, 0755)
+	os.WriteFile(filepath.Join(data, "config.txt"), []byte("hello"), 0644)
+
+	buf := bytes.NewBuffer(nil)
+	_, err := s.Save(buf)
+	require.NoError(t, err)
+
+	// restore to an empty environment
+	os.RemoveAll(data)
+	os.Remove(utils.StatePath())
+
+	volumes := map[string]string{}
+	testutils.RemoveOn(&ct.Mock, "CopyDirectoryToVolume")
+	ct.On("CopyDirectoryToVolume", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		d, _ := os.ReadFile(filepath.Join(args.String(1), "volume.txt"))
+		volumes[args.String(0)] = string(d)
+	}).Return(nil)
+
+	m, c, err := s.Restore(buf)
+	require.NoError(t, err)
+
+	require.Len(t, m.Volumes, 1)
+	require.Equal(t, "data", volumes["data"])
+
+	d, err := os.ReadFile(filepath.Join(data, "config.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(d))
+
+	r, err := c.FindResource("resource.container.db")
+	require.NoError(t, err)
+	require.Equal(t, "postgres:15", r.(*container.Container).Image.Name)
+
+	ct.AssertCalled(t, "PullImage", mock.Anything, false)
+	ct.AssertNumberOfCalls(t, "PullImage", 2)
+}
+
+func TestRestoreWithRunningResourcesReturnsError(t *testing.T) {
+	s, ct := setupSnapshotTests(t, snapshotState)
+
+	buf := bytes.NewBuffer(nil)
+	_, err := s.Save(buf)
+	require.NoError(t, err)
+
+	_, _, err = s.Restore(buf)
+	require.ErrorContains(t, err, "resource.container.consul, resource.container.db, resource.image_cache.default are running")
+
+	ct.AssertNotCalled(t, "CopyDirectoryToVolume", mock.Anything, mock.Anything)
+}
+
+func TestRestoreWithNewerVersionReturnsError(t *testing.T) {
+	s, _ := setupSnapshotTests(t, "")
+
+	dir := t.TempDir()
+	md, _ := json.Marshal(Manifest{Version: Version + 1})
+	os.WriteFile(filepath.Join(dir, manifestFile), md, 0644)
+
+	buf := bytes.NewBuffer(nil)
+	err := (&tar.TarGz{}).Create(buf, &tar.TarGzOptions{OmitRoot: true, ZipContents: true}, []string{dir})
+	require.NoError(t, err)
+
+	_, _, err = s.Restore(buf)
+	require.ErrorContains(t, err, "newer than the supported version")
+}
+
+var snapshotState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "name": "db",
+        "properties": {
+          "status": "created"
+        },
+        "type": "container"
+      },
+      "image": {
+        "name": "postgres:15"
+      },
+      "volumes": [
+        {
+          "source": "data",
+          "destination": "/var/lib/postgresql/data",
+          "type": "volume"
+        },
+        {
+          "source": "/tmp",
+          "destination": "/files"
+        }
+      ]
+  },
+  {
+      "meta": {
+        "name": "consul",
+        "properties": {
+          "status": "created"
+        },
+        "type": "container"
+      },
+      "image": {
+        "name": "consul:1.16"
+      }
+  },
+  {
+      "meta": {
+        "name": "default",
+        "properties": {
+          "status": "created"
+        },
+        "type": "image_cache"
+      }
+  }
+  ]
+}
+`
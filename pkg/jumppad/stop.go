@@ -0,0 +1,130 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/telemetry"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	sdk "github.com/jumppad-labs/plugin-sdk"
+)
+
+// Stop stops the created resources in the state in reverse dependency order,
+// the objects backing the resources are kept so that the environment can be
+// started again without being recreated. Only resources with a provider that
+// implements config.Stopper are stopped, all other resources are unchanged
+func (e *EngineImpl) Stop(ctx context.Context) (err error) {
+	e.log.Info("Stopping resources")
+
+	ctx, span := telemetry.Start(ctx, "stop")
+	defer func() { telemetry.End(span, err) }()
+
+	return e.walkState(ctx, e.stopCallback, true)
+}
+
+// Start starts the stopped resources in the state in dependency order
+func (e *EngineImpl) Start(ctx context.Context) (err error) {
+	e.log.Info("Starting resources")
+
+	ctx, span := telemetry.Start(ctx, "start")
+	defer func() { telemetry.End(span, err) }()
+
+	return e.walkState(ctx, e.startCallback, false)
+}
+
+// walkState calls the callback for each resource in the state and saves the
+// state once all the resources have been processed
+func (e *EngineImpl) walkState(ctx context.Context, callback func(r types.Resource) error, reverse bool) error {
+	defer e.setContext(ctx)()
+	e.targets = nil
+
+	// hold the state lock so the state does not change while the resources
+	// are processed
+	unlock, err := config.LockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	c, err := config.LoadState()
+	if err != nil {
+		return fmt.Errorf("unable to load state: %s", err)
+	}
+
+	e.config = c
+
+	walkErr := c.Walk(callback, reverse)
+
+	// save the status of the resources that were processed before any error
+	err = config.SaveState(c)
+	if err != nil {
+		return fmt.Errorf("unable to save state: %s", err)
+	}
+
+	return walkErr
+}
+
+func (e *EngineImpl) stopCallback(r types.Resource) error {
+	// if the context is cancelled skip
+	if e.ctx.Err() != nil {
+		return nil
+	}
+
+	if r.GetDisabled() || r.Metadata().Properties[constants.PropertyStatus] != constants.StatusCreated {
+		return nil
+	}
+
+	s, ok := e.providers.GetProvider(r).(config.Stopper)
+	if !ok {
+		return nil
+	}
+
+	err := e.runProvider(r, opStop, s.Stop)
+	if err != nil {
+		return fmt.Errorf(`unable to stop resource "%s": %s`, r.Metadata().ID, err)
+	}
+
+	r.Metadata().Properties[constants.PropertyStatus] = constants.StatusStopped
+
+	return nil
+}
+
+func (e *EngineImpl) startCallback(r types.Resource) error {
+	// if the context is cancelled skip
+	if e.ctx.Err() != nil {
+		return nil
+	}
+
+	if r.GetDisabled() || r.Metadata().Properties[constants.PropertyStatus] != constants.StatusStopped {
+		return nil
+	}
+
+	err := e.startResource(r, e.providers.GetProvider(r))
+	if err != nil {
+		return fmt.Errorf(`unable to start resource "%s": %s`, r.Metadata().ID, err)
+	}
+
+	return nil
+}
+
+// startResource starts a stopped resource and marks it as created, when the
+// start fails the resource remains stopped
+func (e *EngineImpl) startResource(r types.Resource, p sdk.Provider) error {
+	s, ok := p.(config.Stopper)
+	if !ok {
+		// there is nothing to start for resources that can not be stopped
+		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+		return nil
+	}
+
+	err := e.runProvider(r, opStart, s.Start)
+	if err != nil {
+		return err
+	}
+
+	r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+
+	return nil
+}
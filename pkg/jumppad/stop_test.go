@@ -0,0 +1,195 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/testutils"
+	sdk "github.com/jumppad-labs/plugin-sdk"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type stopProvider struct {
+	*mocks.Provider
+	name      string
+	providers *stopProviders
+}
+
+func (s *stopProvider) Stop(ctx context.Context) error {
+	return s.providers.call("stop", s.name)
+}
+
+func (s *stopProvider) Start(ctx context.Context) error {
+	return s.providers.call("start", s.name)
+}
+
+// stopProviders returns providers that can be stopped for containers and the
+// image cache and records the order the resources are stopped and started
+type stopProviders struct {
+	*mocks.Providers
+	mutex  sync.Mutex
+	calls  []string
+	errors map[string]error
+}
+
+func (s *stopProviders) GetProvider(r types.Resource) sdk.Provider {
+	p := s.Providers.GetProvider(r)
+
+	switch r.Metadata().Type {
+	case "container", "image_cache":
+		return &stopProvider{p.(*mocks.Provider), r.Metadata().Name, s}
+	}
+
+	return p
+}
+
+func (s *stopProviders) call(op, name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.calls = append(s.calls, op+" "+name)
+
+	return s.errors[op+" "+name]
+}
+
+func setupStopTests(t *testing.T, state string, errors map[string]error) (*EngineImpl, *stopProviders) {
+	testutils.SetupState(t, state)
+
+	sp := &stopProviders{Providers: mocks.NewProviders(nil), errors: errors}
+
+	return &EngineImpl{
+		log:       logger.NewTestLogger(t),
+		providers: sp,
+		events:    events.NewBus(),
+	}, sp
+}
+
+func testResourceStatus(t *testing.T, id string) string {
+	r, err := testLoadState(t).FindResource(id)
+	require.NoError(t, err)
+
+	return r.Metadata().Properties[constants.PropertyStatus].(string)
+}
+
+func TestStopStopsResourcesAndSetsStatus(t *testing.T) {
+	e, sp := setupStopTests(t, existingState, nil)
+
+	err := e.Stop(context.Background())
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"stop container", "stop default"}, sp.calls)
+
+	require.Equal(t, constants.StatusStopped, testResourceStatus(t, "resource.container.container"))
+	require.Equal(t, constants.StatusStopped, testResourceStatus(t, "resource.image_cache.default"))
+
+	// resources that can not be stopped are unchanged
+	require.Equal(t, constants.StatusCreated, testResourceStatus(t, "resource.network.cloud"))
+	require.Equal(t, constants.StatusCreated, testResourceStatus(t, "resource.template.consul_config"))
+}
+
+func TestStopAndStartFollowDependencies(t *testing.T) {
+	e, sp := setupStopTests(t, stopState, nil)
+
+	err := e.Stop(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"stop api", "stop db"}, sp.calls)
+
+	sp.calls = nil
+
+	err = e.Start(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"start db", "start api"}, sp.calls)
+
+	require.Equal(t, constants.StatusCreated, testResourceStatus(t, "resource.container.api"))
+	require.Equal(t, constants.StatusCreated, testResourceStatus(t, "resource.container.db"))
+}
+
+func TestStopWithErrorSavesStatusOfStoppedResources(t *testing.T) {
+	e, _ := setupStopTests(t, stopState, map[string]error{"stop db": fmt.Errorf("boom")})
+
+	err := e.Stop(context.Background())
+	require.ErrorContains(t, err, "boom")
+
+	require.Equal(t, constants.StatusStopped, testResourceStatus(t, "resource.container.api"))
+	require.Equal(t, constants.StatusCreated, testResourceStatus(t, "resource.container.db"))
+}
+
+func TestStartOnlyStartsStoppedResources(t *testing.T) {
+	e, sp := setupStopTests(t, strings.Replace(existingState, `"created"`, `"stopped"`, 1), nil)
+
+	err := e.Start(context.Background())
+	require.NoError(t, err)
+
+	// the network can not be stopped, there is nothing to start
+	require.Empty(t, sp.calls)
+	require.Equal(t, constants.StatusCreated, testResourceStatus(t, "resource.network.cloud"))
+}
+
+func TestStartWithErrorLeavesResourceStopped(t *testing.T) {
+	e, _ := setupStopTests(t, strings.ReplaceAll(stopState, `"created"`, `"stopped"`), map[string]error{"start api": fmt.Errorf("boom")})
+
+	err := e.Start(context.Background())
+	require.ErrorContains(t, err, "boom")
+
+	require.Equal(t, constants.StatusCreated, testResourceStatus(t, "resource.container.db"))
+	require.Equal(t, constants.StatusStopped, testResourceStatus(t, "resource.container.api"))
+}
+
+func TestApplyStartsStoppedResources(t *testing.T) {
+	e, sp := setupStopTests(t, strings.ReplaceAll(singleFileState, `"created"`, `"stopped"`), nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"start default", "start consul"}, sp.calls)
+	require.Equal(t, constants.StatusCreated, testResourceStatus(t, "resource.container.consul"))
+	require.Equal(t, constants.StatusCreated, testResourceStatus(t, "resource.image_cache.default"))
+
+	// stopped resources are started rather than recreated
+	for i, p := range sp.Providers.Providers {
+		if getMetaFromMock(sp.Providers, i).Name == "consul" {
+			p.AssertNotCalled(t, "Create", mock.Anything)
+		}
+	}
+}
+
+var stopState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "name": "db",
+        "properties": {
+          "status": "created"
+        },
+        "type": "container"
+      },
+      "image": {
+        "name": "postgres"
+      }
+  },
+  {
+      "meta": {
+        "name": "api",
+        "properties": {
+          "status": "created"
+        },
+        "type": "container"
+      },
+      "depends_on": ["resource.container.db"],
+      "image": {
+        "name": "api"
+      }
+  }
+  ]
+}
+`
@@ -0,0 +1,126 @@
+package jumppad
+
+import (
+	"fmt"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+)
+
+// resolveTargets returns the ids of the resources named by targets along with
+// the resources they depend on. When dependents is true the resources that
+// depend on the targets are returned instead, this is the set of resources
+// that must be destroyed when the targets are destroyed.
+//
+// A target can be a resource e.g. resource.container.api or a module
+// e.g. module.consul, targeting a module selects all resources in the module
+func resolveTargets(c *hclconfig.Config, targets []string, dependents bool) (map[string]bool, error) {
+	// build the edges of the graph, edges point from a resource to the
+	// resources it depends on, or for dependents the reverse
+	edges := map[string][]string{}
+	for _, r := range c.Resources {
+		for _, d := range resourceDependencies(c, r) {
+			if dependents {
+				edges[d.Metadata().ID] = append(edges[d.Metadata().ID], r.Metadata().ID)
+				continue
+			}
+
+			edges[r.Metadata().ID] = append(edges[r.Metadata().ID], d.Metadata().ID)
+		}
+	}
+
+	selected := map[string]bool{}
+	queue := []string{}
+
+	for _, t := range targets {
+		res, err := findTarget(c, t)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range res {
+			queue = append(queue, r.Metadata().ID)
+		}
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if selected[id] {
+			continue
+		}
+
+		selected[id] = true
+		queue = append(queue, edges[id]...)
+	}
+
+	return selected, nil
+}
+
+func findTarget(c *hclconfig.Config, target string) ([]types.Resource, error) {
+	fqrn, err := resources.ParseFQRN(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target %s: %s", target, err)
+	}
+
+	if fqrn.Type == resources.TypeModule {
+		res, err := c.FindModuleResources(fqrn.String(), true)
+		if err != nil {
+			return nil, fmt.Errorf("target %s not found", target)
+		}
+
+		return res, nil
+	}
+
+	r, err := c.FindResource(fqrn.StringWithoutAttribute())
+	if err != nil {
+		return nil, fmt.Errorf("target %s not found", target)
+	}
+
+	return []types.Resource{r}, nil
+}
+
+// resourceDependencies returns the resources that r depends on, this uses the
+// same rules as the hclconfig graph, explicit dependencies, references to
+// other resources, and the parent module
+func resourceDependencies(c *hclconfig.Config, r types.Resource) []types.Resource {
+	deps := []types.Resource{}
+
+	refs := append([]string{}, r.GetDependencies()...)
+	refs = append(refs, r.Metadata().Links...)
+
+	for _, ref := range refs {
+		fqrn, err := resources.ParseFQRN(ref)
+		if err != nil {
+			continue
+		}
+
+		rel := fqrn.AppendParentModule(r.Metadata().Module)
+
+		if rel.Type == resources.TypeModule {
+			// ignore the error, the module may only contain disabled resources
+			mr, _ := c.FindModuleResources(rel.String(), true)
+			deps = append(deps, mr...)
+
+			if m, err := c.FindResource(rel.String()); err == nil {
+				deps = append(deps, m)
+			}
+
+			continue
+		}
+
+		if d, err := c.FindResource(rel.StringWithoutAttribute()); err == nil {
+			deps = append(deps, d)
+		}
+	}
+
+	if r.Metadata().Module != "" {
+		if m, err := c.FindResource(fmt.Sprintf("module.%s", r.Metadata().Module)); err == nil {
+			deps = append(deps, m)
+		}
+	}
+
+	return deps
+}
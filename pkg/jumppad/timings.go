@@ -0,0 +1,251 @@
+package jumppad
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
+)
+
+// ResourceTiming is the time the providers spent processing a resource
+type ResourceTiming struct {
+	ID       string
+	Type     string
+	Duration time.Duration
+}
+
+// TimingSummary describes where the time was spent when applying a
+// configuration
+type TimingSummary struct {
+	// Slowest contains the resources that took the longest to process,
+	// ordered from slowest
+	Slowest []ResourceTiming
+
+	// CriticalPath is the chain of dependent resources with the longest
+	// total duration, ordered from the first resource processed. Resources
+	// that took no time, such as variables, are not included.
+	CriticalPath []ResourceTiming
+}
+
+// CriticalPathDuration returns the total duration of the critical path
+func (t TimingSummary) CriticalPathDuration() time.Duration {
+	d := time.Duration(0)
+	for _, r := range t.CriticalPath {
+		d += r.Duration
+	}
+
+	return d
+}
+
+// TimingRecorder records the duration of the provider operations published
+// by the engine, Handle should be subscribed to the engine events
+type TimingRecorder struct {
+	mutex   sync.Mutex
+	timings map[string]*ResourceTiming
+}
+
+// NewTimingRecorder creates a new TimingRecorder
+func NewTimingRecorder() *TimingRecorder {
+	return &TimingRecorder{timings: map[string]*ResourceTiming{}}
+}
+
+// Handle records the duration of finished and failed operations, a
+// resource that is retried or replaced has the duration of each operation
+// added together
+func (t *TimingRecorder) Handle(e events.Event) {
+	switch e.Type {
+	case events.ResourceCreateFinished, events.ResourceCreateFailed,
+		events.ResourceRefreshFinished, events.ResourceRefreshFailed,
+		events.ResourceDestroyFinished, events.ResourceDestroyFailed:
+	default:
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	rt, ok := t.timings[e.ResourceID]
+	if !ok {
+		rt = &ResourceTiming{ID: e.ResourceID, Type: e.ResourceType}
+		t.timings[e.ResourceID] = rt
+	}
+
+	rt.Duration += e.Duration()
+}
+
+// Summary returns the n slowest resources and the critical path through the
+// dependency graph of the given config
+func (t *TimingRecorder) Summary(c *hclconfig.Config, n int) TimingSummary {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	s := TimingSummary{}
+
+	for _, rt := range t.timings {
+		if rt.Duration > 0 {
+			s.Slowest = append(s.Slowest, *rt)
+		}
+	}
+
+	sort.Slice(s.Slowest, func(i, j int) bool {
+		if s.Slowest[i].Duration == s.Slowest[j].Duration {
+			return s.Slowest[i].ID < s.Slowest[j].ID
+		}
+
+		return s.Slowest[i].Duration > s.Slowest[j].Duration
+	})
+
+	if len(s.Slowest) > n {
+		s.Slowest = s.Slowest[:n]
+	}
+
+	if c != nil {
+		s.CriticalPath = t.criticalPath(c)
+	}
+
+	return s
+}
+
+// criticalPath finds the path through the dependency graph where the sum
+// of the resource durations is the greatest
+func (t *TimingRecorder) criticalPath(c *hclconfig.Config) []ResourceTiming {
+	deps := dependencyGraph(c)
+
+	// total is the duration of the longest path ending at the resource, prev
+	// is the previous resource on that path
+	total := map[string]time.Duration{}
+	prev := map[string]string{}
+	visiting := map[string]bool{}
+
+	var longest func(id string) time.Duration
+	longest = func(id string) time.Duration {
+		if d, ok := total[id]; ok {
+			return d
+		}
+
+		// the config is a DAG, guard against invalid state
+		if visiting[id] {
+			return 0
+		}
+
+		visiting[id] = true
+		defer delete(visiting, id)
+
+		best := time.Duration(0)
+		for _, d := range deps[id] {
+			if l := longest(d); l > best {
+				best = l
+				prev[id] = d
+			}
+		}
+
+		total[id] = best + t.duration(id)
+
+		return total[id]
+	}
+
+	end := ""
+	for _, id := range sortedKeys(deps) {
+		if l := longest(id); l > 0 && (end == "" || l > total[end]) {
+			end = id
+		}
+	}
+
+	path := []ResourceTiming{}
+	for id := end; id != ""; id = prev[id] {
+		if rt, ok := t.timings[id]; ok && rt.Duration > 0 {
+			path = append([]ResourceTiming{*rt}, path...)
+		}
+	}
+
+	return path
+}
+
+func (t *TimingRecorder) duration(id string) time.Duration {
+	if rt, ok := t.timings[id]; ok {
+		return rt.Duration
+	}
+
+	return 0
+}
+
+// dependencyGraph returns the ids of the resources that each resource in the
+// config depends on, references to attributes are resolved to the resource
+// and a dependency on a module is a dependency on every resource in the
+// module
+func dependencyGraph(c *hclconfig.Config) map[string][]string {
+	graph := map[string][]string{}
+
+	for _, r := range c.Resources {
+		id := r.Metadata().ID
+		graph[id] = []string{}
+
+		refs := append([]string{}, r.GetDependencies()...)
+		refs = append(refs, r.Metadata().Links...)
+
+		seen := map[string]bool{}
+		add := func(d string) {
+			if d != id && !seen[d] {
+				seen[d] = true
+				graph[id] = append(graph[id], d)
+			}
+		}
+
+		// resources in a module depend on the module
+		if r.Metadata().Module != "" {
+			add(moduleID(r.Metadata().Module))
+		}
+
+		for _, ref := range refs {
+			fqrn, err := resources.ParseFQRN(ref)
+			if err != nil {
+				continue
+			}
+
+			abs := fqrn.AppendParentModule(r.Metadata().Module)
+
+			if abs.Type == resources.TypeModule {
+				for _, mr := range moduleResources(c, abs.StringWithoutAttribute()) {
+					add(mr.Metadata().ID)
+				}
+
+				continue
+			}
+
+			add(abs.StringWithoutAttribute())
+		}
+	}
+
+	return graph
+}
+
+// moduleID returns the id of the module resource for the given module path
+func moduleID(module string) string {
+	return resources.FQRN{Type: resources.TypeModule, Resource: module}.String()
+}
+
+// moduleResources returns the resources in the module with the given id
+// including the resources in sub modules
+func moduleResources(c *hclconfig.Config, id string) []types.Resource {
+	rs, err := c.FindModuleResources(id, true)
+	if err != nil {
+		return nil
+	}
+
+	return rs
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,83 @@
+package jumppad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/events"
+	"github.com/stretchr/testify/require"
+)
+
+// testTimingsConfig returns a config where a cluster and an image cache
+// depend on the network, and a helm chart in a module depends on the
+// cluster
+func testTimingsConfig() *hclconfig.Config {
+	c := hclconfig.NewConfig()
+
+	c.AppendResource(&types.ResourceBase{Meta: types.Meta{Name: "main", Type: "network"}})
+	c.AppendResource(&types.ResourceBase{Meta: types.Meta{Name: "dev", Type: "k8s_cluster", Links: []string{"resource.network.main.meta.id"}}})
+	c.AppendResource(&types.ResourceBase{Meta: types.Meta{Name: "cache", Type: "image_cache"}, DependsOn: []string{"resource.network.main"}})
+	c.AppendResource(&resources.Module{ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "apps", Type: resources.TypeModule}, DependsOn: []string{"resource.k8s_cluster.dev"}}})
+	c.AppendResource(&types.ResourceBase{Meta: types.Meta{Name: "vault", Type: "helm", Module: "apps"}})
+
+	return c
+}
+
+func testTimingEvent(id, resourceType string, d time.Duration) events.Event {
+	return events.Event{Type: events.ResourceCreateFinished, ResourceID: id, ResourceType: resourceType, DurationMS: d.Milliseconds()}
+}
+
+func setupTimingsTests() *TimingRecorder {
+	tr := NewTimingRecorder()
+
+	tr.Handle(testTimingEvent("resource.network.main", "network", 1*time.Second))
+	tr.Handle(testTimingEvent("resource.k8s_cluster.dev", "k8s_cluster", 60*time.Second))
+	tr.Handle(testTimingEvent("resource.image_cache.cache", "image_cache", 70*time.Second))
+	tr.Handle(testTimingEvent("module.apps.resource.helm.vault", "helm", 30*time.Second))
+
+	return tr
+}
+
+func TestTimingSummaryReturnsSlowestResources(t *testing.T) {
+	tr := setupTimingsTests()
+
+	s := tr.Summary(testTimingsConfig(), 2)
+
+	require.Len(t, s.Slowest, 2)
+	require.Equal(t, "resource.image_cache.cache", s.Slowest[0].ID)
+	require.Equal(t, 70*time.Second, s.Slowest[0].Duration)
+	require.Equal(t, "resource.k8s_cluster.dev", s.Slowest[1].ID)
+}
+
+func TestTimingSummaryReturnsCriticalPath(t *testing.T) {
+	tr := setupTimingsTests()
+
+	s := tr.Summary(testTimingsConfig(), 5)
+
+	// the image cache is the slowest resource but the cluster and the chart
+	// in the module that depends on it take longer
+	ids := []string{}
+	for _, r := range s.CriticalPath {
+		ids = append(ids, r.ID)
+	}
+
+	require.Equal(t, []string{"resource.network.main", "resource.k8s_cluster.dev", "module.apps.resource.helm.vault"}, ids)
+	require.Equal(t, 91*time.Second, s.CriticalPathDuration())
+}
+
+func TestTimingRecorderAddsDurationsForRetries(t *testing.T) {
+	tr := NewTimingRecorder()
+
+	tr.Handle(events.Event{Type: events.ResourceCreateFailed, ResourceID: "resource.container.db", DurationMS: 1000})
+	tr.Handle(events.Event{Type: events.ResourceCreateRetrying, ResourceID: "resource.container.db"})
+	tr.Handle(events.Event{Type: events.ResourceCreateFinished, ResourceID: "resource.container.db", DurationMS: 2000})
+	tr.Handle(events.Event{Type: events.HealthCheckPassed, ResourceID: "resource.container.db", DurationMS: 5000})
+
+	s := tr.Summary(nil, 5)
+	require.Len(t, s.Slowest, 1)
+	require.Equal(t, 3*time.Second, s.Slowest[0].Duration)
+	require.Empty(t, s.CriticalPath)
+}
@@ -3,8 +3,9 @@ package utils
 import "fmt"
 
 var ErrInvalidBlueprintURI = fmt.Errorf("inavlid blueprint URI")
-var ErrNameExceedsMaxLength = fmt.Errorf("name exceeds the max length of 128 characters")
-var ErrNameContainsInvalidCharacters = fmt.Errorf("name contains invalid characters characters must be either a-z, A-Z, 0-9, -, _")
+var ErrNameExceedsMaxLength = fmt.Errorf("name exceeds the max length of 63 characters")
+var ErrNameContainsInvalidCharacters = fmt.Errorf("name contains invalid characters, characters must be either a-z, 0-9, - and the name must start and end with a-z or 0-9")
+var ErrNameReserved = fmt.Errorf("name is reserved")
 
 // ImageVolumeName is the name of the volume which stores the images for clusters
 const ImageVolumeName string = "images"
@@ -15,8 +16,8 @@ const BuildImagePrefix = "jumppad.dev/localcache"
 // Name of the Cache resource
 const CacheName string = "docker-cache"
 
-// Address of the proxy used for caching docker images
-const jumppadProxyAddress string = "http://default.image-cache.local.jmpd.in:3128"
+// Port of the proxy used for caching docker images
+const jumppadProxyPort int = 3128
 
 // Addresses to bypass when using a HTTP Proxy
 const ProxyBypass string = "localhost,127.0.0.1,cluster.local,jumppad.dev,jumpd.in,svc,consul"
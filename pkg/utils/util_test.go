@@ -84,8 +84,22 @@ func TestValidatesNameAndReturnsErrorWhenInvalid(t *testing.T) {
 	require.False(t, ok)
 }
 
+func TestValidatesNameAndReturnsErrorWhenNotDNSLabel(t *testing.T) {
+	for _, n := range []string{"Nomad", "no_mad", "-nomad", "nomad-", "no.mad"} {
+		ok, err := ValidateName(n)
+		require.ErrorIs(t, err, ErrNameContainsInvalidCharacters, n)
+		require.False(t, ok)
+	}
+}
+
+func TestValidatesNameAndReturnsErrorWhenReserved(t *testing.T) {
+	ok, err := ValidateName("local")
+	require.ErrorIs(t, err, ErrNameReserved)
+	require.False(t, ok)
+}
+
 func TestValidatesNameAndReturnsErrorWhenTooLong(t *testing.T) {
-	dn := strutil.PadLeft("a", 129, 'a')
+	dn := strutil.PadLeft("a", 64, 'a')
 
 	ok, err := ValidateName(dn)
 
@@ -257,7 +271,7 @@ func TestGetLocalIPAndHostnameReturnsCorrectly(t *testing.T) {
 func TestImageCacheAddressReturnsDefaultWhenEnvNotSet(t *testing.T) {
 	proxy := ImageCacheAddress()
 
-	require.Equal(t, jumppadProxyAddress, proxy)
+	require.Equal(t, "http://default.image-cache.local.jmpd.in:3128", proxy)
 }
 
 func TestImageCacheAddressReturnsEnvWhenEnvSet(t *testing.T) {
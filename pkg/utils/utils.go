@@ -59,21 +59,29 @@ func CreateFolders() {
 	os.MkdirAll(ReleasesFolder(), os.FileMode(0755))
 }
 
-// ValidateName ensures that the name for a resource is within certain boundaries
-// Valid characters: [a-z] [A-Z] _ - [0-9]
-// Max length: 128
+// ValidateName ensures that the name is a valid DNS label so that it can be
+// used in paths, domain names and Docker object names.
+// Valid characters: [a-z] - [0-9], the name must start and end with a letter
+// or number
+// Max length: 63
+// Reserved: local
 func ValidateName(name string) (bool, error) {
 	// check the length
-	if len(name) > 128 {
+	if len(name) > 63 {
 		return false, ErrNameExceedsMaxLength
 	}
 
-	r := regexp.MustCompile(`^[a-zA-Z0-9\-_]+$`)
+	r := regexp.MustCompile(`^[a-z0-9]([a-z0-9\-]*[a-z0-9])?$`)
 	ok := r.MatchString(name)
 	if !ok {
 		return false, ErrNameContainsInvalidCharacters
 	}
 
+	// local is the domain used by the default workspace
+	if name == "local" {
+		return false, ErrNameReserved
+	}
+
 	return true, nil
 }
 
@@ -96,9 +104,9 @@ func ReplaceNonURIChars(s string) (string, error) {
 
 // FQDN generates the full qualified name for a container
 func FQDN(name, module, typeName string) string {
-	fqdn := fmt.Sprintf("%s.%s.local.%s", name, typeName, LocalTLD)
+	fqdn := fmt.Sprintf("%s.%s.%s.%s", name, typeName, WorkspaceDomain(), LocalTLD)
 	if module != "" {
-		fqdn = fmt.Sprintf("%s.%s.%s.local.%s", name, module, typeName, LocalTLD)
+		fqdn = fmt.Sprintf("%s.%s.%s.%s.%s", name, module, typeName, WorkspaceDomain(), LocalTLD)
 	}
 
 	// ensure that the name is valid for URI schema
@@ -110,7 +118,8 @@ func FQDN(name, module, typeName string) string {
 	return cleanName
 }
 
-// FQDNVolumeName creates a full qualified volume name
+// FQDNVolumeName creates a full qualified volume name, volumes created in a
+// named workspace include the workspace e.g. images.volume.nomad.jmpd.in
 func FQDNVolumeName(name string) string {
 	// ensure that the name is valid for URI schema
 	cleanName, err := ReplaceNonURIChars(name)
@@ -118,14 +127,18 @@ func FQDNVolumeName(name string) string {
 		panic(err)
 	}
 
-	return fmt.Sprintf("%s.volume.%s", cleanName, LocalTLD)
+	if IsDefaultWorkspace() {
+		return fmt.Sprintf("%s.volume.%s", cleanName, LocalTLD)
+	}
+
+	return fmt.Sprintf("%s.volume.%s.%s", cleanName, CurrentWorkspace(), LocalTLD)
 }
 
 // CreateKubeConfigPath creates the file path for the KubeConfig file when
-// using Kubernetes cluster
+// using Kubernetes cluster, the path is scoped to the active workspace
 func CreateKubeConfigPath(id string) (dir, filePath string, dockerPath string) {
 	id, _ = ReplaceNonURIChars(id)
-	dir = filepath.Join(JumppadHome(), "/config/", id)
+	dir = filepath.Join(WorkspaceDir(CurrentWorkspace()), "/config/", id)
 	filePath = filepath.Join(dir, "/kubeconfig.yaml")
 	dockerPath = filepath.Join(dir, "/kubeconfig-docker.yaml")
 
@@ -171,9 +184,9 @@ func JumppadTemp() string {
 }
 
 // StateDir returns the location of the jumppad
-// state, usually $HOME/.jumppad/state
+// state for the active workspace, usually $HOME/.jumppad/state
 func StateDir() string {
-	return filepath.Join(JumppadHome(), "/state")
+	return filepath.Join(WorkspaceDir(CurrentWorkspace()), "/state")
 }
 
 // PluginsDir returns the location of the plugins
@@ -209,6 +222,36 @@ func StatePath() string {
 	return filepath.Join(StateDir(), "/state.json")
 }
 
+// StateLockPath returns the full path for the lock file which guards
+// concurrent access to the state file
+func StateLockPath() string {
+	return filepath.Join(StateDir(), "/state.lock")
+}
+
+// ResumePath returns the location of the file that records the arguments
+// of a cancelled apply so that it can be resumed
+func ResumePath() string {
+	return filepath.Join(StateDir(), "/resume.json")
+}
+
+// StateKeyPath returns the location of the key used to encrypt sensitive
+// values in the state, the key is shared by all workspaces
+func StateKeyPath() string {
+	return filepath.Join(JumppadHome(), "/state.key")
+}
+
+// SettingsPath returns the location of the settings file which configures
+// the backend used to store the state
+func SettingsPath() string {
+	return filepath.Join(JumppadHome(), "/settings.hcl")
+}
+
+// StateHistoryDir returns the location of the snapshots of previous
+// versions of the state
+func StateHistoryDir() string {
+	return filepath.Join(StateDir(), "/history")
+}
+
 // ImageCacheLog returns the location of the image cache log
 func ImageCacheLog() string {
 	return fmt.Sprintf("%s/images.log", JumppadHome())
@@ -298,8 +341,9 @@ func ReleasesFolder() string {
 }
 
 // DataFolder creates the data directory used by the application
+// the directory is scoped to the active workspace
 func DataFolder(p string, perms os.FileMode) string {
-	data := filepath.Join(JumppadHome(), "data", p)
+	data := filepath.Join(WorkspaceDir(CurrentWorkspace()), "data", p)
 
 	// create the folder if it does not exist
 	os.MkdirAll(data, perms)
@@ -432,7 +476,7 @@ func ImageCacheAddress() string {
 		return p
 	}
 
-	return jumppadProxyAddress
+	return fmt.Sprintf("http://%s:%d", FQDN("default", "", "image-cache"), jumppadProxyPort)
 }
 
 // ImageCacheDisabled returns true if the image cache is disabled
@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultWorkspace is the name of the workspace used when no other workspace
+// has been selected
+const DefaultWorkspace = "default"
+
+// WorkspaceEnvVar is the environment variable that can be used to override
+// the currently selected workspace
+const WorkspaceEnvVar = "JUMPPAD_WORKSPACE"
+
+var ErrWorkspaceNotFound = fmt.Errorf("workspace does not exist")
+var ErrWorkspaceExists = fmt.Errorf("workspace already exists")
+
+// CurrentWorkspace returns the name of the active workspace.
+// The workspace is resolved in the following order:
+// the JUMPPAD_WORKSPACE environment variable, the workspace selected
+// with `jumppad workspace select`, the default workspace.
+// When the workspace name is invalid the default workspace is returned,
+// commands call ResolveWorkspace before any other work so that the error
+// is reported
+func CurrentWorkspace() string {
+	ws, err := ResolveWorkspace()
+	if err != nil {
+		return DefaultWorkspace
+	}
+
+	return ws
+}
+
+// ResolveWorkspace returns the name of the active workspace, an error is
+// returned when the name set in the JUMPPAD_WORKSPACE environment variable
+// or the selected workspace is not valid
+func ResolveWorkspace() (string, error) {
+	if ws := os.Getenv(WorkspaceEnvVar); ws != "" {
+		if _, err := ValidateName(ws); err != nil {
+			return "", fmt.Errorf("invalid workspace '%s' set in %s: %w", ws, WorkspaceEnvVar, err)
+		}
+
+		return ws, nil
+	}
+
+	d, err := os.ReadFile(selectedWorkspaceFile())
+	if err == nil {
+		if ws := strings.TrimSpace(string(d)); ws != "" {
+			if _, err := ValidateName(ws); err != nil {
+				return "", fmt.Errorf("invalid workspace '%s' selected in %s: %w", ws, selectedWorkspaceFile(), err)
+			}
+
+			return ws, nil
+		}
+	}
+
+	return DefaultWorkspace, nil
+}
+
+// IsDefaultWorkspace returns true when the active workspace is the default
+func IsDefaultWorkspace() bool {
+	return CurrentWorkspace() == DefaultWorkspace
+}
+
+// SelectWorkspace persists the given workspace as the active workspace
+// for subsequent commands
+func SelectWorkspace(name string) error {
+	if !WorkspaceExists(name) {
+		return ErrWorkspaceNotFound
+	}
+
+	err := os.MkdirAll(JumppadHome(), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	// selecting the default workspace removes the selection
+	if name == DefaultWorkspace {
+		err := os.Remove(selectedWorkspaceFile())
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	return os.WriteFile(selectedWorkspaceFile(), []byte(name), 0644)
+}
+
+// WorkspacesDir returns the location of the named workspaces,
+// usually $HOME/.jumppad/workspaces
+func WorkspacesDir() string {
+	return filepath.Join(JumppadHome(), "/workspaces")
+}
+
+// WorkspaceDir returns the root folder for the given workspace.
+// The default workspace is rooted at $HOME/.jumppad to remain compatible
+// with state and data created before workspaces existed
+func WorkspaceDir(name string) string {
+	if name == DefaultWorkspace {
+		return JumppadHome()
+	}
+
+	return filepath.Join(WorkspacesDir(), name)
+}
+
+// CreateWorkspace creates the folder structure for a new workspace
+func CreateWorkspace(name string) error {
+	if _, err := ValidateName(name); err != nil {
+		return err
+	}
+
+	if WorkspaceExists(name) {
+		return ErrWorkspaceExists
+	}
+
+	return os.MkdirAll(WorkspaceDir(name), os.ModePerm)
+}
+
+// DeleteWorkspace removes the workspace folder including any state
+// and data
+func DeleteWorkspace(name string) error {
+	if name == DefaultWorkspace {
+		return fmt.Errorf("the default workspace can not be deleted")
+	}
+
+	if !WorkspaceExists(name) {
+		return ErrWorkspaceNotFound
+	}
+
+	return os.RemoveAll(WorkspaceDir(name))
+}
+
+// WorkspaceExists returns true if the given workspace has been created
+func WorkspaceExists(name string) bool {
+	if name == DefaultWorkspace {
+		return true
+	}
+
+	s, err := os.Stat(WorkspaceDir(name))
+	if err != nil {
+		return false
+	}
+
+	return s.IsDir()
+}
+
+// ListWorkspaces returns a sorted list of workspace names, the default
+// workspace is always returned first
+func ListWorkspaces() ([]string, error) {
+	workspaces := []string{}
+
+	entries, err := os.ReadDir(WorkspacesDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != DefaultWorkspace {
+			workspaces = append(workspaces, e.Name())
+		}
+	}
+
+	sort.Strings(workspaces)
+
+	return append([]string{DefaultWorkspace}, workspaces...), nil
+}
+
+// WorkspaceStatePath returns the location of the state file for the
+// given workspace
+func WorkspaceStatePath(name string) string {
+	return filepath.Join(WorkspaceDir(name), "/state", "/state.json")
+}
+
+// WorkspaceDomain returns the domain segment used to namespace the FQDN of
+// resources created in the active workspace, resources in the default
+// workspace use the segment "local" e.g. consul.container.local.jmpd.in
+func WorkspaceDomain() string {
+	if IsDefaultWorkspace() {
+		return "local"
+	}
+
+	return CurrentWorkspace()
+}
+
+// WorkspaceResourceName prefixes the given name with the active workspace,
+// this is used for objects such as networks that share a global namespace.
+// The workspace and the name are separated with a "." which is not valid in
+// workspace names so that the prefixed names are unique.
+// Names in the default workspace are returned unchanged
+func WorkspaceResourceName(name string) string {
+	if IsDefaultWorkspace() {
+		return name
+	}
+
+	return fmt.Sprintf("%s.%s", CurrentWorkspace(), name)
+}
+
+func selectedWorkspaceFile() string {
+	return filepath.Join(JumppadHome(), "/workspace")
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setupWorkspaceTests(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv(HomeEnvName(), home)
+	t.Setenv(WorkspaceEnvVar, "")
+
+	return home
+}
+
+func TestCurrentWorkspaceReturnsDefault(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	require.Equal(t, DefaultWorkspace, CurrentWorkspace())
+	require.True(t, IsDefaultWorkspace())
+}
+
+func TestCurrentWorkspaceReturnsEnvWhenSet(t *testing.T) {
+	setupWorkspaceTests(t)
+	t.Setenv(WorkspaceEnvVar, "nomad")
+
+	require.Equal(t, "nomad", CurrentWorkspace())
+}
+
+func TestSelectWorkspaceReturnsErrorWhenNotExists(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	err := SelectWorkspace("nomad")
+	require.ErrorIs(t, err, ErrWorkspaceNotFound)
+}
+
+func TestSelectWorkspaceSetsCurrent(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	err := CreateWorkspace("nomad")
+	require.NoError(t, err)
+
+	err = SelectWorkspace("nomad")
+	require.NoError(t, err)
+	require.Equal(t, "nomad", CurrentWorkspace())
+
+	err = SelectWorkspace(DefaultWorkspace)
+	require.NoError(t, err)
+	require.Equal(t, DefaultWorkspace, CurrentWorkspace())
+}
+
+func TestCreateWorkspaceReturnsErrorWhenExists(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	err := CreateWorkspace("nomad")
+	require.NoError(t, err)
+
+	err = CreateWorkspace("nomad")
+	require.ErrorIs(t, err, ErrWorkspaceExists)
+}
+
+func TestCreateWorkspaceReturnsErrorWhenInvalidName(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	err := CreateWorkspace("no mad")
+	require.Error(t, err)
+
+	err = CreateWorkspace("local")
+	require.ErrorIs(t, err, ErrNameReserved)
+}
+
+func TestListWorkspacesReturnsDefaultFirst(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	require.NoError(t, CreateWorkspace("nomad"))
+	require.NoError(t, CreateWorkspace("k3s"))
+
+	ws, err := ListWorkspaces()
+	require.NoError(t, err)
+	require.Equal(t, []string{DefaultWorkspace, "k3s", "nomad"}, ws)
+}
+
+func TestDeleteWorkspaceRemovesFolder(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	require.NoError(t, CreateWorkspace("nomad"))
+
+	err := DeleteWorkspace("nomad")
+	require.NoError(t, err)
+	require.False(t, WorkspaceExists("nomad"))
+}
+
+func TestDeleteDefaultWorkspaceReturnsError(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	err := DeleteWorkspace(DefaultWorkspace)
+	require.Error(t, err)
+}
+
+func TestStatePathIsScopedToWorkspace(t *testing.T) {
+	home := setupWorkspaceTests(t)
+
+	require.Equal(t, filepath.Join(home, ".jumppad", "state", "state.json"), StatePath())
+
+	t.Setenv(WorkspaceEnvVar, "nomad")
+	require.Equal(t, filepath.Join(home, ".jumppad", "workspaces", "nomad", "state", "state.json"), StatePath())
+}
+
+func TestDataFolderIsScopedToWorkspace(t *testing.T) {
+	home := setupWorkspaceTests(t)
+	t.Setenv(WorkspaceEnvVar, "nomad")
+
+	df := DataFolder("test", os.ModePerm)
+	require.Equal(t, filepath.Join(home, ".jumppad", "workspaces", "nomad", "data", "test"), df)
+}
+
+func TestFQDNIsScopedToWorkspace(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	require.Equal(t, "test.container.local.jmpd.in", FQDN("test", "", "container"))
+
+	t.Setenv(WorkspaceEnvVar, "nomad")
+	require.Equal(t, "test.container.nomad.jmpd.in", FQDN("test", "", "container"))
+	require.Equal(t, "nomad.onprem", WorkspaceResourceName("onprem"))
+}
+
+func TestVolumeNameIsScopedToWorkspace(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	require.Equal(t, "images.volume.jmpd.in", FQDNVolumeName("images"))
+
+	t.Setenv(WorkspaceEnvVar, "nomad")
+	require.Equal(t, "images.volume.nomad.jmpd.in", FQDNVolumeName("images"))
+}
+
+func TestResolveWorkspaceReturnsErrorWhenEnvInvalid(t *testing.T) {
+	setupWorkspaceTests(t)
+	t.Setenv(WorkspaceEnvVar, "../nomad")
+
+	_, err := ResolveWorkspace()
+	require.ErrorIs(t, err, ErrNameContainsInvalidCharacters)
+	require.Equal(t, DefaultWorkspace, CurrentWorkspace())
+}
+
+func TestResolveWorkspaceReturnsErrorWhenSelectedInvalid(t *testing.T) {
+	setupWorkspaceTests(t)
+
+	os.MkdirAll(JumppadHome(), os.ModePerm)
+	os.WriteFile(selectedWorkspaceFile(), []byte("Nomad"), 0644)
+
+	_, err := ResolveWorkspace()
+	require.ErrorIs(t, err, ErrNameContainsInvalidCharacters)
+}
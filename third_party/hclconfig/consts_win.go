@@ -0,0 +1,5 @@
+//go:build windows
+
+package hclconfig
+
+const LineEnding = "\r\n"